@@ -70,7 +70,7 @@ func (s *accessTokenManagerService) GetAccessToken() (err error, accessToken str
 	accessToken = t.AccessToken
 
 	// 保存token
-	expire := time.Duration(t.Expire) - defaultCleanup - time.Second
+	expire := time.Duration(t.Expire)*time.Second - defaultCleanup - time.Second
 	if expire < 1 {
 		expire = 1
 	}
@@ -14,8 +14,10 @@ func TestAccessTokenManagerService_GetAccessToken(t *testing.T) {
 		mux, server, client := setup(t)
 		defer teardown(server)
 
+		requests := 0
 		mux.HandleFunc("/open-apis/auth/v3/tenant_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
 			testMethod(t, r, http.MethodPost)
+			requests++
 			fmt.Fprint(w, `{
 				"code": 0,
 				"expire": 7200,
@@ -35,5 +37,7 @@ func TestAccessTokenManagerService_GetAccessToken(t *testing.T) {
 		So(err, ShouldBeNil)
 		want = "t-caecc734c2e3328a62489fe0648c4b98779515d3"
 		So(accessToken, ShouldEqual, want)
+		// expire 的单位为秒，有效期内使用缓存的 token
+		So(requests, ShouldEqual, 1)
 	})
 }
@@ -0,0 +1,168 @@
+package alert
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	feishu "github.com/eyotang/go-feishu"
+)
+
+const (
+	defaultMaxAlerts  = 10
+	defaultTimeLayout = "2006-01-02 15:04:05"
+)
+
+// Renderer 把告警渲染为消息卡片。
+type Renderer struct {
+	// 卡片标题颜色，key 为 firing、resolved
+	Templates map[string]string
+	// 一张卡片最多展示的告警数
+	MaxAlerts int
+	// 时间格式和时区
+	TimeLayout string
+	Location   *time.Location
+}
+
+func NewRenderer() *Renderer {
+	return &Renderer{
+		Templates: map[string]string{
+			StatusFiring:   feishu.TemplateRed,
+			StatusResolved: feishu.TemplateGreen,
+		},
+		MaxAlerts:  defaultMaxAlerts,
+		TimeLayout: defaultTimeLayout,
+		Location:   time.Local,
+	}
+}
+
+// Render 生成卡片，firing 和 resolved 使用不同的标题颜色，每条告警展示标签、注解以及来源、静默链接。
+func (r *Renderer) Render(n *Notification) *feishu.BotCardOption {
+	card := &feishu.BotCardOption{
+		Config: feishu.CardConfigOption{WideScreenMode: true, EnableForward: true},
+		Header: feishu.HeadOption{
+			Title:    feishu.TitleOption{Tag: feishu.TextTagPlain, Content: r.title(n)},
+			Template: r.Templates[n.Status],
+		},
+	}
+
+	if len(n.Message) > 0 && n.Source != SourceAlertmanager && n.Source != SourceGrafanaLegacy {
+		card.Elements = append(card.Elements, feishu.NewMarkdownElement(feishu.EscapeMarkdown(n.Message)))
+	}
+
+	max := r.MaxAlerts
+	if max <= 0 || max > len(n.Alerts) {
+		max = len(n.Alerts)
+	}
+	for i, a := range n.Alerts[:max] {
+		if i > 0 || len(card.Elements) > 0 {
+			card.Elements = append(card.Elements, feishu.NewHrElement())
+		}
+		card.Elements = append(card.Elements, r.alertElements(n, &a)...)
+	}
+
+	note := fmt.Sprintf("%s · %s", n.Source, r.formatTime(time.Now()))
+	if len(n.Receiver) > 0 {
+		note = n.Receiver + " · " + note
+	}
+	if more := len(n.Alerts) - max; more > 0 {
+		note = fmt.Sprintf("还有 %d 条告警未展示 · %s", more, note)
+	}
+	card.Elements = append(card.Elements, feishu.NewNoteElement(&feishu.TitleOption{Tag: feishu.TextTagPlain, Content: note}))
+	return card
+}
+
+// RenderApp 生成应用消息使用的卡片。
+func (r *Renderer) RenderApp(n *Notification) *feishu.AppCardOption {
+	return r.Render(n).ToAppCard()
+}
+
+func (r *Renderer) title(n *Notification) string {
+	name := n.AlertName()
+	if n.Status == StatusResolved {
+		return fmt.Sprintf("[RESOLVED] %s", name)
+	}
+	return fmt.Sprintf("[FIRING:%d] %s", n.countStatus(StatusFiring), name)
+}
+
+func (r *Renderer) formatTime(t time.Time) string {
+	if r.Location != nil {
+		t = t.In(r.Location)
+	}
+	return t.Format(r.TimeLayout)
+}
+
+func (r *Renderer) alertElements(n *Notification, a *Alert) []interface{} {
+	var lines []string
+	status := "🔴"
+	if a.Status == StatusResolved {
+		status = "🟢"
+	}
+	summary := a.Annotations["summary"]
+	if len(summary) == 0 {
+		summary = a.Labels["alertname"]
+	}
+	lines = append(lines, fmt.Sprintf("%s **%s**", status, feishu.EscapeMarkdown(summary)))
+	if description := a.Annotations["description"]; len(description) > 0 {
+		lines = append(lines, feishu.EscapeMarkdown(description))
+	}
+	for _, k := range sortedKeys(a.Annotations, "summary", "description", "runbook_url") {
+		lines = append(lines, fmt.Sprintf("**%s**: %s", k, feishu.EscapeMarkdown(a.Annotations[k])))
+	}
+	if len(a.ValueString) > 0 {
+		lines = append(lines, fmt.Sprintf("**value**: %s", feishu.EscapeMarkdown(a.ValueString)))
+	}
+	if !a.StartsAt.IsZero() {
+		lines = append(lines, fmt.Sprintf("**开始时间**: %s", r.formatTime(a.StartsAt)))
+	}
+	if a.Status == StatusResolved && !a.EndsAt.IsZero() {
+		lines = append(lines, fmt.Sprintf("**恢复时间**: %s", r.formatTime(a.EndsAt)))
+	}
+	elements := []interface{}{feishu.NewMarkdownElement(strings.Join(lines, "\n"))}
+
+	var fields []feishu.FieldOption
+	for _, k := range sortedKeys(a.Labels, "alertname") {
+		fields = append(fields, feishu.FieldOption{
+			IsShort: true,
+			Text:    feishu.TitleOption{Tag: feishu.TextTagLarkMd, Content: fmt.Sprintf("**%s**\n%s", k, feishu.EscapeMarkdown(a.Labels[k]))},
+		})
+	}
+	if len(fields) > 0 {
+		elements = append(elements, feishu.NewDivElement(nil, fields...))
+	}
+
+	var buttons []interface{}
+	if len(a.GeneratorURL) > 0 {
+		buttons = append(buttons, feishu.NewLinkButton("查看来源", a.GeneratorURL, "default"))
+	}
+	if runbook := a.Annotations["runbook_url"]; len(runbook) > 0 {
+		buttons = append(buttons, feishu.NewLinkButton("Runbook", runbook, "default"))
+	}
+	if a.Status != StatusResolved {
+		if silence := silenceURL(n, a); len(silence) > 0 {
+			buttons = append(buttons, feishu.NewLinkButton("静默", silence, "danger"))
+		}
+	}
+	if len(a.DashboardURL) > 0 {
+		buttons = append(buttons, feishu.NewLinkButton("Dashboard", a.DashboardURL, "default"))
+	}
+	if len(a.PanelURL) > 0 {
+		buttons = append(buttons, feishu.NewLinkButton("Panel", a.PanelURL, "default"))
+	}
+	if len(buttons) > 0 {
+		elements = append(elements, feishu.NewActionElement(buttons...))
+	}
+	return elements
+}
+
+// silenceURL Grafana 直接提供，Alertmanager 根据 externalURL 和标签生成。
+func silenceURL(n *Notification, a *Alert) string {
+	if len(a.SilenceURL) > 0 {
+		return a.SilenceURL
+	}
+	if n.Source != SourceAlertmanager || len(n.ExternalURL) == 0 || len(a.Labels) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(n.ExternalURL, "/") + "/#/silences/new?filter=" + url.QueryEscape(silenceMatchers(a.Labels))
+}
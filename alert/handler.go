@@ -0,0 +1,243 @@
+package alert
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	feishu "github.com/eyotang/go-feishu"
+	"github.com/pkg/errors"
+)
+
+// 请求体最大 4MB
+const maxBodySize = 4 << 20
+
+// Target 告警发送的目标，BotKey 不为空时通过自定义机器人发送，否则通过应用发送给 ReceiveId。
+type Target struct {
+	BotKey    string
+	BotSecret string
+
+	ReceiveIdType string
+	ReceiveId     string
+}
+
+// Route 路由规则，Receiver、Match、MatchRE 都满足时发送到 Targets，Continue 为 false 时不再匹配后续规则。
+// Match、MatchRE 匹配告警的 commonLabels，Receiver 为空时匹配任意 receiver。
+type Route struct {
+	Receiver string
+	Match    map[string]string
+	MatchRE  map[string]string
+	Targets  []Target
+	Continue bool
+
+	matchRE map[string]*regexp.Regexp
+}
+
+func (r *Route) compile() error {
+	r.matchRE = make(map[string]*regexp.Regexp, len(r.MatchRE))
+	for k, expr := range r.MatchRE {
+		// 和 Alertmanager 一样完整匹配
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return errors.Wrapf(err, "route match_re %s", k)
+		}
+		r.matchRE[k] = re
+	}
+	return nil
+}
+
+func (r *Route) match(n *Notification) bool {
+	if len(r.Receiver) > 0 && r.Receiver != n.Receiver {
+		return false
+	}
+	for k, v := range r.Match {
+		if n.CommonLabels[k] != v {
+			return false
+		}
+	}
+	for k, re := range r.matchRE {
+		if !re.MatchString(n.CommonLabels[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Handler 接收 Alertmanager 和 Grafana 的 webhook，渲染为卡片后按路由发送。
+type Handler struct {
+	client         *feishu.Client
+	routes         []*Route
+	defaultTargets []Target
+	renderer       *Renderer
+
+	// ErrorHandler 目标发送失败时调用，默认输出到标准日志
+	ErrorHandler func(Target, error)
+}
+
+type HandlerOptionFunc func(h *Handler)
+
+// WithDefaultTargets 没有匹配的路由时发送的目标。
+func WithDefaultTargets(targets ...Target) HandlerOptionFunc {
+	return func(h *Handler) {
+		h.defaultTargets = targets
+	}
+}
+
+// WithTemplates 设置 firing、resolved 的卡片标题颜色，如 feishu.TemplateRed。
+func WithTemplates(firing, resolved string) HandlerOptionFunc {
+	return func(h *Handler) {
+		h.renderer.Templates[StatusFiring] = firing
+		h.renderer.Templates[StatusResolved] = resolved
+	}
+}
+
+// WithMaxAlerts 一张卡片最多展示的告警数，默认 10。
+func WithMaxAlerts(max int) HandlerOptionFunc {
+	return func(h *Handler) {
+		h.renderer.MaxAlerts = max
+	}
+}
+
+// WithErrorHandler 目标发送失败时调用，用于记录部分目标失败的情况。
+func WithErrorHandler(fn func(Target, error)) HandlerOptionFunc {
+	return func(h *Handler) {
+		h.ErrorHandler = fn
+	}
+}
+
+// WithRenderer 自定义卡片渲染。
+func WithRenderer(renderer *Renderer) HandlerOptionFunc {
+	return func(h *Handler) {
+		h.renderer = renderer
+	}
+}
+
+func NewHandler(client *feishu.Client, routes []Route, options ...HandlerOptionFunc) (*Handler, error) {
+	h := &Handler{client: client, renderer: NewRenderer(), ErrorHandler: logSendError}
+	for i := range routes {
+		r := routes[i]
+		if err := r.compile(); err != nil {
+			return nil, err
+		}
+		h.routes = append(h.routes, &r)
+	}
+	for _, fn := range options {
+		if fn != nil {
+			fn(h)
+		}
+	}
+	return h, nil
+}
+
+// Targets 返回告警匹配的发送目标。
+func (h *Handler) Targets(n *Notification) []Target {
+	var targets []Target
+	for _, r := range h.routes {
+		if !r.match(n) {
+			continue
+		}
+		targets = append(targets, r.Targets...)
+		if !r.Continue {
+			return targets
+		}
+	}
+	if len(targets) == 0 {
+		targets = h.defaultTargets
+	}
+	return targets
+}
+
+func logSendError(_ Target, err error) {
+	log.Printf("alert: %v", err)
+}
+
+// ServeHTTP 只有所有目标都发送失败时返回 502，由 Alertmanager 重试；
+// 部分目标失败时返回 200，避免重试时向已经成功的目标重复发送，失败的目标通过 ErrorHandler 记录。
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n, err := Parse(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.Notify(n); err != nil {
+		if e, ok := err.(*NotifyError); ok && len(e.Errors) < e.Total {
+			fmt.Fprintf(w, "partial: %d of %d targets failed", len(e.Errors), e.Total)
+			return
+		}
+		// 返回 5xx，Alertmanager 会重试
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
+
+// NotifyError 部分或全部目标发送失败，Total 为目标数。
+type NotifyError struct {
+	Total  int
+	Errors []error
+}
+
+func (e *NotifyError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Notify 发送到所有匹配的目标，有目标失败时返回 *NotifyError。
+func (h *Handler) Notify(n *Notification) error {
+	targets := h.Targets(n)
+	e := &NotifyError{Total: len(targets)}
+	for _, t := range targets {
+		if err := h.send(t, n); err != nil {
+			e.Errors = append(e.Errors, err)
+			if h.ErrorHandler != nil {
+				h.ErrorHandler(t, err)
+			}
+		}
+	}
+	if len(e.Errors) > 0 {
+		return e
+	}
+	return nil
+}
+
+func (h *Handler) send(t Target, n *Notification) error {
+	var (
+		rsp *feishu.ErrorMessage
+		err error
+		to  string
+	)
+	if len(t.BotKey) > 0 {
+		to = "bot " + t.BotKey
+		opt := &feishu.BotCardMessageOption{MsgType: feishu.MsgTypeInteractive, Card: *h.renderer.Render(n)}
+		rsp, _, err = h.client.Bot.SendBotCardMessage(t.BotKey, t.BotSecret, opt)
+	} else {
+		to = t.ReceiveIdType + " " + t.ReceiveId
+		opt := &feishu.AppCardMessageOption{MsgType: feishu.MsgTypeInteractive, ReceiveID: t.ReceiveId, Card: *h.renderer.RenderApp(n)}
+		rsp, _, err = h.client.App.SendAppCardMessage(t.ReceiveIdType, opt)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "send alert to %s", to)
+	}
+	if rsp.Code != 0 {
+		return errors.Errorf("send alert to %s: %d %s", to, rsp.Code, rsp.Message)
+	}
+	return nil
+}
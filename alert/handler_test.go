@@ -0,0 +1,231 @@
+package alert
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	feishu "github.com/eyotang/go-feishu"
+	"github.com/eyotang/go-feishu/feishutest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	opsBotKey = "891105b7-1234-4567-7890-c4c372235090"
+	dbaChatId = "oc_a0553eda9014c201e6969b478895c230"
+)
+
+const alertmanagerPayload = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"HighCPU\"}",
+  "status": "firing",
+  "receiver": "ops",
+  "groupLabels": {"alertname": "HighCPU"},
+  "commonLabels": {"alertname": "HighCPU", "severity": "critical", "team": "ops"},
+  "commonAnnotations": {"summary": "CPU usage is high"},
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighCPU", "severity": "critical", "team": "ops", "instance": "web-1"},
+      "annotations": {"summary": "CPU usage is high", "description": "web-1 cpu > 90%"},
+      "startsAt": "2022-05-01T10:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph?g0.expr=cpu",
+      "fingerprint": "a1"
+    },
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighCPU", "severity": "critical", "team": "ops", "instance": "web-2"},
+      "annotations": {"summary": "CPU usage is high"},
+      "startsAt": "2022-05-01T10:01:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph?g0.expr=cpu",
+      "fingerprint": "a2"
+    }
+  ]
+}`
+
+const grafanaPayload = `{
+  "receiver": "feishu",
+  "status": "resolved",
+  "orgId": 1,
+  "alerts": [
+    {
+      "status": "resolved",
+      "labels": {"alertname": "SlowQuery", "team": "dba"},
+      "annotations": {"summary": "slow queries"},
+      "startsAt": "2022-05-01T10:00:00Z",
+      "endsAt": "2022-05-01T10:30:00Z",
+      "generatorURL": "http://grafana:3000/alerting/grafana/x/view",
+      "fingerprint": "b1",
+      "silenceURL": "http://grafana:3000/alerting/silence/new",
+      "dashboardURL": "http://grafana:3000/d/db",
+      "valueString": "[ var='B' value=3 ]"
+    }
+  ],
+  "groupLabels": {"alertname": "SlowQuery"},
+  "commonLabels": {"alertname": "SlowQuery", "team": "dba"},
+  "commonAnnotations": {},
+  "externalURL": "http://grafana:3000/",
+  "version": "1",
+  "title": "[RESOLVED] SlowQuery",
+  "state": "ok",
+  "message": "Resolved: SlowQuery"
+}`
+
+const legacyGrafanaPayload = `{
+  "title": "[Alerting] Disk usage",
+  "ruleId": 1,
+  "ruleName": "Disk usage",
+  "ruleUrl": "http://grafana:3000/d/disk",
+  "state": "alerting",
+  "message": "disk almost full",
+  "evalMatches": [{"metric": "sda", "value": 95.5, "tags": {"host": "db-1"}}]
+}`
+
+func TestParse(t *testing.T) {
+	Convey("test Parse", t, func() {
+		n, err := Parse([]byte(alertmanagerPayload))
+		So(err, ShouldBeNil)
+		So(n.Source, ShouldEqual, SourceAlertmanager)
+		So(n.AlertName(), ShouldEqual, "HighCPU")
+		So(n.Alerts, ShouldHaveLength, 2)
+
+		n, err = Parse([]byte(grafanaPayload))
+		So(err, ShouldBeNil)
+		So(n.Source, ShouldEqual, SourceGrafana)
+		So(n.Status, ShouldEqual, StatusResolved)
+		So(n.Alerts[0].DashboardURL, ShouldEqual, "http://grafana:3000/d/db")
+
+		n, err = Parse([]byte(legacyGrafanaPayload))
+		So(err, ShouldBeNil)
+		So(n.Source, ShouldEqual, SourceGrafanaLegacy)
+		So(n.Status, ShouldEqual, StatusFiring)
+		So(n.AlertName(), ShouldEqual, "Disk usage")
+		So(n.Alerts[0].Labels["host"], ShouldEqual, "db-1")
+		So(n.Alerts[0].ValueString, ShouldEqual, "95.5")
+
+		_, err = Parse([]byte("not json"))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestRenderer_Render(t *testing.T) {
+	Convey("test Renderer_Render", t, func() {
+		n, _ := Parse([]byte(alertmanagerPayload))
+		r := NewRenderer()
+		card := r.Render(n)
+		So(card.Header.Title.Content, ShouldEqual, "[FIRING:2] HighCPU")
+		So(card.Header.Template, ShouldEqual, feishu.TemplateRed)
+
+		// 告警、分割线、告警、备注
+		var actions []*feishu.ActionElement
+		for _, e := range card.Elements {
+			if a, ok := e.(*feishu.ActionElement); ok {
+				actions = append(actions, a)
+			}
+		}
+		So(actions, ShouldHaveLength, 2)
+		silence := actions[0].Actions[1].(*feishu.ButtonElement)
+		So(silence.URL, ShouldStartWith, "http://alertmanager:9093/#/silences/new?filter=")
+		So(silence.URL, ShouldContainSubstring, "instance%3D%22web-1%22")
+
+		r.MaxAlerts = 1
+		card = r.Render(n)
+		note := card.Elements[len(card.Elements)-1].(*feishu.NoteElement)
+		So(note.Elements[0].(*feishu.TitleOption).Content, ShouldStartWith, "还有 1 条告警未展示")
+
+		n, _ = Parse([]byte(grafanaPayload))
+		card = NewRenderer().Render(n)
+		So(card.Header.Title.Content, ShouldEqual, "[RESOLVED] SlowQuery")
+		So(card.Header.Template, ShouldEqual, feishu.TemplateGreen)
+	})
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	Convey("test Handler_ServeHTTP", t, func() {
+		srv := feishutest.NewServer()
+		defer srv.Close()
+		srv.AddApp("cli_alerttest", "secret")
+		srv.AddWebhookBot(opsBotKey, feishutest.BotConfig{Secret: "bot-secret"})
+
+		client, err := feishu.NewLocalCacheClient("cli_alerttest", "secret", feishu.WithBaseURL(srv.URL))
+		So(err, ShouldBeNil)
+
+		h, err := NewHandler(client, []Route{
+			{Match: map[string]string{"team": "ops"}, Targets: []Target{{BotKey: opsBotKey, BotSecret: "bot-secret"}}},
+			{MatchRE: map[string]string{"team": "db.*"}, Targets: []Target{{ReceiveIdType: "chat_id", ReceiveId: dbaChatId}}},
+		})
+		So(err, ShouldBeNil)
+
+		post := func(body string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/alert", strings.NewReader(body)))
+			return w
+		}
+
+		Convey("alertmanager to webhook bot", func() {
+			w := post(alertmanagerPayload)
+			So(w.Code, ShouldEqual, http.StatusOK)
+			srv.AssertWebhookMessage(t, opsBotKey, "[FIRING:2] HighCPU")
+			So(srv.AppMessages(dbaChatId), ShouldBeEmpty)
+		})
+
+		Convey("grafana to app chat", func() {
+			w := post(grafanaPayload)
+			So(w.Code, ShouldEqual, http.StatusOK)
+			m := srv.AssertAppMessage(t, dbaChatId, "[RESOLVED] SlowQuery")
+			So(m.MsgType, ShouldEqual, feishu.MsgTypeInteractive)
+			So(srv.WebhookMessages(opsBotKey), ShouldBeEmpty)
+		})
+
+		Convey("send failed", func() {
+			srv.InjectFault("bot/v2/hook/"+opsBotKey, feishutest.Fault{Code: feishu.WebhookCodeSignMismatch, Msg: "sign match fail"})
+			w := post(alertmanagerPayload)
+			So(w.Code, ShouldEqual, http.StatusBadGateway)
+		})
+
+		Convey("bad request", func() {
+			So(post("{").Code, ShouldEqual, http.StatusBadRequest)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/alert", nil))
+			So(w.Code, ShouldEqual, http.StatusMethodNotAllowed)
+		})
+	})
+
+	Convey("test Handler_ServeHTTP partial failure", t, func() {
+		srv := feishutest.NewServer()
+		defer srv.Close()
+		srv.AddApp("cli_alerttest_partial", "secret")
+		srv.AddWebhookBot(opsBotKey, feishutest.BotConfig{Secret: "bot-secret"})
+		client, err := feishu.NewLocalCacheClient("cli_alerttest_partial", "secret", feishu.WithBaseURL(srv.URL))
+		So(err, ShouldBeNil)
+
+		var failed []Target
+		h, err := NewHandler(client, []Route{
+			{Match: map[string]string{"team": "ops"}, Targets: []Target{
+				{BotKey: opsBotKey, BotSecret: "bot-secret"},
+				{ReceiveIdType: "chat_id", ReceiveId: dbaChatId},
+			}},
+		}, WithErrorHandler(func(t Target, err error) {
+			failed = append(failed, t)
+		}))
+		So(err, ShouldBeNil)
+
+		// 部分目标失败时返回 200，避免 Alertmanager 重试时重复发送到成功的目标
+		srv.InjectFault("bot/v2/hook/"+opsBotKey, feishutest.Fault{Code: feishu.WebhookCodeSignMismatch, Msg: "sign match fail"})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/alert", strings.NewReader(alertmanagerPayload)))
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Body.String(), ShouldContainSubstring, "1 of 2 targets failed")
+		So(failed, ShouldResemble, []Target{{BotKey: opsBotKey, BotSecret: "bot-secret"}})
+		srv.AssertAppMessage(t, dbaChatId, "[FIRING:2] HighCPU")
+	})
+
+	Convey("test NewHandler invalid match_re", t, func() {
+		_, err := NewHandler(nil, []Route{{MatchRE: map[string]string{"team": "("}}})
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,214 @@
+// Package alert 把 Prometheus Alertmanager 和 Grafana 的 webhook 告警转换为飞书消息卡片，
+// 按路由规则通过自定义机器人或应用发送。
+package alert
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+
+	SourceAlertmanager  = "alertmanager"
+	SourceGrafana       = "grafana"
+	SourceGrafanaLegacy = "grafana_legacy"
+)
+
+// Alert 一条告警。
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+
+	// Grafana unified alerting 附加的字段
+	SilenceURL   string `json:"silenceURL,omitempty"`
+	DashboardURL string `json:"dashboardURL,omitempty"`
+	PanelURL     string `json:"panelURL,omitempty"`
+	ValueString  string `json:"valueString,omitempty"`
+}
+
+// Notification Alertmanager webhook 的内容，Grafana unified alerting 兼容此格式并增加了 title、message。
+type Notification struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []Alert           `json:"alerts"`
+
+	// Grafana
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message,omitempty"`
+	State   string `json:"state,omitempty"`
+
+	// 来源，解析时设置
+	Source string `json:"-"`
+}
+
+// legacyGrafana Grafana 旧版告警的 webhook 内容。
+type legacyGrafana struct {
+	Title       string `json:"title"`
+	RuleId      int64  `json:"ruleId"`
+	RuleName    string `json:"ruleName"`
+	RuleURL     string `json:"ruleUrl"`
+	State       string `json:"state"`
+	Message     string `json:"message"`
+	ImageURL    string `json:"imageUrl"`
+	EvalMatches []struct {
+		Metric string            `json:"metric"`
+		Value  float64           `json:"value"`
+		Tags   map[string]string `json:"tags"`
+	} `json:"evalMatches"`
+	Tags map[string]string `json:"tags"`
+}
+
+// Parse 解析 Alertmanager、Grafana unified alerting 和 Grafana 旧版告警的 webhook 内容。
+func Parse(body []byte) (*Notification, error) {
+	var probe struct {
+		Alerts   json.RawMessage `json:"alerts"`
+		RuleName string          `json:"ruleName"`
+		OrgId    *int64          `json:"orgId"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return nil, err
+	}
+
+	if len(probe.Alerts) == 0 && len(probe.RuleName) > 0 {
+		return parseLegacyGrafana(body)
+	}
+
+	n := new(Notification)
+	if err := json.Unmarshal(body, n); err != nil {
+		return nil, err
+	}
+	n.Source = SourceAlertmanager
+	if probe.OrgId != nil || len(n.Title) > 0 {
+		n.Source = SourceGrafana
+	}
+	if len(n.Status) == 0 {
+		n.Status = StatusFiring
+		if len(n.Alerts) > 0 && n.countStatus(StatusFiring) == 0 {
+			n.Status = StatusResolved
+		}
+	}
+	return n, nil
+}
+
+func parseLegacyGrafana(body []byte) (*Notification, error) {
+	g := new(legacyGrafana)
+	if err := json.Unmarshal(body, g); err != nil {
+		return nil, err
+	}
+
+	status := StatusFiring
+	if g.State == "ok" {
+		status = StatusResolved
+	}
+	labels := map[string]string{"alertname": g.RuleName}
+	for k, v := range g.Tags {
+		labels[k] = v
+	}
+	n := &Notification{
+		Status:            status,
+		Title:             g.Title,
+		Message:           g.Message,
+		State:             g.State,
+		GroupLabels:       map[string]string{"alertname": g.RuleName},
+		CommonLabels:      labels,
+		CommonAnnotations: map[string]string{"description": g.Message},
+		Source:            SourceGrafanaLegacy,
+	}
+	for _, m := range g.EvalMatches {
+		alertLabels := map[string]string{"metric": m.Metric}
+		for k, v := range labels {
+			alertLabels[k] = v
+		}
+		for k, v := range m.Tags {
+			alertLabels[k] = v
+		}
+		n.Alerts = append(n.Alerts, Alert{
+			Status:       status,
+			Labels:       alertLabels,
+			GeneratorURL: g.RuleURL,
+			ValueString:  formatFloat(m.Value),
+		})
+	}
+	if len(n.Alerts) == 0 {
+		n.Alerts = append(n.Alerts, Alert{
+			Status:       status,
+			Labels:       labels,
+			Annotations:  map[string]string{"description": g.Message},
+			GeneratorURL: g.RuleURL,
+		})
+	}
+	return n, nil
+}
+
+func formatFloat(v float64) string {
+	buf, _ := json.Marshal(v)
+	return string(buf)
+}
+
+// AlertName 告警名称。
+func (n *Notification) AlertName() string {
+	for _, labels := range []map[string]string{n.GroupLabels, n.CommonLabels} {
+		if name := labels["alertname"]; len(name) > 0 {
+			return name
+		}
+	}
+	if len(n.Alerts) > 0 {
+		if name := n.Alerts[0].Labels["alertname"]; len(name) > 0 {
+			return name
+		}
+	}
+	return n.Title
+}
+
+func (n *Notification) countStatus(status string) int {
+	count := 0
+	for _, a := range n.Alerts {
+		if a.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// sortedKeys 返回排序后的 key，排除 exclude。
+func sortedKeys(m map[string]string, exclude ...string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		skip := false
+		for _, e := range exclude {
+			if k == e {
+				skip = true
+			}
+		}
+		if !skip {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// silenceMatchers Alertmanager 新建静默页面的 filter，如 {alertname="HighCPU",instance="a"}。
+func silenceMatchers(labels map[string]string) string {
+	var matchers []string
+	for _, k := range sortedKeys(labels) {
+		v := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(labels[k])
+		matchers = append(matchers, k+`="`+v+`"`)
+	}
+	return "{" + strings.Join(matchers, ",") + "}"
+}
@@ -0,0 +1,99 @@
+package feishu
+
+import (
+	"net/http"
+)
+
+// EphemeralCardOptions 发送仅特定用户可见的卡片，OpenId、UserId、Email 任选其一。
+// 只支持卡片消息，机器人需要在群中。
+type EphemeralCardOptions struct {
+	ChatId  string        `json:"chat_id"`
+	OpenId  string        `json:"open_id,omitempty"`
+	UserId  string        `json:"user_id,omitempty"`
+	Email   string        `json:"email,omitempty"`
+	MsgType string        `json:"msg_type"`
+	Card    AppCardOption `json:"card"`
+}
+
+type EphemeralResponse struct {
+	CodeMsg
+	Data struct {
+		MessageId string `json:"message_id"`
+	} `json:"data"`
+}
+
+type DeleteEphemeralOptions struct {
+	MessageId string `json:"message_id"`
+}
+
+// DelayUpdateCard 延迟更新的卡片，OpenIds 为空时更新所有人看到的卡片，否则只更新这些用户的（独享卡片）。
+type DelayUpdateCard struct {
+	AppCardOption
+	OpenIds []string `json:"open_ids,omitempty"`
+}
+
+type DelayUpdateCardOptions struct {
+	Token string          `json:"token"`
+	Card  DelayUpdateCard `json:"card"`
+}
+
+// SendEphemeralCard 在群中发送仅指定用户可见的卡片。
+func (s *AppService) SendEphemeralCard(opt *EphemeralCardOptions, options ...RequestOptionFunc) (*EphemeralResponse, *Response, error) {
+	u := "ephemeral/v1/send"
+	if opt != nil && len(opt.MsgType) == 0 {
+		opt.MsgType = MsgTypeInteractive
+	}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(EphemeralResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteEphemeralCard 删除仅指定用户可见的卡片。
+func (s *AppService) DeleteEphemeralCard(messageId string, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := "ephemeral/v1/delete"
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, &DeleteEphemeralOptions{MessageId: messageId}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DelayUpdateCard 卡片交互回调后延迟更新卡片，token 为回调中的 token，有效期 30 分钟，最多更新 2 次。
+func (s *AppService) DelayUpdateCard(token string, card *DelayUpdateCard, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := "interactive/v1/card/update"
+	opt := &DelayUpdateCardOptions{Token: token}
+	if card != nil {
+		opt.Card = *card
+	}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
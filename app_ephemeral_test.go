@@ -0,0 +1,70 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAppService_EphemeralCard(t *testing.T) {
+	Convey("test AppService_EphemeralCard", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/ephemeral/v1/send", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"chat_id":"oc_a0553eda9014c201e6969b478895c230","open_id":"ou_6yf8o46d1a4f2c1baf0f6a5b1c5c0c60","msg_type":"interactive","card":{"config":{"wide_screen_mode":true,"enable_forward":false},"header":{"title":{"tag":"plain_text","content":"confirm deploy"},"template":"orange"},"elements":[]}}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "ok", "data": {"message_id": "om_fb1ad8c15a4a0c2ef3cd5b5b5a1e8c21"}}`)
+		})
+		mux.HandleFunc("/open-apis/ephemeral/v1/delete", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"message_id":"om_fb1ad8c15a4a0c2ef3cd5b5b5a1e8c21"}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "ok"}`)
+		})
+
+		opt := &EphemeralCardOptions{
+			ChatId: "oc_a0553eda9014c201e6969b478895c230",
+			OpenId: "ou_6yf8o46d1a4f2c1baf0f6a5b1c5c0c60",
+			Card: AppCardOption{
+				Config:   CardConfigOption{WideScreenMode: true},
+				Header:   HeadOption{Title: TitleOption{Tag: TextTagPlain, Content: "confirm deploy"}, Template: TemplateOrange},
+				Elements: []interface{}{},
+			},
+		}
+		rsp, _, err := client.App.SendEphemeralCard(opt)
+		So(err, ShouldBeNil)
+		So(rsp.Data.MessageId, ShouldEqual, "om_fb1ad8c15a4a0c2ef3cd5b5b5a1e8c21")
+
+		deleted, _, err := client.App.DeleteEphemeralCard(rsp.Data.MessageId)
+		So(err, ShouldBeNil)
+		So(deleted.Code, ShouldEqual, 0)
+	})
+}
+
+func TestAppService_DelayUpdateCard(t *testing.T) {
+	Convey("test AppService_DelayUpdateCard", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/interactive/v1/card/update", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"token":"c-295ee57216a5dc9de90fefd0aadb4b1d7d5c3","card":{"config":{"wide_screen_mode":true,"enable_forward":false},"header":{"title":{"tag":"plain_text","content":"deployed"},"template":"green"},"elements":null,"open_ids":["ou_6yf8o46d1a4f2c1baf0f6a5b1c5c0c60"]}}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "ok"}`)
+		})
+
+		card := &DelayUpdateCard{
+			AppCardOption: AppCardOption{
+				Config: CardConfigOption{WideScreenMode: true},
+				Header: HeadOption{Title: TitleOption{Tag: TextTagPlain, Content: "deployed"}, Template: TemplateGreen},
+			},
+			OpenIds: []string{"ou_6yf8o46d1a4f2c1baf0f6a5b1c5c0c60"},
+		}
+		rsp, _, err := client.App.DelayUpdateCard("c-295ee57216a5dc9de90fefd0aadb4b1d7d5c3", card)
+		So(err, ShouldBeNil)
+		So(rsp.Code, ShouldEqual, 0)
+	})
+}
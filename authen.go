@@ -0,0 +1,144 @@
+package feishu
+
+import (
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+const (
+	grantTypeAuthorizationCode = "authorization_code"
+	grantTypeRefreshToken      = "refresh_token"
+)
+
+type AuthorizeURLOptions struct {
+	RedirectURI string `url:"redirect_uri"`
+	AppId       string `url:"app_id"`
+	State       string `url:"state,omitempty"`
+}
+
+// AuthorizeURL 生成网页登录授权地址，用户登录后飞书会带上 code 和 state 重定向到 redirect_uri。
+// opt.AppId 为空时使用 WithTenantAccessTokenInternal 配置的 appId。
+func (s *AuthService) AuthorizeURL(opt *AuthorizeURLOptions) (string, error) {
+	o := *opt
+	if len(o.AppId) == 0 {
+		o.AppId = s.client.appId
+	}
+	q, err := query.Values(&o)
+	if err != nil {
+		return "", err
+	}
+
+	u := s.client.BaseURL()
+	u.Path += "authen/v1/index"
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+type GetUserAccessTokenOptions struct {
+	GrantType string `json:"grant_type"`
+	Code      string `json:"code"`
+}
+
+type RefreshUserAccessTokenOptions struct {
+	GrantType    string `json:"grant_type"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type UserInfo struct {
+	Name            string `json:"name"`
+	EnName          string `json:"en_name"`
+	AvatarURL       string `json:"avatar_url"`
+	AvatarThumb     string `json:"avatar_thumb"`
+	AvatarMiddle    string `json:"avatar_middle"`
+	AvatarBig       string `json:"avatar_big"`
+	OpenId          string `json:"open_id"`
+	UnionId         string `json:"union_id"`
+	UserId          string `json:"user_id"`
+	Email           string `json:"email"`
+	EnterpriseEmail string `json:"enterprise_email"`
+	Mobile          string `json:"mobile"`
+	TenantKey       string `json:"tenant_key"`
+	EmployeeNo      string `json:"employee_no"`
+}
+
+type UserAccessTokenData struct {
+	UserInfo
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
+	Sid              string `json:"sid"`
+}
+
+type UserAccessToken struct {
+	CodeMsg
+	Data UserAccessTokenData `json:"data"`
+}
+
+// GetUserAccessToken 使用登录回调中的 code 换取 user_access_token，需要 app_access_token。
+func (s *AuthService) GetUserAccessToken(code string, options ...RequestOptionFunc) (*UserAccessToken, *Response, error) {
+	u := "authen/v1/access_token"
+	opt := &GetUserAccessTokenOptions{
+		GrantType: grantTypeAuthorizationCode,
+		Code:      code,
+	}
+
+	req, err := s.client.NewAppServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(UserAccessToken)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+func (s *AuthService) RefreshUserAccessToken(refreshToken string, options ...RequestOptionFunc) (*UserAccessToken, *Response, error) {
+	u := "authen/v1/refresh_access_token"
+	opt := &RefreshUserAccessTokenOptions{
+		GrantType:    grantTypeRefreshToken,
+		RefreshToken: refreshToken,
+	}
+
+	req, err := s.client.NewAppServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(UserAccessToken)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+type UserInfoResponse struct {
+	CodeMsg
+	Data UserInfo `json:"data"`
+}
+
+// GetUserInfo 获取登录用户信息，需要使用 WithToken(userAccessToken) 或 Client.WithUserAccessToken(userID)。
+func (s *AuthService) GetUserInfo(options ...RequestOptionFunc) (*UserInfoResponse, *Response, error) {
+	u := "authen/v1/user_info"
+
+	req, err := s.client.NewRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(UserInfoResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
@@ -0,0 +1,135 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func mockAppAccessToken(t *testing.T, mux *http.ServeMux) {
+	mux.HandleFunc("/open-apis/auth/v3/app_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		fmt.Fprint(w, `{
+			"code": 0,
+			"msg": "ok",
+			"app_access_token": "a-6U1SbDiM6XIH2DcTCPyeub",
+			"expire": 7200
+		}`)
+	})
+}
+
+func TestAuthService_AuthorizeURL(t *testing.T) {
+	Convey("test AuthService_AuthorizeURL", t, func() {
+		client, err := NewLocalCacheClient(appId, appSecret)
+		So(err, ShouldBeNil)
+
+		u, err := client.Auth.AuthorizeURL(&AuthorizeURLOptions{
+			RedirectURI: "https://example.com/callback",
+			State:       "xyz",
+		})
+		So(err, ShouldBeNil)
+		want := "https://open.feishu.cn/open-apis/authen/v1/index?app_id=" + appId + "&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&state=xyz"
+		So(u, ShouldEqual, want)
+	})
+}
+
+func TestAuthService_GetUserAccessToken(t *testing.T) {
+	Convey("test AuthService_GetUserAccessToken", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockAppAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/authen/v1/access_token", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"grant_type":"authorization_code","code":"Cl0qhw"}`)
+			if got := r.Header.Get("Authorization"); got != "Bearer a-6U1SbDiM6XIH2DcTCPyeub" {
+				t.Errorf("Authorization: %s", got)
+			}
+			fmt.Fprint(w, `{
+				"code": 0,
+				"msg": "success",
+				"data": {
+					"access_token": "u-Q7JWnaIM_kRChuLfreHmpArjOEayt",
+					"token_type": "Bearer",
+					"expires_in": 7140,
+					"name": "zhangsan",
+					"open_id": "ou_caecc734c2e3328a62489fe0648c4b98779515d3",
+					"union_id": "on_d89jhk00a5e82x8e2f8c3a1e71a2dc5f",
+					"refresh_expires_in": 2591940,
+					"refresh_token": "ur-t9HHgRCjMqGqIU9v05Zhos"
+				}
+			}`)
+		})
+
+		token, _, err := client.Auth.GetUserAccessToken("Cl0qhw")
+		So(err, ShouldBeNil)
+		So(token.Code, ShouldEqual, 0)
+		So(token.Data.AccessToken, ShouldEqual, "u-Q7JWnaIM_kRChuLfreHmpArjOEayt")
+		So(token.Data.OpenId, ShouldEqual, "ou_caecc734c2e3328a62489fe0648c4b98779515d3")
+		So(token.Data.RefreshToken, ShouldEqual, "ur-t9HHgRCjMqGqIU9v05Zhos")
+	})
+}
+
+func TestUserAccessTokenStore_GetUserAccessToken(t *testing.T) {
+	Convey("test UserAccessTokenStore_GetUserAccessToken", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockAppAccessToken(t, mux)
+
+		refreshed := 0
+		mux.HandleFunc("/open-apis/authen/v1/refresh_access_token", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"grant_type":"refresh_token","refresh_token":"ur-old"}`)
+			refreshed++
+			fmt.Fprint(w, `{
+				"code": 0,
+				"msg": "success",
+				"data": {
+					"access_token": "u-new",
+					"expires_in": 7140,
+					"refresh_expires_in": 2591940,
+					"refresh_token": "ur-new"
+				}
+			}`)
+		})
+		mux.HandleFunc("/open-apis/authen/v1/user_info", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			if got := r.Header.Get("Authorization"); got != "Bearer u-new" {
+				t.Errorf("Authorization: %s", got)
+			}
+			fmt.Fprint(w, `{
+				"code": 0,
+				"msg": "success",
+				"data": {
+					"name": "zhangsan",
+					"open_id": "ou_store_test",
+					"email": "zhangsan@a.com"
+				}
+			}`)
+		})
+
+		userID := "ou_store_test"
+		_, err := client.UserAccessTokens.GetUserAccessToken(userID)
+		So(err, ShouldEqual, ErrUserAccessTokenNotFound)
+
+		// 即将过期的 token 会自动刷新
+		err = client.UserAccessTokens.Set(userID, &UserAccessTokenData{
+			AccessToken:      "u-old",
+			ExpiresIn:        60,
+			RefreshToken:     "ur-old",
+			RefreshExpiresIn: 2591940,
+		})
+		So(err, ShouldBeNil)
+
+		info, _, err := client.Auth.GetUserInfo(client.WithUserAccessToken(userID))
+		So(err, ShouldBeNil)
+		So(info.Data.Email, ShouldEqual, "zhangsan@a.com")
+
+		token, err := client.UserAccessTokens.GetUserAccessToken(userID)
+		So(err, ShouldBeNil)
+		So(token, ShouldEqual, "u-new")
+		So(refreshed, ShouldEqual, 1)
+	})
+}
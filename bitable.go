@@ -0,0 +1,423 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+type BitableService struct {
+	client *Client
+}
+
+// 字段类型
+const (
+	BitableFieldText         = 1
+	BitableFieldNumber       = 2
+	BitableFieldSingleSelect = 3
+	BitableFieldMultiSelect  = 4
+	BitableFieldDateTime     = 5
+	BitableFieldCheckbox     = 7
+	BitableFieldUser         = 11
+	BitableFieldPhoneNumber  = 13
+	BitableFieldUrl          = 15
+	BitableFieldAttachment   = 17
+	BitableFieldSingleLink   = 18
+	BitableFieldLookup       = 19
+	BitableFieldFormula      = 20
+	BitableFieldDuplexLink   = 21
+	BitableFieldCreatedTime  = 1001
+	BitableFieldModifiedTime = 1002
+	BitableFieldCreatedUser  = 1003
+	BitableFieldModifiedUser = 1004
+	BitableFieldAutoNumber   = 1005
+)
+
+// 视图类型
+const (
+	BitableViewGrid    = "grid"
+	BitableViewKanban  = "kanban"
+	BitableViewGallery = "gallery"
+	BitableViewGantt   = "gantt"
+	BitableViewForm    = "form"
+)
+
+type BitableApp struct {
+	AppToken   string `json:"app_token"`
+	Name       string `json:"name"`
+	Revision   int    `json:"revision"`
+	IsAdvanced bool   `json:"is_advanced"`
+}
+
+type BitableTable struct {
+	TableId  string `json:"table_id"`
+	Revision int    `json:"revision"`
+	Name     string `json:"name"`
+}
+
+type BitableView struct {
+	ViewId   string `json:"view_id,omitempty"`
+	ViewName string `json:"view_name"`
+	ViewType string `json:"view_type,omitempty"`
+}
+
+// BitableField Property 按字段类型设置，如单选、多选的选项，数字的格式，关联的数据表。
+type BitableField struct {
+	FieldId     string                `json:"field_id,omitempty"`
+	FieldName   string                `json:"field_name"`
+	Type        int                   `json:"type"`
+	Property    *BitableFieldProperty `json:"property,omitempty"`
+	IsPrimary   bool                  `json:"is_primary,omitempty"`
+	Description *BitableFieldDesc     `json:"description,omitempty"`
+}
+
+type BitableFieldProperty struct {
+	Options       []BitableFieldOption `json:"options,omitempty"`
+	Formatter     string               `json:"formatter,omitempty"`
+	DateFormatter string               `json:"date_formatter,omitempty"`
+	AutoFill      bool                 `json:"auto_fill,omitempty"`
+	Multiple      *bool                `json:"multiple,omitempty"`
+	TableId       string               `json:"table_id,omitempty"`
+	TableName     string               `json:"table_name,omitempty"`
+	BackFieldName string               `json:"back_field_name,omitempty"`
+}
+
+type BitableFieldOption struct {
+	Id    string `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Color int    `json:"color,omitempty"`
+}
+
+type BitableFieldDesc struct {
+	Text string `json:"text"`
+}
+
+type BitableAppResponse struct {
+	CodeMsg
+	Data struct {
+		App BitableApp `json:"app"`
+	} `json:"data"`
+}
+
+type BitablePageOptions struct {
+	PageSize  int    `url:"page_size,omitempty"`
+	PageToken string `url:"page_token,omitempty"`
+}
+
+type ListBitableTablesResponse struct {
+	CodeMsg
+	Data struct {
+		Items     []BitableTable `json:"items"`
+		PageToken string         `json:"page_token"`
+		HasMore   bool           `json:"has_more"`
+		Total     int            `json:"total"`
+	} `json:"data"`
+}
+
+// CreateBitableTableOptions Fields 为空时只有默认的字段，第一个字段为索引字段。
+type CreateBitableTableOptions struct {
+	Name            string         `json:"name"`
+	DefaultViewName string         `json:"default_view_name,omitempty"`
+	Fields          []BitableField `json:"fields,omitempty"`
+}
+
+type CreateBitableTableResponse struct {
+	CodeMsg
+	Data struct {
+		TableId       string   `json:"table_id"`
+		DefaultViewId string   `json:"default_view_id"`
+		FieldIdList   []string `json:"field_id_list"`
+	} `json:"data"`
+}
+
+type ListBitableViewsResponse struct {
+	CodeMsg
+	Data struct {
+		Items     []BitableView `json:"items"`
+		PageToken string        `json:"page_token"`
+		HasMore   bool          `json:"has_more"`
+		Total     int           `json:"total"`
+	} `json:"data"`
+}
+
+type BitableViewResponse struct {
+	CodeMsg
+	Data struct {
+		View BitableView `json:"view"`
+	} `json:"data"`
+}
+
+type ListBitableFieldsOptions struct {
+	ViewId    string `url:"view_id,omitempty"`
+	PageSize  int    `url:"page_size,omitempty"`
+	PageToken string `url:"page_token,omitempty"`
+}
+
+type ListBitableFieldsResponse struct {
+	CodeMsg
+	Data struct {
+		Items     []BitableField `json:"items"`
+		PageToken string         `json:"page_token"`
+		HasMore   bool           `json:"has_more"`
+		Total     int            `json:"total"`
+	} `json:"data"`
+}
+
+type BitableFieldResponse struct {
+	CodeMsg
+	Data struct {
+		Field BitableField `json:"field"`
+	} `json:"data"`
+}
+
+type DeleteBitableFieldResponse struct {
+	CodeMsg
+	Data struct {
+		FieldId string `json:"field_id"`
+		Deleted bool   `json:"deleted"`
+	} `json:"data"`
+}
+
+// GetApp 查询多维表格的名称和版本。
+func (s *BitableService) GetApp(appToken string, options ...RequestOptionFunc) (*BitableAppResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s", appToken)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BitableAppResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListTables 查询多维表格中的数据表。
+func (s *BitableService) ListTables(appToken string, opt *BitablePageOptions, options ...RequestOptionFunc) (*ListBitableTablesResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables", appToken)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListBitableTablesResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AllTables 翻页查询所有数据表。
+func (s *BitableService) AllTables(appToken string, options ...RequestOptionFunc) ([]BitableTable, error) {
+	opt := &BitablePageOptions{PageSize: 100}
+
+	var tables []BitableTable
+	for {
+		rsp, _, err := s.ListTables(appToken, opt, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("list tables of %s: %d %s", appToken, rsp.Code, rsp.Message)
+		}
+		tables = append(tables, rsp.Data.Items...)
+		if !rsp.Data.HasMore || len(rsp.Data.PageToken) == 0 {
+			return tables, nil
+		}
+		opt.PageToken = rsp.Data.PageToken
+	}
+}
+
+// CreateTable 创建数据表。
+func (s *BitableService) CreateTable(appToken string, opt *CreateBitableTableOptions, options ...RequestOptionFunc) (*CreateBitableTableResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables", appToken)
+	body := struct {
+		Table *CreateBitableTableOptions `json:"table"`
+	}{opt}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, &body, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(CreateBitableTableResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteTable 删除数据表。
+func (s *BitableService) DeleteTable(appToken, tableId string, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s", appToken, tableId)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListViews 查询数据表的视图。
+func (s *BitableService) ListViews(appToken, tableId string, opt *BitablePageOptions, options ...RequestOptionFunc) (*ListBitableViewsResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/views", appToken, tableId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListBitableViewsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// CreateView 创建视图，ViewType 为空时是表格视图。
+func (s *BitableService) CreateView(appToken, tableId string, view *BitableView, options ...RequestOptionFunc) (*BitableViewResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/views", appToken, tableId)
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, view, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BitableViewResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteView 删除视图。
+func (s *BitableService) DeleteView(appToken, tableId, viewId string, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/views/%s", appToken, tableId, viewId)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListFields 查询数据表的字段，ViewId 不为空时按视图中的顺序返回。
+func (s *BitableService) ListFields(appToken, tableId string, opt *ListBitableFieldsOptions, options ...RequestOptionFunc) (*ListBitableFieldsResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/fields", appToken, tableId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListBitableFieldsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AllFields 翻页查询数据表的所有字段。
+func (s *BitableService) AllFields(appToken, tableId string, options ...RequestOptionFunc) ([]BitableField, error) {
+	opt := &ListBitableFieldsOptions{PageSize: 100}
+
+	var fields []BitableField
+	for {
+		rsp, _, err := s.ListFields(appToken, tableId, opt, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("list fields of %s: %d %s", tableId, rsp.Code, rsp.Message)
+		}
+		fields = append(fields, rsp.Data.Items...)
+		if !rsp.Data.HasMore || len(rsp.Data.PageToken) == 0 {
+			return fields, nil
+		}
+		opt.PageToken = rsp.Data.PageToken
+	}
+}
+
+// CreateField 创建字段。
+func (s *BitableService) CreateField(appToken, tableId string, field *BitableField, options ...RequestOptionFunc) (*BitableFieldResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/fields", appToken, tableId)
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, field, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BitableFieldResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UpdateField 更新字段的名称、类型和属性。
+func (s *BitableService) UpdateField(appToken, tableId, fieldId string, field *BitableField, options ...RequestOptionFunc) (*BitableFieldResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/fields/%s", appToken, tableId, fieldId)
+
+	req, err := s.client.NewServerRequest(http.MethodPut, u, field, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BitableFieldResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteField 删除字段，索引字段不能删除。
+func (s *BitableService) DeleteField(appToken, tableId, fieldId string, options ...RequestOptionFunc) (*DeleteBitableFieldResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/fields/%s", appToken, tableId, fieldId)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(DeleteBitableFieldResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
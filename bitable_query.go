@@ -0,0 +1,222 @@
+package feishu
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BitableCond 筛选条件，通过 BitableEq、BitableAnd 等构造，转换为多维表格的筛选公式。
+type BitableCond struct {
+	op    string
+	field string
+	value interface{}
+	conds []BitableCond
+}
+
+// BitableEq 字段等于 value，value 可以是字符串、数字、布尔值和 time.Time。
+func BitableEq(field string, value interface{}) BitableCond {
+	return BitableCond{op: "=", field: field, value: value}
+}
+
+func BitableNe(field string, value interface{}) BitableCond {
+	return BitableCond{op: "!=", field: field, value: value}
+}
+
+func BitableGt(field string, value interface{}) BitableCond {
+	return BitableCond{op: ">", field: field, value: value}
+}
+
+func BitableGe(field string, value interface{}) BitableCond {
+	return BitableCond{op: ">=", field: field, value: value}
+}
+
+func BitableLt(field string, value interface{}) BitableCond {
+	return BitableCond{op: "<", field: field, value: value}
+}
+
+func BitableLe(field string, value interface{}) BitableCond {
+	return BitableCond{op: "<=", field: field, value: value}
+}
+
+// BitableContains 文本包含 value，多选、人员包含选项或人员名称。
+func BitableContains(field, value string) BitableCond {
+	return BitableCond{op: "contains", field: field, value: value}
+}
+
+// BitableIn 字段等于任意一个值。
+func BitableIn(field string, values ...interface{}) BitableCond {
+	conds := make([]BitableCond, 0, len(values))
+	for _, v := range values {
+		conds = append(conds, BitableEq(field, v))
+	}
+	return BitableOr(conds...)
+}
+
+func BitableIsEmpty(field string) BitableCond {
+	return BitableCond{op: "=", field: field, value: ""}
+}
+
+func BitableNotEmpty(field string) BitableCond {
+	return BitableCond{op: "!=", field: field, value: ""}
+}
+
+// BitableAnd 所有条件都满足，没有条件时总是满足。
+func BitableAnd(conds ...BitableCond) BitableCond {
+	return BitableCond{op: "AND", conds: conds}
+}
+
+// BitableOr 满足任意一个条件，没有条件时总是不满足。
+func BitableOr(conds ...BitableCond) BitableCond {
+	return BitableCond{op: "OR", conds: conds}
+}
+
+func BitableNot(cond BitableCond) BitableCond {
+	return BitableCond{op: "NOT", conds: []BitableCond{cond}}
+}
+
+// String 筛选公式，如 AND(CurrentValue.[状态]="待处理",CurrentValue.[工时]>2)。
+func (c BitableCond) String() string {
+	return c.formula(nil)
+}
+
+// formula resolve 把条件中的字段名转换为多维表格的字段名称，可以为空。
+func (c BitableCond) formula(resolve func(string) string) string {
+	switch c.op {
+	case "":
+		return ""
+	case "AND", "OR", "NOT":
+		parts := make([]string, 0, len(c.conds))
+		for _, cond := range c.conds {
+			if f := cond.formula(resolve); len(f) > 0 {
+				parts = append(parts, f)
+			}
+		}
+		switch {
+		case len(parts) == 0 && c.op == "OR":
+			return "FALSE()"
+		case len(parts) == 0:
+			return ""
+		case len(parts) == 1 && c.op != "NOT":
+			return parts[0]
+		}
+		return c.op + "(" + strings.Join(parts, ",") + ")"
+	}
+
+	name := c.field
+	if resolve != nil {
+		name = resolve(name)
+	}
+	field := "CurrentValue.[" + name + "]"
+	if c.op == "contains" {
+		return field + ".contains(" + bitableLiteral(c.value) + ")"
+	}
+	return field + c.op + bitableLiteral(c.value)
+}
+
+// bitableLiteral 公式中的常量，字符串加引号，时间转换为 TODATE。
+func bitableLiteral(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return `""`
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		if v {
+			return "TRUE()"
+		}
+		return "FALSE()"
+	case time.Time:
+		return `TODATE("` + v.Format("2006-01-02 15:04:05") + `")`
+	case fmt.Stringer:
+		return strconv.Quote(v.String())
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return `""`
+		}
+		return bitableLiteral(rv.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	case reflect.String:
+		return strconv.Quote(rv.String())
+	case reflect.Bool:
+		return bitableLiteral(rv.Bool())
+	}
+	return strconv.Quote(fmt.Sprint(v))
+}
+
+// BitableQuery 查询条件、排序和数量，字段可以是多维表格的字段名称，也可以是结构体的字段名。
+type BitableQuery struct {
+	cond   BitableCond
+	sort   []bitableSort
+	viewId string
+	limit  int
+}
+
+type bitableSort struct {
+	field string
+	desc  bool
+}
+
+func NewBitableQuery() *BitableQuery {
+	return &BitableQuery{}
+}
+
+// Where 增加条件，多个条件都需要满足。
+func (q *BitableQuery) Where(conds ...BitableCond) *BitableQuery {
+	if len(q.cond.op) > 0 {
+		conds = append([]BitableCond{q.cond}, conds...)
+	}
+	q.cond = BitableAnd(conds...)
+	return q
+}
+
+// OrderBy 按字段排序，可以多次调用。
+func (q *BitableQuery) OrderBy(field string, desc bool) *BitableQuery {
+	q.sort = append(q.sort, bitableSort{field: field, desc: desc})
+	return q
+}
+
+// View 只查询视图中的记录。
+func (q *BitableQuery) View(viewId string) *BitableQuery {
+	q.viewId = viewId
+	return q
+}
+
+// Limit 最多返回 n 条记录，0 为不限制。
+func (q *BitableQuery) Limit(n int) *BitableQuery {
+	q.limit = n
+	return q
+}
+
+// Filter 筛选公式。
+func (q *BitableQuery) Filter() string {
+	return q.cond.formula(nil)
+}
+
+// options 转换为查询参数，resolve 可以为空。
+func (q *BitableQuery) options(resolve func(string) string) *ListRecordsOptions {
+	opt := &ListRecordsOptions{ViewId: q.viewId, Filter: q.cond.formula(resolve)}
+	for _, s := range q.sort {
+		name := s.field
+		if resolve != nil {
+			name = resolve(name)
+		}
+		if s.desc {
+			opt.Sort = append(opt.Sort, name+" DESC")
+		} else {
+			opt.Sort = append(opt.Sort, name+" ASC")
+		}
+	}
+	return opt
+}
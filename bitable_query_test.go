@@ -0,0 +1,43 @@
+package feishu
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBitableQuery(t *testing.T) {
+	Convey("test BitableQuery", t, func() {
+		So(BitableEq("状态", "待处理").String(), ShouldEqual, `CurrentValue.[状态]="待处理"`)
+		So(BitableGe("工时", 2.5).String(), ShouldEqual, `CurrentValue.[工时]>=2.5`)
+		So(BitableEq("已关闭", true).String(), ShouldEqual, `CurrentValue.[已关闭]=TRUE()`)
+		So(BitableLt("发现时间", time.Date(2021, 6, 1, 0, 0, 0, 0, time.Local)).String(), ShouldEqual, `CurrentValue.[发现时间]<TODATE("2021-06-01 00:00:00")`)
+		So(BitableContains("标题", `"登录"`).String(), ShouldEqual, `CurrentValue.[标题].contains("\"登录\"")`)
+		So(BitableNotEmpty("负责人").String(), ShouldEqual, `CurrentValue.[负责人]!=""`)
+		So(BitableIn("编号", "B1", "B2").String(), ShouldEqual, `OR(CurrentValue.[编号]="B1",CurrentValue.[编号]="B2")`)
+		So(BitableIn("编号").String(), ShouldEqual, "FALSE()")
+		So(BitableAnd(BitableEq("优先级", "P0")).String(), ShouldEqual, `CurrentValue.[优先级]="P0"`)
+		So(BitableNot(BitableIsEmpty("标题")).String(), ShouldEqual, `NOT(CurrentValue.[标题]="")`)
+
+		q := NewBitableQuery().
+			Where(BitableEq("Status", "待处理")).
+			Where(BitableOr(BitableEq("Priority", "P0"), BitableGt("Hours", 8))).
+			OrderBy("Priority", false).
+			OrderBy("创建时间", true).
+			View("vew1")
+		So(q.Filter(), ShouldEqual, `AND(CurrentValue.[Status]="待处理",OR(CurrentValue.[Priority]="P0",CurrentValue.[Hours]>8))`)
+
+		names := map[string]string{"Status": "状态", "Priority": "优先级", "Hours": "工时"}
+		opt := q.options(func(name string) string {
+			if n, ok := names[name]; ok {
+				return n
+			}
+			return name
+		})
+		So(opt.Filter, ShouldEqual, `AND(CurrentValue.[状态]="待处理",OR(CurrentValue.[优先级]="P0",CurrentValue.[工时]>8))`)
+		So(opt.Sort, ShouldResemble, BitableNames{"优先级 ASC", "创建时间 DESC"})
+		So(opt.ViewId, ShouldEqual, "vew1")
+		So(NewBitableQuery().Filter(), ShouldEqual, "")
+	})
+}
@@ -0,0 +1,298 @@
+package feishu
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// 批量操作记录时每次请求的最大记录数
+var bitableBatchSize = 500
+
+// BitableNames 以 JSON 数组作为查询参数，如 ["优先级 DESC","创建时间 ASC"]。
+type BitableNames []string
+
+func (n BitableNames) EncodeValues(key string, v *url.Values) error {
+	data, err := json.Marshal([]string(n))
+	if err != nil {
+		return err
+	}
+	v.Set(key, string(data))
+	return nil
+}
+
+// ListRecordsOptions Filter 为筛选公式，如 AND(CurrentValue.[状态]="待处理",CurrentValue.[优先级]="P0")；
+// Sort 如 "优先级 DESC"；FieldNames 为返回的字段，为空时返回所有字段；PageSize 最大 500。
+type ListRecordsOptions struct {
+	ViewId           string       `url:"view_id,omitempty"`
+	Filter           string       `url:"filter,omitempty"`
+	Sort             BitableNames `url:"sort,omitempty"`
+	FieldNames       BitableNames `url:"field_names,omitempty"`
+	TextFieldAsArray bool         `url:"text_field_as_array,omitempty"`
+	AutomaticFields  bool         `url:"automatic_fields,omitempty"`
+	UserIdType       string       `url:"user_id_type,omitempty"`
+	PageSize         int          `url:"page_size,omitempty"`
+	PageToken        string       `url:"page_token,omitempty"`
+}
+
+type ListRecordsResponse struct {
+	CodeMsg
+	Data struct {
+		Items     []BitableRecord `json:"items"`
+		PageToken string          `json:"page_token"`
+		HasMore   bool            `json:"has_more"`
+		Total     int             `json:"total"`
+	} `json:"data"`
+}
+
+// BitableRecordQueryOptions ClientToken 用于幂等创建，为 uuid 格式。批量操作分多次请求时每次使用由 ClientToken 派生的不同 token。
+type BitableRecordQueryOptions struct {
+	UserIdType  string `url:"user_id_type,omitempty"`
+	ClientToken string `url:"client_token,omitempty"`
+}
+
+type RecordResponse struct {
+	CodeMsg
+	Data struct {
+		Record BitableRecord `json:"record"`
+	} `json:"data"`
+}
+
+type BatchRecordsResponse struct {
+	CodeMsg
+	Data struct {
+		Records []BitableRecord `json:"records"`
+	} `json:"data"`
+}
+
+type DeletedRecord struct {
+	RecordId string `json:"record_id"`
+	Deleted  bool   `json:"deleted"`
+}
+
+type DeleteRecordResponse struct {
+	CodeMsg
+	Data DeletedRecord `json:"data"`
+}
+
+type BatchDeleteRecordsResponse struct {
+	CodeMsg
+	Data struct {
+		Records []DeletedRecord `json:"records"`
+	} `json:"data"`
+}
+
+// ListRecords 查询记录，一次一页。
+func (s *BitableService) ListRecords(appToken, tableId string, opt *ListRecordsOptions, options ...RequestOptionFunc) (*ListRecordsResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/records", appToken, tableId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListRecordsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AllRecords 翻页查询所有满足条件的记录，opt 可以为空。
+func (s *BitableService) AllRecords(appToken, tableId string, opt *ListRecordsOptions, options ...RequestOptionFunc) ([]BitableRecord, error) {
+	query := ListRecordsOptions{PageSize: bitableBatchSize}
+	if opt != nil {
+		query = *opt
+		if query.PageSize == 0 {
+			query.PageSize = bitableBatchSize
+		}
+	}
+
+	var records []BitableRecord
+	for {
+		rsp, _, err := s.ListRecords(appToken, tableId, &query, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("list records of %s: %d %s", tableId, rsp.Code, rsp.Message)
+		}
+		records = append(records, rsp.Data.Items...)
+		if !rsp.Data.HasMore || len(rsp.Data.PageToken) == 0 {
+			return records, nil
+		}
+		query.PageToken = rsp.Data.PageToken
+	}
+}
+
+// GetRecord 查询记录。
+func (s *BitableService) GetRecord(appToken, tableId, recordId string, query *BitableRecordQueryOptions, options ...RequestOptionFunc) (*RecordResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/records/%s", appToken, tableId, recordId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, query, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(RecordResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// CreateRecord 创建记录。
+func (s *BitableService) CreateRecord(appToken, tableId string, fields BitableFields, query *BitableRecordQueryOptions, options ...RequestOptionFunc) (*RecordResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/records", appToken, tableId)
+	options = append(options, WithQuery(query))
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, &BitableRecord{Fields: fields}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(RecordResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UpdateRecord 更新记录中 fields 包含的字段，字段的值为 nil 时清空。
+func (s *BitableService) UpdateRecord(appToken, tableId, recordId string, fields BitableFields, query *BitableRecordQueryOptions, options ...RequestOptionFunc) (*RecordResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/records/%s", appToken, tableId, recordId)
+	options = append(options, WithQuery(query))
+
+	req, err := s.client.NewServerRequest(http.MethodPut, u, &BitableRecord{Fields: fields}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(RecordResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteRecord 删除记录。
+func (s *BitableService) DeleteRecord(appToken, tableId, recordId string, options ...RequestOptionFunc) (*DeleteRecordResponse, *Response, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/records/%s", appToken, tableId, recordId)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(DeleteRecordResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// BatchCreateRecords 创建记录，每 500 条一次请求，按顺序返回创建的记录。
+// 出错时返回已经创建的记录。
+func (s *BitableService) BatchCreateRecords(appToken, tableId string, records []BitableRecord, query *BitableRecordQueryOptions, options ...RequestOptionFunc) ([]BitableRecord, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/records/batch_create", appToken, tableId)
+	return s.batchRecords(u, records, query, options)
+}
+
+// BatchUpdateRecords 更新记录，RecordId 不能为空，每 500 条一次请求。出错时返回已经更新的记录。
+func (s *BitableService) BatchUpdateRecords(appToken, tableId string, records []BitableRecord, query *BitableRecordQueryOptions, options ...RequestOptionFunc) ([]BitableRecord, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/records/batch_update", appToken, tableId)
+	for i := range records {
+		if len(records[i].RecordId) == 0 {
+			return nil, errors.Errorf("update record %d without record_id", i)
+		}
+	}
+	return s.batchRecords(u, records, query, options)
+}
+
+func (s *BitableService) batchRecords(u string, records []BitableRecord, query *BitableRecordQueryOptions, options []RequestOptionFunc) ([]BitableRecord, error) {
+	var done []BitableRecord
+	for start := 0; start < len(records); start += bitableBatchSize {
+		end := start + bitableBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		opt := struct {
+			Records []BitableRecord `json:"records"`
+		}{records[start:end]}
+
+		// 每次请求的内容不同，使用不同的 client_token，重试整个批量操作时仍然幂等
+		q := query
+		if query != nil && len(query.ClientToken) > 0 && len(records) > bitableBatchSize {
+			chunk := *query
+			chunk.ClientToken = bitableChunkToken(query.ClientToken, start)
+			q = &chunk
+		}
+		req, err := s.client.NewServerRequest(http.MethodPost, u, &opt, append(options[:len(options):len(options)], WithQuery(q)))
+		if err != nil {
+			return done, err
+		}
+
+		c := new(BatchRecordsResponse)
+		if _, err = s.client.Do(req, c); err != nil {
+			return done, err
+		}
+		if c.Code != 0 {
+			return done, errors.Errorf("records %d-%d: %d %s", start, end-1, c.Code, c.Message)
+		}
+		done = append(done, c.Data.Records...)
+	}
+	return done, nil
+}
+
+// bitableChunkToken 由 token 和分片的起始位置派生 uuid 格式的 token。
+func bitableChunkToken(token string, start int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s:%d", token, start)))
+	sum[6] = sum[6]&0x0f | 0x40
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// BatchDeleteRecords 删除记录，每 500 条一次请求。出错时返回已经删除的记录。
+func (s *BitableService) BatchDeleteRecords(appToken, tableId string, recordIds []string, options ...RequestOptionFunc) ([]DeletedRecord, error) {
+	u := fmt.Sprintf("bitable/v1/apps/%s/tables/%s/records/batch_delete", appToken, tableId)
+
+	var deleted []DeletedRecord
+	for start := 0; start < len(recordIds); start += bitableBatchSize {
+		end := start + bitableBatchSize
+		if end > len(recordIds) {
+			end = len(recordIds)
+		}
+		opt := struct {
+			Records []string `json:"records"`
+		}{recordIds[start:end]}
+
+		req, err := s.client.NewServerRequest(http.MethodPost, u, &opt, options)
+		if err != nil {
+			return deleted, err
+		}
+
+		c := new(BatchDeleteRecordsResponse)
+		if _, err = s.client.Do(req, c); err != nil {
+			return deleted, err
+		}
+		if c.Code != 0 {
+			return deleted, errors.Errorf("records %d-%d: %d %s", start, end-1, c.Code, c.Message)
+		}
+		deleted = append(deleted, c.Data.Records...)
+	}
+	return deleted, nil
+}
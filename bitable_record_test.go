@@ -0,0 +1,159 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBitableService_ListRecords(t *testing.T) {
+	Convey("test BitableService_ListRecords", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		base := "/open-apis/bitable/v1/apps/" + testBitableAppToken + "/tables/" + testBitableTableId + "/records"
+		mux.HandleFunc(base, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			q := r.URL.Query()
+			if q.Get("filter") != `CurrentValue.[状态]="待处理"` || q.Get("sort") != `["优先级 ASC"]` || q.Get("field_names") != `["标题","优先级","负责人"]` {
+				t.Errorf("Request query: %s", r.URL.RawQuery)
+			}
+			if q.Get("page_size") != "500" {
+				t.Errorf("Request page_size: %s", q.Get("page_size"))
+			}
+			if q.Get("page_token") == "" {
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"has_more": true, "page_token": "p2", "total": 2, "items": [
+					{"record_id": "rec1", "fields": {"标题": "登录失败", "优先级": "P0", "负责人": [{"id": "ou_1", "name": "张三"}]}}
+				]}}`)
+				return
+			}
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "total": 2, "items": [
+				{"record_id": "rec2", "fields": {"标题": [{"type": "text", "text": "导出"}, {"type": "mention", "text": "@李四"}], "优先级": "P1"}}
+			]}}`)
+		})
+		mux.HandleFunc(base+"/rec1", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				testParams(t, r, "user_id_type=user_id")
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"record": {"record_id": "rec1", "fields": {"标题": "登录失败"}}}}`)
+			case http.MethodPut:
+				testBody(t, r, `{"fields":{"状态":"已修复"}}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"record": {"record_id": "rec1", "fields": {"状态": "已修复"}}}}`)
+			case http.MethodDelete:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"record_id": "rec1", "deleted": true}}`)
+			}
+		})
+
+		records, err := client.Bitable.AllRecords(testBitableAppToken, testBitableTableId, &ListRecordsOptions{
+			Filter:     `CurrentValue.[状态]="待处理"`,
+			Sort:       BitableNames{"优先级 ASC"},
+			FieldNames: BitableNames{"标题", "优先级", "负责人"},
+		})
+		So(err, ShouldBeNil)
+		So(records, ShouldHaveLength, 2)
+		So(records[0].Fields.Text("标题"), ShouldEqual, "登录失败")
+		So(records[0].Fields.Persons("负责人")[0].Name, ShouldEqual, "张三")
+		So(records[1].Fields.Text("标题"), ShouldEqual, "导出@李四")
+
+		record, _, err := client.Bitable.GetRecord(testBitableAppToken, testBitableTableId, "rec1", &BitableRecordQueryOptions{UserIdType: "user_id"})
+		So(err, ShouldBeNil)
+		So(record.Data.Record.RecordId, ShouldEqual, "rec1")
+
+		updated, _, err := client.Bitable.UpdateRecord(testBitableAppToken, testBitableTableId, "rec1", BitableFields{"状态": "已修复"}, nil)
+		So(err, ShouldBeNil)
+		So(updated.Data.Record.Fields.SingleSelect("状态"), ShouldEqual, "已修复")
+
+		deleted, _, err := client.Bitable.DeleteRecord(testBitableAppToken, testBitableTableId, "rec1")
+		So(err, ShouldBeNil)
+		So(deleted.Data.Deleted, ShouldBeTrue)
+	})
+}
+
+func TestBitableService_BatchRecords(t *testing.T) {
+	Convey("test BitableService_BatchRecords", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		base := "/open-apis/bitable/v1/apps/" + testBitableAppToken + "/tables/" + testBitableTableId + "/records"
+		var (
+			batches []int
+			tokens  []string
+		)
+		mux.HandleFunc(base+"/batch_create", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			tokens = append(tokens, r.URL.Query().Get("client_token"))
+			var opt struct {
+				Records []BitableRecord `json:"records"`
+			}
+			json.NewDecoder(r.Body).Decode(&opt)
+			batches = append(batches, len(opt.Records))
+			for i := range opt.Records {
+				opt.Records[i].RecordId = fmt.Sprintf("rec%d", len(batches)*1000+i)
+			}
+			body, _ := json.Marshal(map[string]interface{}{"code": 0, "msg": "success", "data": opt})
+			w.Write(body)
+		})
+		mux.HandleFunc(base+"/batch_update", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"records":[{"record_id":"rec1","fields":{"状态":"已关闭"}}]}`)
+			fmt.Fprint(w, `{"code": 1254043, "msg": "RecordIdNotFound", "data": {}}`)
+		})
+		mux.HandleFunc(base+"/batch_delete", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			var opt struct {
+				Records []string `json:"records"`
+			}
+			json.NewDecoder(r.Body).Decode(&opt)
+			var deleted []DeletedRecord
+			for _, id := range opt.Records {
+				deleted = append(deleted, DeletedRecord{RecordId: id, Deleted: true})
+			}
+			body, _ := json.Marshal(map[string]interface{}{"code": 0, "msg": "success", "data": map[string]interface{}{"records": deleted}})
+			w.Write(body)
+		})
+
+		records := make([]BitableRecord, 1203)
+		for i := range records {
+			records[i].Fields = BitableFields{"标题": fmt.Sprintf("缺陷 %d", i)}
+		}
+		clientToken := "fe599b60-450f-46ff-b2ef-9f6675625b97"
+		created, err := client.Bitable.BatchCreateRecords(testBitableAppToken, testBitableTableId, records, &BitableRecordQueryOptions{ClientToken: clientToken})
+		So(err, ShouldBeNil)
+		So(batches, ShouldResemble, []int{500, 500, 203})
+		// 每次请求使用不同的 client_token，相同的 ClientToken 派生的 token 相同
+		So(tokens, ShouldHaveLength, 3)
+		So(tokens[0], ShouldNotEqual, tokens[1])
+		So(tokens[1], ShouldNotEqual, tokens[2])
+		So(tokens[0], ShouldNotEqual, clientToken)
+		So(tokens[0], ShouldEqual, bitableChunkToken(clientToken, 0))
+		So(tokens[2], ShouldHaveLength, len(clientToken))
+
+		// 只有一次请求时直接使用 ClientToken
+		_, err = client.Bitable.BatchCreateRecords(testBitableAppToken, testBitableTableId, records[:1], &BitableRecordQueryOptions{ClientToken: clientToken})
+		So(err, ShouldBeNil)
+		So(tokens[3], ShouldEqual, clientToken)
+		So(created, ShouldHaveLength, 1203)
+		So(created[500].RecordId, ShouldEqual, "rec2000")
+		So(created[1202].Fields.Text("标题"), ShouldEqual, "缺陷 1202")
+
+		_, err = client.Bitable.BatchUpdateRecords(testBitableAppToken, testBitableTableId, []BitableRecord{{Fields: BitableFields{"状态": "已关闭"}}}, nil)
+		So(err, ShouldNotBeNil)
+
+		_, err = client.Bitable.BatchUpdateRecords(testBitableAppToken, testBitableTableId, []BitableRecord{{RecordId: "rec1", Fields: BitableFields{"状态": "已关闭"}}}, nil)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "RecordIdNotFound")
+
+		ids := make([]string, 0, len(created))
+		for _, r := range created {
+			ids = append(ids, r.RecordId)
+		}
+		deleted, err := client.Bitable.BatchDeleteRecords(testBitableAppToken, testBitableTableId, ids)
+		So(err, ShouldBeNil)
+		So(deleted, ShouldHaveLength, 1203)
+	})
+}
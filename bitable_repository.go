@@ -0,0 +1,645 @@
+package feishu
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Upsert 时每次查询的 key 数量
+var bitableUpsertKeys = 50
+
+var bitableFieldTypes = map[string]int{
+	"text":          BitableFieldText,
+	"number":        BitableFieldNumber,
+	"select":        BitableFieldSingleSelect,
+	"multiselect":   BitableFieldMultiSelect,
+	"date":          BitableFieldDateTime,
+	"checkbox":      BitableFieldCheckbox,
+	"user":          BitableFieldUser,
+	"phone":         BitableFieldPhoneNumber,
+	"url":           BitableFieldUrl,
+	"attachment":    BitableFieldAttachment,
+	"link":          BitableFieldSingleLink,
+	"lookup":        BitableFieldLookup,
+	"formula":       BitableFieldFormula,
+	"duplexlink":    BitableFieldDuplexLink,
+	"created_time":  BitableFieldCreatedTime,
+	"modified_time": BitableFieldModifiedTime,
+	"created_user":  BitableFieldCreatedUser,
+	"modified_user": BitableFieldModifiedUser,
+	"autonumber":    BitableFieldAutoNumber,
+}
+
+var (
+	bitablePersonsType     = reflect.TypeOf([]BitablePerson{})
+	bitableAttachmentsType = reflect.TypeOf([]BitableAttachment{})
+	bitableUrlType         = reflect.TypeOf(BitableUrl{})
+	stringsType            = reflect.TypeOf([]string{})
+)
+
+// BitableRepository 按结构体读写数据表的记录，字段通过 tag 对应：
+//
+//	type Bug struct {
+//		RecordId string          `feishu:"record_id"`
+//		Key      string          `feishu:"field=编号,key"`
+//		Title    string          `feishu:"field=标题"`
+//		Priority string          `feishu:"field=优先级,type=select"`
+//		Labels   []string        `feishu:"field=标签"`
+//		Owners   []BitablePerson `feishu:"field=负责人"`
+//		Hours    float64         `feishu:"field=工时"`
+//		Found    time.Time       `feishu:"field=发现时间"`
+//		Release  []string        `feishu:"field=版本,type=link,table=tblxxx"`
+//		Created  time.Time       `feishu:"field=创建时间,type=created_time"`
+//		Note     string          `feishu:"-"`
+//	}
+//
+// field 为空时以字段名为名称，type 为空时按 Go 类型推断，公式、查找引用、创建时间等只读字段不会写入。
+// key 为 Upsert 使用的唯一字段，record_id 字段保存记录的 record_id。
+type BitableRepository struct {
+	service  *BitableService
+	appToken string
+	tableId  string
+
+	rowType  reflect.Type
+	fields   []bitableField
+	key      int
+	recordId int
+}
+
+type bitableField struct {
+	index int
+	name  string
+	typ   int
+	table string
+}
+
+// readOnly 只读字段不能写入。
+func (f *bitableField) readOnly() bool {
+	return f.typ == BitableFieldLookup || f.typ == BitableFieldFormula || f.typ >= BitableFieldCreatedTime
+}
+
+// NewRepository model 为结构体或结构体指针，只用于获取类型。
+func (s *BitableService) NewRepository(appToken, tableId string, model interface{}) (*BitableRepository, error) {
+	typ := reflect.TypeOf(model)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, errors.Errorf("repository model %T, want struct", model)
+	}
+
+	r := &BitableRepository{service: s, appToken: appToken, tableId: tableId, rowType: typ, key: -1, recordId: -1}
+	names := make(map[string]string)
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag := sf.Tag.Get("feishu")
+		if len(sf.PkgPath) > 0 || tag == "-" {
+			continue
+		}
+
+		f := bitableField{index: i, name: sf.Name}
+		key, typeName := false, ""
+		for _, part := range strings.Split(tag, ",") {
+			part = strings.TrimSpace(part)
+			kv := strings.SplitN(part, "=", 2)
+			switch {
+			case len(part) == 0:
+			case part == "key":
+				key = true
+			case part == "record_id":
+				if sf.Type.Kind() != reflect.String {
+					return nil, errors.Errorf("record_id field %s must be string", sf.Name)
+				}
+				r.recordId = i
+			case len(kv) == 2 && kv[0] == "field":
+				f.name = strings.TrimSpace(kv[1])
+			case len(kv) == 2 && kv[0] == "type":
+				typeName = strings.TrimSpace(kv[1])
+			case len(kv) == 2 && kv[0] == "table":
+				f.table = strings.TrimSpace(kv[1])
+			default:
+				return nil, errors.Errorf("invalid tag %q of field %s", tag, sf.Name)
+			}
+		}
+		if r.recordId == i {
+			continue
+		}
+
+		if len(typeName) > 0 {
+			var ok bool
+			if f.typ, ok = bitableFieldTypes[typeName]; !ok {
+				return nil, errors.Errorf("unknown field type %q of field %s", typeName, sf.Name)
+			}
+		} else {
+			var err error
+			if f.typ, err = bitableFieldType(sf.Type); err != nil {
+				return nil, errors.Wrapf(err, "field %s", sf.Name)
+			}
+		}
+		if other, ok := names[f.name]; ok {
+			return nil, errors.Errorf("fields %s and %s both map to %s", other, sf.Name, f.name)
+		}
+		names[f.name] = sf.Name
+		if key {
+			if r.key >= 0 {
+				return nil, errors.Errorf("more than one key field in %s", typ)
+			}
+			r.key = len(r.fields)
+		}
+		r.fields = append(r.fields, f)
+	}
+	if len(r.fields) == 0 {
+		return nil, errors.Errorf("no field of %s maps to the table", typ)
+	}
+	return r, nil
+}
+
+// bitableFieldType 按 Go 类型推断字段类型。
+func bitableFieldType(typ reflect.Type) (int, error) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ {
+	case timeType:
+		return BitableFieldDateTime, nil
+	case stringsType:
+		return BitableFieldMultiSelect, nil
+	case bitablePersonsType:
+		return BitableFieldUser, nil
+	case bitableAttachmentsType:
+		return BitableFieldAttachment, nil
+	case bitableUrlType:
+		return BitableFieldUrl, nil
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return BitableFieldText, nil
+	case reflect.Bool:
+		return BitableFieldCheckbox, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return BitableFieldNumber, nil
+	}
+	return 0, errors.Errorf("unsupported type %s", typ)
+}
+
+// resolve 结构体的字段名转换为多维表格的字段名称。
+func (r *BitableRepository) resolve(name string) string {
+	for _, f := range r.fields {
+		if r.rowType.Field(f.index).Name == name {
+			return f.name
+		}
+	}
+	return name
+}
+
+// EnsureFields 创建数据表中缺少的字段，返回创建的字段名称。已有字段的类型不会修改。
+func (r *BitableRepository) EnsureFields(options ...RequestOptionFunc) ([]string, error) {
+	existing, err := r.service.AllFields(r.appToken, r.tableId, options...)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(existing))
+	for _, f := range existing {
+		names[f.FieldName] = true
+	}
+
+	var created []string
+	for _, f := range r.fields {
+		if names[f.name] {
+			continue
+		}
+		field := &BitableField{FieldName: f.name, Type: f.typ}
+		if f.typ == BitableFieldSingleLink || f.typ == BitableFieldDuplexLink {
+			if len(f.table) == 0 {
+				return created, errors.Errorf("create link field %s without table", f.name)
+			}
+			field.Property = &BitableFieldProperty{TableId: f.table}
+		}
+		rsp, _, err := r.service.CreateField(r.appToken, r.tableId, field, options...)
+		if err != nil {
+			return created, err
+		}
+		if rsp.Code != 0 {
+			return created, errors.Errorf("create field %s: %d %s", f.name, rsp.Code, rsp.Message)
+		}
+		created = append(created, f.name)
+	}
+	return created, nil
+}
+
+// Find 查询记录到 dst，dst 为 *[]T 或 *[]*T，q 为空时查询所有记录。
+func (r *BitableRepository) Find(dst interface{}, q *BitableQuery, options ...RequestOptionFunc) error {
+	slice := reflect.ValueOf(dst)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice || sheetRowType(slice.Elem().Type().Elem()) != r.rowType {
+		return errors.Errorf("find records into %T, want *[]%s", dst, r.rowType)
+	}
+	slice = slice.Elem()
+
+	if q == nil {
+		q = NewBitableQuery()
+	}
+	opt := q.options(r.resolve)
+	for _, f := range r.fields {
+		opt.FieldNames = append(opt.FieldNames, f.name)
+	}
+	opt.PageSize = bitableBatchSize
+	if q.limit > 0 && q.limit < opt.PageSize {
+		opt.PageSize = q.limit
+	}
+
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+	for {
+		rsp, _, err := r.service.ListRecords(r.appToken, r.tableId, opt, options...)
+		if err != nil {
+			return err
+		}
+		if rsp.Code != 0 {
+			return errors.Errorf("list records of %s: %d %s", r.tableId, rsp.Code, rsp.Message)
+		}
+		for i := range rsp.Data.Items {
+			elem, err := r.decode(&rsp.Data.Items[i])
+			if err != nil {
+				return err
+			}
+			if slice.Type().Elem().Kind() == reflect.Ptr {
+				elem = elem.Addr()
+			}
+			slice.Set(reflect.Append(slice, elem))
+			if q.limit > 0 && slice.Len() >= q.limit {
+				return nil
+			}
+		}
+		if !rsp.Data.HasMore || len(rsp.Data.PageToken) == 0 {
+			return nil
+		}
+		opt.PageToken = rsp.Data.PageToken
+	}
+}
+
+// Get 查询记录到 dst，dst 为 *T。
+func (r *BitableRepository) Get(recordId string, dst interface{}, options ...RequestOptionFunc) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Type() != r.rowType {
+		return errors.Errorf("get record into %T, want *%s", dst, r.rowType)
+	}
+	rsp, _, err := r.service.GetRecord(r.appToken, r.tableId, recordId, nil, options...)
+	if err != nil {
+		return err
+	}
+	if rsp.Code != 0 {
+		return errors.Errorf("get record %s: %d %s", recordId, rsp.Code, rsp.Message)
+	}
+	elem, err := r.decode(&rsp.Data.Record)
+	if err != nil {
+		return err
+	}
+	v.Elem().Set(elem)
+	return nil
+}
+
+// Create 创建记录，src 为 *T、[]T 或 []*T，创建后设置 record_id 字段。
+func (r *BitableRepository) Create(src interface{}, options ...RequestOptionFunc) error {
+	rows, err := r.rows(src)
+	if err != nil {
+		return err
+	}
+	records := make([]BitableRecord, len(rows))
+	for i, row := range rows {
+		if records[i].Fields, err = r.encode(row, false); err != nil {
+			return err
+		}
+	}
+	created, err := r.service.BatchCreateRecords(r.appToken, r.tableId, records, nil, options...)
+	r.setRecordIds(rows, created)
+	return err
+}
+
+// Update 更新记录，record_id 字段不能为空，零值的字段会被清空。
+func (r *BitableRepository) Update(src interface{}, options ...RequestOptionFunc) error {
+	if r.recordId < 0 {
+		return errors.Errorf("update records of %s without record_id field", r.rowType)
+	}
+	rows, err := r.rows(src)
+	if err != nil {
+		return err
+	}
+	records := make([]BitableRecord, len(rows))
+	for i, row := range rows {
+		records[i].RecordId = row.Field(r.recordId).String()
+		if records[i].Fields, err = r.encode(row, true); err != nil {
+			return err
+		}
+	}
+	_, err = r.service.BatchUpdateRecords(r.appToken, r.tableId, records, nil, options...)
+	return err
+}
+
+// Upsert 按 key 字段查询记录，存在时更新，否则创建，返回创建和更新的数量。
+// key 不能为空，数据表中多条记录的 key 相同时返回错误。
+func (r *BitableRepository) Upsert(src interface{}, options ...RequestOptionFunc) (int, int, error) {
+	if r.key < 0 {
+		return 0, 0, errors.Errorf("upsert records of %s without key field", r.rowType)
+	}
+	rows, err := r.rows(src)
+	if err != nil {
+		return 0, 0, err
+	}
+	key := r.fields[r.key]
+
+	keys := make([]interface{}, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for i, row := range rows {
+		v := row.Field(key.index)
+		// 空的 key 在筛选时会匹配所有 key 为空的记录
+		if value, err := r.encodeField(v, &key); err != nil || value == nil {
+			return 0, 0, errors.Errorf("record %d: key %s is empty", i, key.name)
+		}
+		keys[i] = reflect.Indirect(v).Interface()
+		k := fmt.Sprint(keys[i])
+		if seen[k] {
+			return 0, 0, errors.Errorf("duplicate key %s=%s", key.name, k)
+		}
+		seen[k] = true
+	}
+
+	// 查询已有记录的 record_id
+	recordIds := make(map[string]string, len(rows))
+	for start := 0; start < len(keys); start += bitableUpsertKeys {
+		end := start + bitableUpsertKeys
+		if end > len(keys) {
+			end = len(keys)
+		}
+		opt := &ListRecordsOptions{
+			Filter:     BitableIn(key.name, keys[start:end]...).String(),
+			FieldNames: BitableNames{key.name},
+		}
+		records, err := r.service.AllRecords(r.appToken, r.tableId, opt, options...)
+		if err != nil {
+			return 0, 0, err
+		}
+		for i := range records {
+			v := reflect.New(r.rowType.Field(key.index).Type).Elem()
+			if err := r.decodeField(records[i].Fields, v, &key); err != nil {
+				return 0, 0, err
+			}
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				continue
+			}
+			k := fmt.Sprint(reflect.Indirect(v).Interface())
+			if !seen[k] {
+				continue
+			}
+			if id, ok := recordIds[k]; ok {
+				return 0, 0, errors.Errorf("records %s and %s have the same key %s=%s", id, records[i].RecordId, key.name, k)
+			}
+			recordIds[k] = records[i].RecordId
+		}
+	}
+
+	var creates, updates []reflect.Value
+	var createRecords, updateRecords []BitableRecord
+	for i, row := range rows {
+		if id, ok := recordIds[fmt.Sprint(keys[i])]; ok {
+			fields, err := r.encode(row, true)
+			if err != nil {
+				return 0, 0, err
+			}
+			if r.recordId >= 0 {
+				row.Field(r.recordId).SetString(id)
+			}
+			updates = append(updates, row)
+			updateRecords = append(updateRecords, BitableRecord{RecordId: id, Fields: fields})
+			continue
+		}
+		fields, err := r.encode(row, false)
+		if err != nil {
+			return 0, 0, err
+		}
+		creates = append(creates, row)
+		createRecords = append(createRecords, BitableRecord{Fields: fields})
+	}
+
+	created, err := r.service.BatchCreateRecords(r.appToken, r.tableId, createRecords, nil, options...)
+	r.setRecordIds(creates, created)
+	if err != nil {
+		return len(created), 0, err
+	}
+	updated, err := r.service.BatchUpdateRecords(r.appToken, r.tableId, updateRecords, nil, options...)
+	return len(created), len(updated), err
+}
+
+// Delete 删除记录，src 为 *T、[]T 或 []*T，需要 record_id 字段。
+func (r *BitableRepository) Delete(src interface{}, options ...RequestOptionFunc) error {
+	if r.recordId < 0 {
+		return errors.Errorf("delete records of %s without record_id field", r.rowType)
+	}
+	rows, err := r.rows(src)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.Field(r.recordId).String())
+	}
+	_, err = r.service.BatchDeleteRecords(r.appToken, r.tableId, ids, options...)
+	return err
+}
+
+// rows 返回 src 中可以设置的结构体。
+func (r *BitableRepository) rows(src interface{}) ([]reflect.Value, error) {
+	v := reflect.ValueOf(src)
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Type() == r.rowType {
+		return []reflect.Value{v.Elem()}, nil
+	}
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice || sheetRowType(v.Type().Elem()) != r.rowType {
+		return nil, errors.Errorf("records %T, want *%s or slice of %s", src, r.rowType, r.rowType)
+	}
+	rows := make([]reflect.Value, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		row := reflect.Indirect(v.Index(i))
+		if !row.IsValid() {
+			return nil, errors.Errorf("record %d is nil", i)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (r *BitableRepository) setRecordIds(rows []reflect.Value, records []BitableRecord) {
+	if r.recordId < 0 {
+		return
+	}
+	for i := range records {
+		if i < len(rows) && rows[i].CanSet() {
+			rows[i].Field(r.recordId).SetString(records[i].RecordId)
+		}
+	}
+}
+
+// encode 结构体转换为记录的字段，clear 为 true 时零值的字段为 nil 以清空。
+func (r *BitableRepository) encode(row reflect.Value, clear bool) (BitableFields, error) {
+	fields := make(BitableFields, len(r.fields))
+	for i := range r.fields {
+		f := &r.fields[i]
+		if f.readOnly() {
+			continue
+		}
+		value, err := r.encodeField(row.Field(f.index), f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %s", f.name)
+		}
+		if value != nil || clear {
+			fields[f.name] = value
+		}
+	}
+	return fields, nil
+}
+
+func (r *BitableRepository) encodeField(v reflect.Value, f *bitableField) (interface{}, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Type() {
+	case timeType:
+		if ms := BitableTimestamp(v.Interface().(time.Time)); ms != 0 {
+			return ms, nil
+		}
+		return nil, nil
+	case bitablePersonsType:
+		persons := v.Interface().([]BitablePerson)
+		if len(persons) == 0 {
+			return nil, nil
+		}
+		ids := make([]string, 0, len(persons))
+		for _, p := range persons {
+			ids = append(ids, p.Id)
+		}
+		return NewBitablePersons(ids...), nil
+	case bitableAttachmentsType:
+		attachments := v.Interface().([]BitableAttachment)
+		if len(attachments) == 0 {
+			return nil, nil
+		}
+		tokens := make([]string, 0, len(attachments))
+		for _, a := range attachments {
+			tokens = append(tokens, a.FileToken)
+		}
+		return NewBitableAttachments(tokens...), nil
+	case bitableUrlType:
+		if u := v.Interface().(BitableUrl); len(u.Link) > 0 {
+			return u, nil
+		}
+		return nil, nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if v.Len() == 0 {
+			return nil, nil
+		}
+		return v.String(), nil
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return nil, nil
+		}
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return nil, errors.Errorf("unsupported type %s", v.Type())
+	}
+	return v.Interface(), nil
+}
+
+// decode 记录转换为结构体。
+func (r *BitableRepository) decode(record *BitableRecord) (reflect.Value, error) {
+	elem := reflect.New(r.rowType).Elem()
+	if r.recordId >= 0 {
+		elem.Field(r.recordId).SetString(record.RecordId)
+	}
+	for i := range r.fields {
+		f := &r.fields[i]
+		if err := r.decodeField(record.Fields, elem.Field(f.index), f); err != nil {
+			return elem, errors.Wrapf(err, "record %s field %s", record.RecordId, f.name)
+		}
+	}
+	return elem, nil
+}
+
+func (r *BitableRepository) decodeField(fields BitableFields, v reflect.Value, f *bitableField) error {
+	if value, ok := fields[f.name]; !ok || value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := r.decodeField(fields, elem.Elem(), f); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		v.Set(reflect.ValueOf(fields.Time(f.name)))
+		return nil
+	case bitablePersonsType:
+		v.Set(reflect.ValueOf(fields.Persons(f.name)))
+		return nil
+	case bitableAttachmentsType:
+		v.Set(reflect.ValueOf(fields.Attachments(f.name)))
+		return nil
+	case bitableUrlType:
+		v.Set(reflect.ValueOf(fields.Url(f.name)))
+		return nil
+	case stringsType:
+		if f.typ == BitableFieldSingleLink || f.typ == BitableFieldDuplexLink {
+			v.Set(reflect.ValueOf(fields.Links(f.name)))
+		} else {
+			v.Set(reflect.ValueOf(fields.MultiSelect(f.name)))
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(fields.Text(f.name))
+		return nil
+	case reflect.Bool:
+		v.SetBool(fields.Checkbox(f.name))
+		return nil
+	}
+
+	n, ok := fields.Number(f.name)
+	if !ok {
+		if !fields.decode(f.name, v.Addr().Interface()) {
+			return errors.Errorf("invalid value %v for %s", fields[f.name], v.Type())
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(math.Round(n)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(math.Round(n)))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(n)
+	default:
+		return errors.Errorf("invalid value %v for %s", fields[f.name], v.Type())
+	}
+	return nil
+}
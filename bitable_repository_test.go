@@ -0,0 +1,220 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testBug struct {
+	RecordId string          `feishu:"record_id"`
+	Key      string          `feishu:"field=编号,key"`
+	Title    string          `feishu:"field=标题"`
+	Priority string          `feishu:"field=优先级,type=select"`
+	Labels   []string        `feishu:"field=标签"`
+	Owners   []BitablePerson `feishu:"field=负责人"`
+	Hours    float64         `feishu:"field=工时"`
+	Closed   bool            `feishu:"field=已关闭"`
+	Found    *time.Time      `feishu:"field=发现时间"`
+	Created  time.Time       `feishu:"field=创建时间,type=created_time"`
+	Note     string          `feishu:"-"`
+}
+
+// testBitableStore 内存中的数据表，查询时忽略筛选公式。
+type testBitableStore struct {
+	t       *testing.T
+	fields  []BitableField
+	records []BitableRecord
+	lists   []string
+}
+
+func (s *testBitableStore) register(mux *http.ServeMux) {
+	base := "/open-apis/bitable/v1/apps/" + testBitableAppToken + "/tables/" + testBitableTableId
+	write := func(w http.ResponseWriter, data interface{}) {
+		body, _ := json.Marshal(map[string]interface{}{"code": 0, "msg": "success", "data": data})
+		w.Write(body)
+	}
+	mux.HandleFunc(base+"/fields", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			write(w, map[string]interface{}{"items": s.fields})
+		case http.MethodPost:
+			var field BitableField
+			json.NewDecoder(r.Body).Decode(&field)
+			field.FieldId = fmt.Sprintf("fld%d", len(s.fields)+1)
+			s.fields = append(s.fields, field)
+			write(w, map[string]interface{}{"field": field})
+		}
+	})
+	mux.HandleFunc(base+"/records", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(s.t, r, http.MethodGet)
+		s.lists = append(s.lists, r.URL.Query().Get("filter"))
+		write(w, map[string]interface{}{"items": s.records})
+	})
+	mux.HandleFunc(base+"/records/batch_create", func(w http.ResponseWriter, r *http.Request) {
+		var opt struct {
+			Records []BitableRecord `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&opt)
+		for i := range opt.Records {
+			opt.Records[i].RecordId = fmt.Sprintf("rec%d", len(s.records)+1)
+			s.records = append(s.records, opt.Records[i])
+		}
+		write(w, opt)
+	})
+	mux.HandleFunc(base+"/records/batch_update", func(w http.ResponseWriter, r *http.Request) {
+		var opt struct {
+			Records []BitableRecord `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&opt)
+		for i := range opt.Records {
+			record := s.find(opt.Records[i].RecordId)
+			for k, v := range opt.Records[i].Fields {
+				record.Fields[k] = v
+			}
+			opt.Records[i] = *record
+		}
+		write(w, opt)
+	})
+	mux.HandleFunc(base+"/records/batch_delete", func(w http.ResponseWriter, r *http.Request) {
+		var opt struct {
+			Records []string `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&opt)
+		var deleted []DeletedRecord
+		for _, id := range opt.Records {
+			for i := range s.records {
+				if s.records[i].RecordId == id {
+					s.records = append(s.records[:i], s.records[i+1:]...)
+					deleted = append(deleted, DeletedRecord{RecordId: id, Deleted: true})
+					break
+				}
+			}
+		}
+		write(w, map[string]interface{}{"records": deleted})
+	})
+	mux.HandleFunc(base+"/records/rec1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(s.t, r, http.MethodGet)
+		write(w, map[string]interface{}{"record": s.find("rec1")})
+	})
+}
+
+func (s *testBitableStore) find(recordId string) *BitableRecord {
+	for i := range s.records {
+		if s.records[i].RecordId == recordId {
+			return &s.records[i]
+		}
+	}
+	s.t.Errorf("record %s not found", recordId)
+	return &BitableRecord{Fields: BitableFields{}}
+}
+
+func TestBitableRepository(t *testing.T) {
+	Convey("test BitableRepository", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		store := &testBitableStore{t: t, fields: []BitableField{
+			{FieldId: "fld1", FieldName: "标题", Type: BitableFieldText, IsPrimary: true},
+			{FieldId: "fld2", FieldName: "创建时间", Type: BitableFieldCreatedTime},
+		}}
+		store.register(mux)
+
+		repo, err := client.Bitable.NewRepository(testBitableAppToken, testBitableTableId, &testBug{})
+		So(err, ShouldBeNil)
+
+		created, err := repo.EnsureFields()
+		So(err, ShouldBeNil)
+		So(created, ShouldResemble, []string{"编号", "优先级", "标签", "负责人", "工时", "已关闭", "发现时间"})
+		So(store.fields[3].Type, ShouldEqual, BitableFieldSingleSelect)
+		So(store.fields[4].Type, ShouldEqual, BitableFieldMultiSelect)
+		So(store.fields[5].Type, ShouldEqual, BitableFieldUser)
+		So(store.fields[8].Type, ShouldEqual, BitableFieldDateTime)
+
+		created, err = repo.EnsureFields()
+		So(err, ShouldBeNil)
+		So(created, ShouldBeEmpty)
+
+		found := time.Date(2021, 6, 1, 10, 0, 0, 0, time.Local)
+		bugs := []*testBug{
+			{Key: "B1", Title: "登录失败", Priority: "P0", Labels: []string{"前端"}, Owners: NewBitablePersons("ou_1"), Hours: 2.5, Found: &found},
+			{Key: "B2", Title: "导出超时", Priority: "P1"},
+		}
+		So(repo.Create(bugs), ShouldBeNil)
+		So(bugs[0].RecordId, ShouldEqual, "rec1")
+		So(bugs[1].RecordId, ShouldEqual, "rec2")
+		So(store.records[0].Fields, ShouldContainKey, "发现时间")
+		So(store.records[1].Fields, ShouldNotContainKey, "标签")
+		So(store.records[1].Fields, ShouldNotContainKey, "创建时间")
+
+		var got testBug
+		So(repo.Get("rec1", &got), ShouldBeNil)
+		So(got.RecordId, ShouldEqual, "rec1")
+		So(got.Labels, ShouldResemble, []string{"前端"})
+		So(got.Owners[0].Id, ShouldEqual, "ou_1")
+		So(got.Hours, ShouldEqual, 2.5)
+		So(got.Found.Equal(found), ShouldBeTrue)
+
+		var list []testBug
+		q := NewBitableQuery().Where(BitableEq("Priority", "P0")).OrderBy("Hours", true).Limit(1)
+		So(repo.Find(&list, q), ShouldBeNil)
+		So(list, ShouldHaveLength, 1)
+		So(list[0].Title, ShouldEqual, "登录失败")
+		So(store.lists[0], ShouldEqual, `CurrentValue.[优先级]="P0"`)
+
+		bugs[0].Closed = true
+		bugs[0].Labels = nil
+		So(repo.Update(bugs[0]), ShouldBeNil)
+		So(store.records[0].Fields["已关闭"], ShouldEqual, true)
+		So(store.records[0].Fields, ShouldContainKey, "标签")
+		So(store.records[0].Fields["标签"], ShouldBeNil)
+
+		upserts := []testBug{
+			{Key: "B2", Title: "导出超时", Priority: "P0"},
+			{Key: "B3", Title: "搜索为空", Priority: "P2"},
+		}
+		n, m, err := repo.Upsert(upserts)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 1)
+		So(m, ShouldEqual, 1)
+		So(upserts[0].RecordId, ShouldEqual, "rec2")
+		So(upserts[1].RecordId, ShouldEqual, "rec3")
+		So(store.records, ShouldHaveLength, 3)
+		So(store.records[1].Fields["优先级"], ShouldEqual, "P0")
+		So(store.lists[1], ShouldEqual, `OR(CurrentValue.[编号]="B2",CurrentValue.[编号]="B3")`)
+
+		_, _, err = repo.Upsert([]testBug{{Key: "B4"}, {Key: "B4"}})
+		So(err, ShouldNotBeNil)
+		_, _, err = repo.Upsert([]testBug{{Key: "", Title: "无编号"}})
+		So(err, ShouldNotBeNil)
+		So(store.records, ShouldHaveLength, 3)
+
+		store.records = append(store.records, BitableRecord{RecordId: "rec9", Fields: map[string]interface{}{"编号": "B3"}})
+		_, _, err = repo.Upsert([]testBug{{Key: "B3", Title: "搜索为空", Priority: "P1"}})
+		So(err, ShouldNotBeNil)
+		So(store.records[2].Fields["优先级"], ShouldEqual, "P2")
+		store.records = store.records[:3]
+
+		list = nil
+		So(repo.Find(&list, nil), ShouldBeNil)
+		So(list, ShouldHaveLength, 3)
+		So(repo.Delete(list[:2]), ShouldBeNil)
+		So(store.records, ShouldHaveLength, 1)
+		So(store.records[0].RecordId, ShouldEqual, "rec3")
+
+		_, err = client.Bitable.NewRepository(testBitableAppToken, testBitableTableId, struct {
+			A string `feishu:"key"`
+			B string `feishu:"key"`
+		}{})
+		So(err, ShouldNotBeNil)
+		_, err = client.Bitable.NewRepository(testBitableAppToken, testBitableTableId, struct {
+			A chan int
+		}{})
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,142 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testBitableAppToken = "bascnCMII2ORej2RItqpZZUNMIe"
+	testBitableTableId  = "tblsRc9GRRXKqhvW"
+)
+
+func TestBitableService_Tables(t *testing.T) {
+	Convey("test BitableService_Tables", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		base := "/open-apis/bitable/v1/apps/" + testBitableAppToken
+		mux.HandleFunc(base, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"app": {"app_token": "%s", "name": "缺陷分拣", "revision": 12}}}`, testBitableAppToken)
+		})
+		mux.HandleFunc(base+"/tables", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				if r.URL.Query().Get("page_token") == "" {
+					testParams(t, r, "page_size=100")
+					fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"has_more": true, "page_token": "p2", "total": 2, "items": [{"table_id": "%s", "name": "缺陷"}]}}`, testBitableTableId)
+					return
+				}
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "total": 2, "items": [{"table_id": "tbl2", "name": "版本"}]}}`)
+			case http.MethodPost:
+				testBody(t, r, `{"table":{"name":"版本","fields":[{"field_name":"版本号","type":1},{"field_name":"状态","type":3,"property":{"options":[{"name":"开发中"},{"name":"已发布"}]}}]}}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"table_id": "tbl2", "default_view_id": "vew1", "field_id_list": ["fld1", "fld2"]}}`)
+			}
+		})
+		mux.HandleFunc(base+"/tables/tbl2", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodDelete)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+		})
+
+		app, _, err := client.Bitable.GetApp(testBitableAppToken)
+		So(err, ShouldBeNil)
+		So(app.Data.App.Name, ShouldEqual, "缺陷分拣")
+
+		tables, err := client.Bitable.AllTables(testBitableAppToken)
+		So(err, ShouldBeNil)
+		So(tables, ShouldHaveLength, 2)
+		So(tables[1].Name, ShouldEqual, "版本")
+
+		created, _, err := client.Bitable.CreateTable(testBitableAppToken, &CreateBitableTableOptions{
+			Name: "版本",
+			Fields: []BitableField{
+				{FieldName: "版本号", Type: BitableFieldText},
+				{FieldName: "状态", Type: BitableFieldSingleSelect, Property: &BitableFieldProperty{
+					Options: []BitableFieldOption{{Name: "开发中"}, {Name: "已发布"}},
+				}},
+			},
+		})
+		So(err, ShouldBeNil)
+		So(created.Data.TableId, ShouldEqual, "tbl2")
+		So(created.Data.FieldIdList, ShouldHaveLength, 2)
+
+		deleted, _, err := client.Bitable.DeleteTable(testBitableAppToken, "tbl2")
+		So(err, ShouldBeNil)
+		So(deleted.Code, ShouldEqual, 0)
+	})
+}
+
+func TestBitableService_ViewsFields(t *testing.T) {
+	Convey("test BitableService_ViewsFields", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		base := "/open-apis/bitable/v1/apps/" + testBitableAppToken + "/tables/" + testBitableTableId
+		mux.HandleFunc(base+"/views", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"items": [{"view_id": "vew1", "view_name": "表格", "view_type": "grid"}]}}`)
+			case http.MethodPost:
+				testBody(t, r, `{"view_name":"看板","view_type":"kanban"}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"view": {"view_id": "vew2", "view_name": "看板", "view_type": "kanban"}}}`)
+			}
+		})
+		mux.HandleFunc(base+"/fields", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				testParams(t, r, "page_size=100")
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "items": [
+					{"field_id": "fld1", "field_name": "标题", "type": 1, "is_primary": true},
+					{"field_id": "fld2", "field_name": "优先级", "type": 3, "property": {"options": [{"id": "opt1", "name": "P0", "color": 0}]}}
+				]}}`)
+			case http.MethodPost:
+				testBody(t, r, `{"field_name":"负责人","type":11,"property":{"multiple":false}}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"field": {"field_id": "fld3", "field_name": "负责人", "type": 11}}}`)
+			}
+		})
+		mux.HandleFunc(base+"/fields/fld3", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPut:
+				testBody(t, r, `{"field_name":"处理人","type":11}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"field": {"field_id": "fld3", "field_name": "处理人", "type": 11}}}`)
+			case http.MethodDelete:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"field_id": "fld3", "deleted": true}}`)
+			}
+		})
+
+		views, _, err := client.Bitable.ListViews(testBitableAppToken, testBitableTableId, nil)
+		So(err, ShouldBeNil)
+		So(views.Data.Items[0].ViewType, ShouldEqual, BitableViewGrid)
+
+		view, _, err := client.Bitable.CreateView(testBitableAppToken, testBitableTableId, &BitableView{ViewName: "看板", ViewType: BitableViewKanban})
+		So(err, ShouldBeNil)
+		So(view.Data.View.ViewId, ShouldEqual, "vew2")
+
+		fields, err := client.Bitable.AllFields(testBitableAppToken, testBitableTableId)
+		So(err, ShouldBeNil)
+		So(fields, ShouldHaveLength, 2)
+		So(fields[0].IsPrimary, ShouldBeTrue)
+		So(fields[1].Property.Options[0].Name, ShouldEqual, "P0")
+
+		multiple := false
+		field, _, err := client.Bitable.CreateField(testBitableAppToken, testBitableTableId, &BitableField{
+			FieldName: "负责人", Type: BitableFieldUser, Property: &BitableFieldProperty{Multiple: &multiple},
+		})
+		So(err, ShouldBeNil)
+		So(field.Data.Field.FieldId, ShouldEqual, "fld3")
+
+		updated, _, err := client.Bitable.UpdateField(testBitableAppToken, testBitableTableId, "fld3", &BitableField{FieldName: "处理人", Type: BitableFieldUser})
+		So(err, ShouldBeNil)
+		So(updated.Data.Field.FieldName, ShouldEqual, "处理人")
+
+		deleted, _, err := client.Bitable.DeleteField(testBitableAppToken, testBitableTableId, "fld3")
+		So(err, ShouldBeNil)
+		So(deleted.Data.Deleted, ShouldBeTrue)
+	})
+}
@@ -0,0 +1,234 @@
+package feishu
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BitableRecord 记录，Fields 的 key 为字段名称。CreatedTime 等在查询时设置 AutomaticFields 才返回。
+type BitableRecord struct {
+	RecordId         string         `json:"record_id,omitempty"`
+	Fields           BitableFields  `json:"fields"`
+	CreatedBy        *BitablePerson `json:"created_by,omitempty"`
+	CreatedTime      int64          `json:"created_time,omitempty"`
+	LastModifiedBy   *BitablePerson `json:"last_modified_by,omitempty"`
+	LastModifiedTime int64          `json:"last_modified_time,omitempty"`
+}
+
+// BitableFields 记录中字段的值，写入时的格式：
+//
+//	文本、单选、电话       string
+//	数字                   float64 或整数
+//	多选                   []string
+//	日期                   毫秒时间戳，见 BitableTimestamp
+//	复选框                 bool
+//	人员                   []BitablePerson，见 NewBitablePersons
+//	超链接                 BitableUrl
+//	附件                   []BitableAttachment，见 NewBitableAttachments
+//	单向关联、双向关联     []string，记录的 record_id
+//
+// 读取时通过 Text、Number 等方法转换为对应的类型，值为空的字段不返回。
+type BitableFields map[string]interface{}
+
+type BitablePerson struct {
+	Id     string `json:"id"`
+	Name   string `json:"name,omitempty"`
+	EnName string `json:"en_name,omitempty"`
+	Email  string `json:"email,omitempty"`
+}
+
+type BitableAttachment struct {
+	FileToken string `json:"file_token"`
+	Name      string `json:"name,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Size      int64  `json:"size,omitempty"`
+	Url       string `json:"url,omitempty"`
+	TmpUrl    string `json:"tmp_url,omitempty"`
+}
+
+type BitableUrl struct {
+	Link string `json:"link"`
+	Text string `json:"text"`
+}
+
+// NewBitablePersons 人员字段的值，ids 的类型与查询参数 user_id_type 一致，默认为 open_id。
+func NewBitablePersons(ids ...string) []BitablePerson {
+	persons := make([]BitablePerson, 0, len(ids))
+	for _, id := range ids {
+		persons = append(persons, BitablePerson{Id: id})
+	}
+	return persons
+}
+
+// NewBitableAttachments 附件字段的值，tokens 为上传到多维表格的文件 token。
+func NewBitableAttachments(tokens ...string) []BitableAttachment {
+	attachments := make([]BitableAttachment, 0, len(tokens))
+	for _, token := range tokens {
+		attachments = append(attachments, BitableAttachment{FileToken: token})
+	}
+	return attachments
+}
+
+// BitableTimestamp 日期字段的值，零值为 0。
+func BitableTimestamp(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// BitableTime 毫秒时间戳转换为时间，0 为零值。
+func BitableTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// decode 通过 JSON 把字段的值转换为 v 的类型。
+func (f BitableFields) decode(name string, v interface{}) bool {
+	value, ok := f[name]
+	if !ok || value == nil {
+		return false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Text 文本、单选、电话、自动编号等字段的文本，富文本和数组按顺序连接，数字转换为文本。
+func (f BitableFields) Text(name string) string {
+	switch v := f[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}:
+		if text, ok := v["text"].(string); ok {
+			return text
+		}
+	case []interface{}:
+		// 富文本、人员按顺序连接，多选以逗号分隔
+		var texts []string
+		sep := ""
+		for _, item := range v {
+			switch item := item.(type) {
+			case string:
+				texts, sep = append(texts, item), ","
+			case map[string]interface{}:
+				if text, ok := item["text"].(string); ok {
+					texts = append(texts, text)
+				} else if name, ok := item["name"].(string); ok {
+					texts = append(texts, name)
+				}
+			}
+		}
+		return strings.Join(texts, sep)
+	}
+	return ""
+}
+
+// Number 数字字段的值，文本形式的数字也会转换。
+func (f BitableFields) Number(name string) (float64, bool) {
+	switch v := f[name].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// SingleSelect 单选字段选中的选项。
+func (f BitableFields) SingleSelect(name string) string {
+	if s, ok := f[name].(string); ok {
+		return s
+	}
+	return ""
+}
+
+// MultiSelect 多选字段选中的选项。
+func (f BitableFields) MultiSelect(name string) []string {
+	var options []string
+	f.decode(name, &options)
+	return options
+}
+
+// Time 日期、创建时间、修改时间字段的值。
+func (f BitableFields) Time(name string) time.Time {
+	if ms, ok := f.Number(name); ok {
+		return BitableTime(int64(ms))
+	}
+	return time.Time{}
+}
+
+// Checkbox 复选框字段是否选中。
+func (f BitableFields) Checkbox(name string) bool {
+	b, _ := f[name].(bool)
+	return b
+}
+
+// Persons 人员、创建人、修改人字段的值。
+func (f BitableFields) Persons(name string) []BitablePerson {
+	var persons []BitablePerson
+	if !f.decode(name, &persons) {
+		// 创建人、修改人为单个人员
+		var person BitablePerson
+		if f.decode(name, &person) && len(person.Id) > 0 {
+			persons = []BitablePerson{person}
+		}
+	}
+	return persons
+}
+
+// Attachments 附件字段的值，Url 下载时需要授权。
+func (f BitableFields) Attachments(name string) []BitableAttachment {
+	var attachments []BitableAttachment
+	f.decode(name, &attachments)
+	return attachments
+}
+
+// Url 超链接字段的值。
+func (f BitableFields) Url(name string) BitableUrl {
+	var u BitableUrl
+	f.decode(name, &u)
+	return u
+}
+
+// Links 单向关联、双向关联字段关联的记录 id。
+func (f BitableFields) Links(name string) []string {
+	var ids []string
+	if f.decode(name, &ids) {
+		return ids
+	}
+	var link struct {
+		LinkRecordIds []string `json:"link_record_ids"`
+	}
+	if f.decode(name, &link) {
+		return link.LinkRecordIds
+	}
+	var items []struct {
+		RecordIds []string `json:"record_ids"`
+	}
+	f.decode(name, &items)
+	for _, item := range items {
+		ids = append(ids, item.RecordIds...)
+	}
+	return ids
+}
@@ -0,0 +1,61 @@
+package feishu
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBitableFields(t *testing.T) {
+	Convey("test BitableFields", t, func() {
+		var record BitableRecord
+		err := json.Unmarshal([]byte(`{"record_id": "rec1", "fields": {
+			"标题": "登录失败",
+			"工时": 2.5,
+			"优先级": "P0",
+			"标签": ["前端", "回归"],
+			"发现时间": 1656633600000,
+			"已验证": true,
+			"负责人": [{"id": "ou_1", "name": "张三", "email": "zhangsan@example.com"}],
+			"创建人": {"id": "ou_2", "name": "李四"},
+			"截图": [{"file_token": "boxcn1", "name": "a.png", "type": "image/png", "size": 1024, "url": "https://example.com/a.png"}],
+			"链接": {"link": "https://example.com/issue/1", "text": "issue 1"},
+			"版本": ["recA", "recB"],
+			"关联": {"link_record_ids": ["recC"]}
+		}}`), &record)
+		So(err, ShouldBeNil)
+
+		f := record.Fields
+		So(f.Text("标题"), ShouldEqual, "登录失败")
+		n, ok := f.Number("工时")
+		So(ok, ShouldBeTrue)
+		So(n, ShouldEqual, 2.5)
+		_, ok = f.Number("不存在")
+		So(ok, ShouldBeFalse)
+		So(f.SingleSelect("优先级"), ShouldEqual, "P0")
+		So(f.MultiSelect("标签"), ShouldResemble, []string{"前端", "回归"})
+		So(f.Text("标签"), ShouldEqual, "前端,回归")
+		So(f.Time("发现时间").Equal(time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+		So(f.Time("不存在").IsZero(), ShouldBeTrue)
+		So(f.Checkbox("已验证"), ShouldBeTrue)
+		So(f.Persons("负责人")[0].Email, ShouldEqual, "zhangsan@example.com")
+		So(f.Persons("创建人")[0].Id, ShouldEqual, "ou_2")
+		So(f.Attachments("截图")[0].FileToken, ShouldEqual, "boxcn1")
+		So(f.Url("链接").Text, ShouldEqual, "issue 1")
+		So(f.Text("链接"), ShouldEqual, "issue 1")
+		So(f.Links("版本"), ShouldResemble, []string{"recA", "recB"})
+		So(f.Links("关联"), ShouldResemble, []string{"recC"})
+
+		body, err := json.Marshal(&BitableRecord{Fields: BitableFields{
+			"负责人":  NewBitablePersons("ou_1"),
+			"截图":   NewBitableAttachments("boxcn1"),
+			"发现时间": BitableTimestamp(time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)),
+		}})
+		So(err, ShouldBeNil)
+		So(string(body), ShouldEqual, `{"fields":{"发现时间":1656633600000,"截图":[{"file_token":"boxcn1"}],"负责人":[{"id":"ou_1"}]}}`)
+		So(BitableTimestamp(time.Time{}), ShouldEqual, 0)
+		So(BitableTime(0).IsZero(), ShouldBeTrue)
+	})
+}
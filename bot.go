@@ -51,14 +51,10 @@ type BotResponse struct {
 func (s *BotService) SendBotCardMessage(botKey, secret string, opt *BotCardMessageOption, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
 	u := fmt.Sprintf("bot/v2/hook/%s", botKey)
 
-	if len(secret) > 0 {
-		timestamp := time.Now().Unix()
-		opt.Timestamp = strconv.FormatInt(timestamp, 10)
-		if sign, err := GenSign(secret, timestamp); err != nil {
-			return nil, nil, err
-		} else {
-			opt.Sign = sign
-		}
+	if timestamp, sign, err := s.sign(botKey, secret); err != nil {
+		return nil, nil, err
+	} else {
+		opt.Timestamp, opt.Sign = timestamp, sign
 	}
 
 	req, err := s.client.NewRequest(http.MethodPost, u, opt, options)
@@ -75,6 +71,26 @@ func (s *BotService) SendBotCardMessage(botKey, secret string, opt *BotCardMessa
 	return c, resp, err
 }
 
+// sign 生成签名，secret 为空时使用 WithBotSecretProvider 配置的 secret，都没有则不签名。
+func (s *BotService) sign(botKey, secret string) (timestamp, sign string, err error) {
+	if len(secret) == 0 {
+		if provider, ok := s.client.botSecrets[botKey]; ok {
+			if secret, err = provider.Secret(); err != nil {
+				return "", "", err
+			}
+		}
+	}
+	if len(secret) == 0 {
+		return "", "", nil
+	}
+
+	now := time.Now().Unix()
+	if sign, err = GenSign(secret, now); err != nil {
+		return "", "", err
+	}
+	return strconv.FormatInt(now, 10), sign, nil
+}
+
 func GenSign(secret string, timestamp int64) (string, error) {
 	//timestamp + key 做sha256, 再进行base64 encode
 	stringToSign := fmt.Sprintf("%v", timestamp) + "\n" + secret
@@ -98,14 +114,10 @@ type BotMessageOption struct {
 func (s *BotService) SendBotMessage(botKey, secret string, opt *BotMessageOption, options ...RequestOptionFunc) (*BotResponse, *Response, error) {
 	u := fmt.Sprintf("bot/v2/hook/%s", botKey)
 
-	if len(secret) > 0 {
-		timestamp := time.Now().Unix()
-		opt.Timestamp = strconv.FormatInt(timestamp, 10)
-		if sign, err := GenSign(secret, timestamp); err != nil {
-			return nil, nil, err
-		} else {
-			opt.Sign = sign
-		}
+	if timestamp, sign, err := s.sign(botKey, secret); err != nil {
+		return nil, nil, err
+	} else {
+		opt.Timestamp, opt.Sign = timestamp, sign
 	}
 
 	req, err := s.client.NewRequest(http.MethodPost, u, opt, options)
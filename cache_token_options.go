@@ -46,6 +46,21 @@ type LocalCache struct {
 	sync.Mutex
 }
 
+// separateLockCache 共享缓存数据，但使用独立的锁。
+// 刷新时需要先获取其他 token 的场景（如 jsapi_ticket 依赖 tenant_access_token）使用，避免重复上锁导致死锁。
+type separateLockCache struct {
+	TokenCache
+	mu sync.Mutex
+}
+
+func (c *separateLockCache) Lock() {
+	c.mu.Lock()
+}
+
+func (c *separateLockCache) Unlock() {
+	c.mu.Unlock()
+}
+
 var (
 	tokenCache *LocalCache
 	once       sync.Once
@@ -70,6 +85,13 @@ func WithLocalCache() CacheOptionFunc {
 	}
 }
 
+// 使用自定义缓存，多个 token manager 可共享同一个缓存
+func WithTokenCache(cache TokenCache) CacheOptionFunc {
+	return func(s *accessTokenManagerService) {
+		s.Cache = cache
+	}
+}
+
 func WithRedisCache(addr, password string) CacheOptionFunc {
 	return func(s *accessTokenManagerService) {
 		s.Cache = &RedisCache{
@@ -0,0 +1,219 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type CalendarService struct {
+	client *Client
+}
+
+type Calendar struct {
+	CalendarId   string `json:"calendar_id,omitempty"`
+	Summary      string `json:"summary,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Permissions  string `json:"permissions,omitempty"` // private、show_only_free_busy、public
+	Color        int    `json:"color,omitempty"`
+	Type         string `json:"type,omitempty"` // primary、shared、google、resource、exchange
+	SummaryAlias string `json:"summary_alias,omitempty"`
+	IsDeleted    bool   `json:"is_deleted,omitempty"`
+	IsThirdParty bool   `json:"is_third_party,omitempty"`
+	Role         string `json:"role,omitempty"` // unknown、free_busy_reader、reader、writer、owner
+}
+
+// ListCalendarsOptions 分页参数，SyncToken 用于增量同步，第一次同步时为空。
+type ListCalendarsOptions struct {
+	PageSize  int    `url:"page_size,omitempty"`
+	PageToken string `url:"page_token,omitempty"`
+	SyncToken string `url:"sync_token,omitempty"`
+}
+
+type ListCalendarsResponse struct {
+	CodeMsg
+	Data struct {
+		HasMore      bool       `json:"has_more"`
+		PageToken    string     `json:"page_token"`
+		SyncToken    string     `json:"sync_token"`
+		CalendarList []Calendar `json:"calendar_list"`
+	} `json:"data"`
+}
+
+type CalendarResponse struct {
+	CodeMsg
+	Data struct {
+		Calendar Calendar `json:"calendar"`
+	} `json:"data"`
+}
+
+// GetCalendarResponse 查询日历时 data 即日历。
+type GetCalendarResponse struct {
+	CodeMsg
+	Data Calendar `json:"data"`
+}
+
+type PrimaryCalendarsResponse struct {
+	CodeMsg
+	Data struct {
+		Calendars []struct {
+			Calendar Calendar `json:"calendar"`
+			UserId   string   `json:"user_id"`
+		} `json:"calendars"`
+	} `json:"data"`
+}
+
+// ListCalendars 查询应用身份可见的日历。
+func (s *CalendarService) ListCalendars(opt *ListCalendarsOptions, options ...RequestOptionFunc) (*ListCalendarsResponse, *Response, error) {
+	u := "calendar/v4/calendars"
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListCalendarsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// CreateCalendar 创建共享日历。
+func (s *CalendarService) CreateCalendar(opt *Calendar, options ...RequestOptionFunc) (*CalendarResponse, *Response, error) {
+	u := "calendar/v4/calendars"
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(CalendarResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// GetCalendar 查询日历。
+func (s *CalendarService) GetCalendar(calendarId string, options ...RequestOptionFunc) (*GetCalendarResponse, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s", calendarId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(GetCalendarResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteCalendar 删除共享日历。
+func (s *CalendarService) DeleteCalendar(calendarId string, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s", calendarId)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// PrimaryCalendar 查询主日历，应用身份时为机器人的主日历。
+func (s *CalendarService) PrimaryCalendar(userIdType string, options ...RequestOptionFunc) (*PrimaryCalendarsResponse, *Response, error) {
+	u := "calendar/v4/calendars/primary"
+	options = append(options, WithQuery(&UserIdTypeQueryOptions{UserIdType: userIdType}))
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(PrimaryCalendarsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// FreeBusyOptions 查询用户或会议室的忙闲，UserId、RoomId 任选其一，时间为 RFC3339 格式。
+type FreeBusyOptions struct {
+	TimeMin string `json:"time_min"`
+	TimeMax string `json:"time_max"`
+	UserId  string `json:"user_id,omitempty"`
+	RoomId  string `json:"room_id,omitempty"`
+}
+
+type FreeBusy struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type FreeBusyResponse struct {
+	CodeMsg
+	Data struct {
+		FreebusyList []FreeBusy `json:"freebusy_list"`
+	} `json:"data"`
+}
+
+// FreeBusy 查询一个用户或会议室的忙碌时间段。
+func (s *CalendarService) FreeBusy(userIdType string, opt *FreeBusyOptions, options ...RequestOptionFunc) (*FreeBusyResponse, *Response, error) {
+	u := "calendar/v4/freebusy/list"
+	options = append(options, WithQuery(&UserIdTypeQueryOptions{UserIdType: userIdType}))
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(FreeBusyResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UsersFreeBusy 查询多个用户在 [start, end) 内的忙碌时间段，返回 userId 到忙碌时间段的映射。
+func (s *CalendarService) UsersFreeBusy(userIdType string, userIds []string, start, end time.Time, options ...RequestOptionFunc) (map[string][]FreeBusy, error) {
+	result := make(map[string][]FreeBusy, len(userIds))
+	for _, userId := range userIds {
+		opt := &FreeBusyOptions{TimeMin: start.Format(time.RFC3339), TimeMax: end.Format(time.RFC3339), UserId: userId}
+		rsp, _, err := s.FreeBusy(userIdType, opt, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("freebusy of %s: %d %s", userId, rsp.Code, rsp.Message)
+		}
+		result[userId] = rsp.Data.FreebusyList
+	}
+	return result, nil
+}
+
+// unixString 日程接口的时间戳为秒级字符串。
+func unixString(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -0,0 +1,365 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// 参会人类型
+const (
+	AttendeeTypeUser       = "user"
+	AttendeeTypeChat       = "chat"
+	AttendeeTypeResource   = "resource"
+	AttendeeTypeThirdParty = "third_party"
+)
+
+// EventTime 全天日程使用 Date（如 2022-05-01），否则使用 Timestamp（秒级时间戳）。
+type EventTime struct {
+	Date      string `json:"date,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Timezone  string `json:"timezone,omitempty"`
+}
+
+func NewEventTime(t time.Time) *EventTime {
+	et := &EventTime{Timestamp: unixString(t)}
+	// time.Local 的名称不是有效的时区
+	if loc := t.Location().String(); loc != "Local" {
+		et.Timezone = loc
+	}
+	return et
+}
+
+func NewEventDate(date string) *EventTime {
+	return &EventTime{Date: date}
+}
+
+// Time 转换为 time.Time，全天日程为当天 0 点。
+func (t *EventTime) Time() (time.Time, error) {
+	if len(t.Date) > 0 {
+		loc := time.Local
+		if len(t.Timezone) > 0 {
+			if l, err := time.LoadLocation(t.Timezone); err == nil {
+				loc = l
+			}
+		}
+		return time.ParseInLocation("2006-01-02", t.Date, loc)
+	}
+	sec, err := strconv.ParseInt(t.Timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid event timestamp %q", t.Timestamp)
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// Vchat 视频会议，VcType 为 vc（飞书视频会议）、third_party、no_meeting 等。
+type Vchat struct {
+	VcType      string `json:"vc_type,omitempty"`
+	IconType    string `json:"icon_type,omitempty"`
+	Description string `json:"description,omitempty"`
+	MeetingUrl  string `json:"meeting_url,omitempty"`
+}
+
+type EventLocation struct {
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+}
+
+// Reminder 日程开始前 Minutes 分钟提醒，负数为开始后。
+type Reminder struct {
+	Minutes int `json:"minutes"`
+}
+
+// CalendarEvent 日程，更新时只发送不为空的字段。
+// Recurrence 为 RFC5545 的 RRULE，如 FREQ=WEEKLY;INTERVAL=1;BYDAY=MO;COUNT=10。
+type CalendarEvent struct {
+	EventId             string         `json:"event_id,omitempty"`
+	OrganizerCalendarId string         `json:"organizer_calendar_id,omitempty"`
+	Summary             string         `json:"summary,omitempty"`
+	Description         string         `json:"description,omitempty"`
+	NeedNotification    *bool          `json:"need_notification,omitempty"`
+	StartTime           *EventTime     `json:"start_time,omitempty"`
+	EndTime             *EventTime     `json:"end_time,omitempty"`
+	Vchat               *Vchat         `json:"vchat,omitempty"`
+	Visibility          string         `json:"visibility,omitempty"`       // default、public、private
+	AttendeeAbility     string         `json:"attendee_ability,omitempty"` // none、can_see_others、can_invite_others、can_modify_event
+	FreeBusyStatus      string         `json:"free_busy_status,omitempty"` // busy、free
+	Location            *EventLocation `json:"location,omitempty"`
+	Color               int            `json:"color,omitempty"`
+	Reminders           []Reminder     `json:"reminders,omitempty"`
+	Recurrence          string         `json:"recurrence,omitempty"`
+	Status              string         `json:"status,omitempty"` // tentative、confirmed、cancelled
+	IsException         bool           `json:"is_exception,omitempty"`
+	RecurringEventId    string         `json:"recurring_event_id,omitempty"`
+	CreateTime          string         `json:"create_time,omitempty"`
+}
+
+type EventResponse struct {
+	CodeMsg
+	Data struct {
+		Event CalendarEvent `json:"event"`
+	} `json:"data"`
+}
+
+// ListEventsOptions StartTime、EndTime 为秒级时间戳，和 SyncToken 不能同时使用。
+type ListEventsOptions struct {
+	PageSize  int    `url:"page_size,omitempty"`
+	PageToken string `url:"page_token,omitempty"`
+	SyncToken string `url:"sync_token,omitempty"`
+	StartTime string `url:"start_time,omitempty"`
+	EndTime   string `url:"end_time,omitempty"`
+}
+
+type ListEventsResponse struct {
+	CodeMsg
+	Data struct {
+		HasMore   bool            `json:"has_more"`
+		PageToken string          `json:"page_token"`
+		SyncToken string          `json:"sync_token"`
+		Items     []CalendarEvent `json:"items"`
+	} `json:"data"`
+}
+
+type DeleteEventOptions struct {
+	NeedNotification bool `url:"need_notification"`
+}
+
+// CreateEvent 创建日程。
+func (s *CalendarService) CreateEvent(calendarId string, opt *CalendarEvent, options ...RequestOptionFunc) (*EventResponse, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s/events", calendarId)
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(EventResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// GetEvent 查询日程。
+func (s *CalendarService) GetEvent(calendarId, eventId string, options ...RequestOptionFunc) (*EventResponse, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s/events/%s", calendarId, eventId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(EventResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// PatchEvent 更新日程，只更新 opt 中不为空的字段。
+func (s *CalendarService) PatchEvent(calendarId, eventId string, opt *CalendarEvent, options ...RequestOptionFunc) (*EventResponse, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s/events/%s", calendarId, eventId)
+
+	req, err := s.client.NewServerRequest(http.MethodPatch, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(EventResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteEvent 删除日程，needNotification 为 true 时通知参会人。
+func (s *CalendarService) DeleteEvent(calendarId, eventId string, needNotification bool, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s/events/%s", calendarId, eventId)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, &DeleteEventOptions{NeedNotification: needNotification}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListEvents 查询日历中的日程。
+func (s *CalendarService) ListEvents(calendarId string, opt *ListEventsOptions, options ...RequestOptionFunc) (*ListEventsResponse, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s/events", calendarId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListEventsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// SyncEvents 增量同步日程，syncToken 为空时全量同步。返回变更的日程（删除的日程 Status 为 cancelled）
+// 和下次同步使用的 syncToken。
+func (s *CalendarService) SyncEvents(calendarId, syncToken string, options ...RequestOptionFunc) ([]CalendarEvent, string, error) {
+	opt := &ListEventsOptions{SyncToken: syncToken}
+
+	var events []CalendarEvent
+	for {
+		rsp, _, err := s.ListEvents(calendarId, opt, options...)
+		if err != nil {
+			return nil, "", err
+		}
+		if rsp.Code != 0 {
+			return nil, "", errors.Errorf("sync events of %s: %d %s", calendarId, rsp.Code, rsp.Message)
+		}
+		events = append(events, rsp.Data.Items...)
+		if len(rsp.Data.SyncToken) > 0 {
+			syncToken = rsp.Data.SyncToken
+		}
+		if !rsp.Data.HasMore || len(rsp.Data.PageToken) == 0 {
+			return events, syncToken, nil
+		}
+		opt.PageToken = rsp.Data.PageToken
+	}
+}
+
+// Attendee 参会人，Type 为 user 时使用 UserId，chat 使用 ChatId，resource（会议室）使用 RoomId，
+// third_party（外部邮箱）使用 ThirdPartyEmail。
+type Attendee struct {
+	Type            string `json:"type"`
+	AttendeeId      string `json:"attendee_id,omitempty"`
+	RsvpStatus      string `json:"rsvp_status,omitempty"` // needs_action、accept、tentative、decline、removed
+	IsOptional      bool   `json:"is_optional,omitempty"`
+	IsOrganizer     bool   `json:"is_organizer,omitempty"`
+	IsExternal      bool   `json:"is_external,omitempty"`
+	DisplayName     string `json:"display_name,omitempty"`
+	UserId          string `json:"user_id,omitempty"`
+	ChatId          string `json:"chat_id,omitempty"`
+	RoomId          string `json:"room_id,omitempty"`
+	ThirdPartyEmail string `json:"third_party_email,omitempty"`
+	OperateId       string `json:"operate_id,omitempty"`
+}
+
+func NewUserAttendee(userId string) Attendee {
+	return Attendee{Type: AttendeeTypeUser, UserId: userId}
+}
+
+func NewChatAttendee(chatId string) Attendee {
+	return Attendee{Type: AttendeeTypeChat, ChatId: chatId}
+}
+
+func NewRoomAttendee(roomId string) Attendee {
+	return Attendee{Type: AttendeeTypeResource, RoomId: roomId}
+}
+
+func NewEmailAttendee(email string) Attendee {
+	return Attendee{Type: AttendeeTypeThirdParty, ThirdPartyEmail: email}
+}
+
+type CreateAttendeesOptions struct {
+	Attendees        []Attendee `json:"attendees"`
+	NeedNotification bool       `json:"need_notification"`
+}
+
+type AttendeesResponse struct {
+	CodeMsg
+	Data struct {
+		Attendees []Attendee `json:"attendees"`
+	} `json:"data"`
+}
+
+type ListAttendeesOptions struct {
+	UserIdType string `url:"user_id_type,omitempty"`
+	PageSize   int    `url:"page_size,omitempty"`
+	PageToken  string `url:"page_token,omitempty"`
+}
+
+type ListAttendeesResponse struct {
+	CodeMsg
+	Data struct {
+		HasMore   bool       `json:"has_more"`
+		PageToken string     `json:"page_token"`
+		Items     []Attendee `json:"items"`
+	} `json:"data"`
+}
+
+type DeleteAttendeesOptions struct {
+	AttendeeIds      []string `json:"attendee_ids"`
+	NeedNotification bool     `json:"need_notification"`
+}
+
+// CreateAttendees 添加参会人，包括用户、群、会议室和外部邮箱。
+func (s *CalendarService) CreateAttendees(calendarId, eventId, userIdType string, opt *CreateAttendeesOptions, options ...RequestOptionFunc) (*AttendeesResponse, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s/events/%s/attendees", calendarId, eventId)
+	options = append(options, WithQuery(&UserIdTypeQueryOptions{UserIdType: userIdType}))
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(AttendeesResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListAttendees 查询参会人。
+func (s *CalendarService) ListAttendees(calendarId, eventId string, opt *ListAttendeesOptions, options ...RequestOptionFunc) (*ListAttendeesResponse, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s/events/%s/attendees", calendarId, eventId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListAttendeesResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteAttendees 删除参会人，AttendeeIds 为参会人的 AttendeeId。
+func (s *CalendarService) DeleteAttendees(calendarId, eventId string, opt *DeleteAttendeesOptions, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := fmt.Sprintf("calendar/v4/calendars/%s/events/%s/attendees/batch_delete", calendarId, eventId)
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
@@ -0,0 +1,133 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testCalendarId = "feishu.cn_xxxxxxxxxx@group.calendar.feishu.cn"
+
+func TestCalendarService_Events(t *testing.T) {
+	Convey("test CalendarService_Events", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		eventsPath := "/open-apis/calendar/v4/calendars/" + testCalendarId + "/events"
+		mux.HandleFunc(eventsPath, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				testBody(t, r, `{"summary":"release review","start_time":{"timestamp":"1651395600","timezone":"Asia/Shanghai"},"end_time":{"timestamp":"1651399200","timezone":"Asia/Shanghai"},"vchat":{"vc_type":"vc"},"reminders":[{"minutes":5}],"recurrence":"FREQ=WEEKLY;INTERVAL=1;BYDAY=MO"}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"event": {"event_id": "00592a0e-7edf-4678-bc9d-1b77383ef08e_0", "summary": "release review", "start_time": {"timestamp": "1651395600", "timezone": "Asia/Shanghai"}, "recurrence": "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO"}}}`)
+			case http.MethodGet:
+				switch r.URL.Query().Get("page_token") {
+				case "":
+					testParams(t, r, "sync_token=s1")
+					fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"has_more": true, "page_token": "p2", "items": [{"event_id": "e1", "status": "confirmed"}]}}`)
+				case "p2":
+					testParams(t, r, "page_token=p2&sync_token=s1")
+					fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "sync_token": "s2", "items": [{"event_id": "e2", "status": "cancelled"}]}}`)
+				}
+			}
+		})
+		mux.HandleFunc(eventsPath+"/00592a0e-7edf-4678-bc9d-1b77383ef08e_0", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPatch:
+				testBody(t, r, `{"summary":"release review v2","reminders":[{"minutes":15}]}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"event": {"event_id": "00592a0e-7edf-4678-bc9d-1b77383ef08e_0", "summary": "release review v2"}}}`)
+			case http.MethodGet:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"event": {"event_id": "00592a0e-7edf-4678-bc9d-1b77383ef08e_0", "summary": "release review v2"}}}`)
+			case http.MethodDelete:
+				testParams(t, r, "need_notification=true")
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+			}
+		})
+
+		loc := time.FixedZone("Asia/Shanghai", 8*3600)
+		start := time.Date(2022, 5, 1, 17, 0, 0, 0, loc)
+		event := &CalendarEvent{
+			Summary:    "release review",
+			StartTime:  NewEventTime(start),
+			EndTime:    NewEventTime(start.Add(time.Hour)),
+			Vchat:      &Vchat{VcType: "vc"},
+			Reminders:  []Reminder{{Minutes: 5}},
+			Recurrence: "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO",
+		}
+		created, _, err := client.Calendar.CreateEvent(testCalendarId, event)
+		So(err, ShouldBeNil)
+		eventId := created.Data.Event.EventId
+		So(eventId, ShouldEqual, "00592a0e-7edf-4678-bc9d-1b77383ef08e_0")
+		startTime, err := created.Data.Event.StartTime.Time()
+		So(err, ShouldBeNil)
+		So(startTime.Equal(start), ShouldBeTrue)
+
+		patched, _, err := client.Calendar.PatchEvent(testCalendarId, eventId, &CalendarEvent{Summary: "release review v2", Reminders: []Reminder{{Minutes: 15}}})
+		So(err, ShouldBeNil)
+		So(patched.Data.Event.Summary, ShouldEqual, "release review v2")
+
+		got, _, err := client.Calendar.GetEvent(testCalendarId, eventId)
+		So(err, ShouldBeNil)
+		So(got.Data.Event.Summary, ShouldEqual, "release review v2")
+
+		events, syncToken, err := client.Calendar.SyncEvents(testCalendarId, "s1")
+		So(err, ShouldBeNil)
+		So(events, ShouldHaveLength, 2)
+		So(events[1].Status, ShouldEqual, "cancelled")
+		So(syncToken, ShouldEqual, "s2")
+
+		deleted, _, err := client.Calendar.DeleteEvent(testCalendarId, eventId, true)
+		So(err, ShouldBeNil)
+		So(deleted.Code, ShouldEqual, 0)
+	})
+}
+
+func TestCalendarService_Attendees(t *testing.T) {
+	Convey("test CalendarService_Attendees", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		attendeesPath := "/open-apis/calendar/v4/calendars/" + testCalendarId + "/events/e1/attendees"
+		mux.HandleFunc(attendeesPath, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				testParams(t, r, "user_id_type=open_id")
+				testBody(t, r, `{"attendees":[{"type":"user","user_id":"ou_1"},{"type":"chat","chat_id":"oc_1"},{"type":"resource","room_id":"omm_1"},{"type":"third_party","third_party_email":"guest@example.com"}],"need_notification":true}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"attendees": [{"type": "user", "attendee_id": "user_1", "rsvp_status": "needs_action", "user_id": "ou_1"}, {"type": "resource", "attendee_id": "resource_1", "room_id": "omm_1"}]}}`)
+			case http.MethodGet:
+				testParams(t, r, "user_id_type=open_id")
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "items": [{"type": "user", "attendee_id": "user_1", "rsvp_status": "accept", "user_id": "ou_1"}]}}`)
+			}
+		})
+		mux.HandleFunc(attendeesPath+"/batch_delete", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"attendee_ids":["user_1"],"need_notification":false}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+		})
+
+		opt := &CreateAttendeesOptions{
+			Attendees: []Attendee{
+				NewUserAttendee("ou_1"),
+				NewChatAttendee("oc_1"),
+				NewRoomAttendee("omm_1"),
+				NewEmailAttendee("guest@example.com"),
+			},
+			NeedNotification: true,
+		}
+		created, _, err := client.Calendar.CreateAttendees(testCalendarId, "e1", "open_id", opt)
+		So(err, ShouldBeNil)
+		So(created.Data.Attendees, ShouldHaveLength, 2)
+
+		list, _, err := client.Calendar.ListAttendees(testCalendarId, "e1", &ListAttendeesOptions{UserIdType: "open_id"})
+		So(err, ShouldBeNil)
+		So(list.Data.Items[0].RsvpStatus, ShouldEqual, "accept")
+
+		deleted, _, err := client.Calendar.DeleteAttendees(testCalendarId, "e1", &DeleteAttendeesOptions{AttendeeIds: []string{"user_1"}})
+		So(err, ShouldBeNil)
+		So(deleted.Code, ShouldEqual, 0)
+	})
+}
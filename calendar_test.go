@@ -0,0 +1,97 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCalendarService_Calendars(t *testing.T) {
+	Convey("test CalendarService_Calendars", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/calendar/v4/calendars", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				testParams(t, r, "page_size=50&sync_token=ListCalendarsSyncToken_1632452910")
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "page_token": "", "sync_token": "ListCalendarsSyncToken_1632452911", "calendar_list": [{"calendar_id": "feishu.cn_xxxxxxxxxx@group.calendar.feishu.cn", "summary": "ops", "permissions": "private", "type": "shared", "role": "owner"}]}}`)
+			case http.MethodPost:
+				testBody(t, r, `{"summary":"ops","description":"on-call","permissions":"public"}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"calendar": {"calendar_id": "feishu.cn_xxxxxxxxxx@group.calendar.feishu.cn", "summary": "ops", "role": "owner"}}}`)
+			}
+		})
+		mux.HandleFunc("/open-apis/calendar/v4/calendars/feishu.cn_xxxxxxxxxx@group.calendar.feishu.cn", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"calendar_id": "feishu.cn_xxxxxxxxxx@group.calendar.feishu.cn", "summary": "ops"}}`)
+			case http.MethodDelete:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+			}
+		})
+		mux.HandleFunc("/open-apis/calendar/v4/calendars/primary", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "user_id_type=open_id")
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"calendars": [{"calendar": {"calendar_id": "feishu.cn_primary@group.calendar.feishu.cn", "type": "primary"}, "user_id": "ou_xxx"}]}}`)
+		})
+
+		list, _, err := client.Calendar.ListCalendars(&ListCalendarsOptions{PageSize: 50, SyncToken: "ListCalendarsSyncToken_1632452910"})
+		So(err, ShouldBeNil)
+		So(list.Data.CalendarList, ShouldHaveLength, 1)
+		So(list.Data.SyncToken, ShouldEqual, "ListCalendarsSyncToken_1632452911")
+
+		created, _, err := client.Calendar.CreateCalendar(&Calendar{Summary: "ops", Description: "on-call", Permissions: "public"})
+		So(err, ShouldBeNil)
+		calendarId := created.Data.Calendar.CalendarId
+		So(calendarId, ShouldEqual, "feishu.cn_xxxxxxxxxx@group.calendar.feishu.cn")
+
+		got, _, err := client.Calendar.GetCalendar(calendarId)
+		So(err, ShouldBeNil)
+		So(got.Data.Summary, ShouldEqual, "ops")
+
+		deleted, _, err := client.Calendar.DeleteCalendar(calendarId)
+		So(err, ShouldBeNil)
+		So(deleted.Code, ShouldEqual, 0)
+
+		primary, _, err := client.Calendar.PrimaryCalendar("open_id")
+		So(err, ShouldBeNil)
+		So(primary.Data.Calendars[0].Calendar.Type, ShouldEqual, "primary")
+	})
+}
+
+func TestCalendarService_UsersFreeBusy(t *testing.T) {
+	Convey("test CalendarService_UsersFreeBusy", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		start := time.Date(2022, 5, 1, 9, 0, 0, 0, time.UTC)
+		end := start.Add(8 * time.Hour)
+		mux.HandleFunc("/open-apis/calendar/v4/freebusy/list", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "user_id_type=user_id")
+			opt := new(FreeBusyOptions)
+			if err := json.NewDecoder(r.Body).Decode(opt); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if opt.TimeMin != "2022-05-01T09:00:00Z" || opt.TimeMax != "2022-05-01T17:00:00Z" {
+				t.Errorf("freebusy time range: %s - %s", opt.TimeMin, opt.TimeMax)
+			}
+			if opt.UserId == "u1" {
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"freebusy_list": [{"start_time": "2022-05-01T10:00:00Z", "end_time": "2022-05-01T11:00:00Z"}]}}`)
+				return
+			}
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"freebusy_list": []}}`)
+		})
+
+		busy, err := client.Calendar.UsersFreeBusy("user_id", []string{"u1", "u2"}, start, end)
+		So(err, ShouldBeNil)
+		So(busy["u1"], ShouldResemble, []FreeBusy{{StartTime: "2022-05-01T10:00:00Z", EndTime: "2022-05-01T11:00:00Z"}})
+		So(busy["u2"], ShouldBeEmpty)
+	})
+}
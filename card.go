@@ -0,0 +1,153 @@
+package feishu
+
+import "strings"
+
+// 消息卡片的元素，可放入 BotCardOption.Elements 和 AppCardOption.Elements。
+
+const (
+	TextTagPlain  = "plain_text"
+	TextTagLarkMd = "lark_md"
+
+	// 卡片标题颜色
+	TemplateBlue      = "blue"
+	TemplateWathet    = "wathet"
+	TemplateTurquoise = "turquoise"
+	TemplateGreen     = "green"
+	TemplateYellow    = "yellow"
+	TemplateOrange    = "orange"
+	TemplateRed       = "red"
+	TemplateCarmine   = "carmine"
+	TemplateViolet    = "violet"
+	TemplatePurple    = "purple"
+	TemplateIndigo    = "indigo"
+	TemplateGrey      = "grey"
+)
+
+// MarkdownElement 卡片 markdown 元素。
+type MarkdownElement struct {
+	Tag       string `json:"tag"`
+	Content   string `json:"content"`
+	TextAlign string `json:"text_align,omitempty"`
+}
+
+func NewMarkdownElement(content string) *MarkdownElement {
+	return &MarkdownElement{Tag: "markdown", Content: content}
+}
+
+type FieldOption struct {
+	IsShort bool        `json:"is_short"`
+	Text    TitleOption `json:"text"`
+}
+
+// DivElement 文本元素，Fields 可以双列展示。
+type DivElement struct {
+	Tag    string        `json:"tag"`
+	Text   *TitleOption  `json:"text,omitempty"`
+	Fields []FieldOption `json:"fields,omitempty"`
+}
+
+func NewDivElement(text *TitleOption, fields ...FieldOption) *DivElement {
+	return &DivElement{Tag: "div", Text: text, Fields: fields}
+}
+
+// HrElement 分割线。
+type HrElement struct {
+	Tag string `json:"tag"`
+}
+
+func NewHrElement() *HrElement {
+	return &HrElement{Tag: "hr"}
+}
+
+// NoteElement 备注，Elements 为文本或图片。
+type NoteElement struct {
+	Tag      string        `json:"tag"`
+	Elements []interface{} `json:"elements"`
+}
+
+func NewNoteElement(elements ...interface{}) *NoteElement {
+	return &NoteElement{Tag: "note", Elements: elements}
+}
+
+// ImageElement 图片，ImgKey 通过上传图片获得。
+type ImageElement struct {
+	Tag    string      `json:"tag"`
+	ImgKey string      `json:"img_key"`
+	Alt    TitleOption `json:"alt"`
+}
+
+func NewImageElement(imgKey, alt string) *ImageElement {
+	return &ImageElement{Tag: "img", ImgKey: imgKey, Alt: TitleOption{Tag: TextTagPlain, Content: alt}}
+}
+
+// ButtonElement 按钮，设置 URL 时点击跳转，否则回传 Value。
+type ButtonElement struct {
+	Tag   string                 `json:"tag"`
+	Text  TitleOption            `json:"text"`
+	URL   string                 `json:"url,omitempty"`
+	Type  string                 `json:"type,omitempty"`
+	Value map[string]interface{} `json:"value,omitempty"`
+}
+
+// NewLinkButton 跳转链接的按钮，buttonType 为 default、primary、danger。
+func NewLinkButton(text, url, buttonType string) *ButtonElement {
+	return &ButtonElement{Tag: "button", Text: TitleOption{Tag: TextTagPlain, Content: text}, URL: url, Type: buttonType}
+}
+
+// ActionElement 交互元素，Actions 为按钮等。
+type ActionElement struct {
+	Tag     string        `json:"tag"`
+	Actions []interface{} `json:"actions"`
+	Layout  string        `json:"layout,omitempty"`
+}
+
+func NewActionElement(actions ...interface{}) *ActionElement {
+	return &ActionElement{Tag: "action", Actions: actions}
+}
+
+// ColumnSetElement 多列布局，可用于展示表格。
+type ColumnSetElement struct {
+	Tag             string           `json:"tag"`
+	FlexMode        string           `json:"flex_mode,omitempty"`
+	BackgroundStyle string           `json:"background_style,omitempty"`
+	Columns         []*ColumnElement `json:"columns"`
+}
+
+func NewColumnSetElement(columns ...*ColumnElement) *ColumnSetElement {
+	return &ColumnSetElement{Tag: "column_set", FlexMode: "none", BackgroundStyle: "default", Columns: columns}
+}
+
+// ColumnElement 列，Width 为 auto、weighted，weighted 时按 Weight 分配宽度。
+type ColumnElement struct {
+	Tag           string        `json:"tag"`
+	Width         string        `json:"width,omitempty"`
+	Weight        int           `json:"weight,omitempty"`
+	VerticalAlign string        `json:"vertical_align,omitempty"`
+	Elements      []interface{} `json:"elements"`
+}
+
+func NewColumnElement(weight int, elements ...interface{}) *ColumnElement {
+	return &ColumnElement{Tag: "column", Width: "weighted", Weight: weight, VerticalAlign: "top", Elements: elements}
+}
+
+// ToAppCard 转换为应用消息使用的卡片。
+func (c *BotCardOption) ToAppCard() *AppCardOption {
+	return &AppCardOption{Config: c.Config, Header: c.Header, Elements: c.Elements}
+}
+
+// EscapeMarkdown 转义卡片 markdown、lark_md 中有特殊含义的字符。
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`~`, `\~`,
+	"`", "\\`",
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `&lt;`,
+	`>`, `&gt;`,
+)
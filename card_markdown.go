@@ -0,0 +1,299 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eyotang/go-feishu/internal/markdown"
+)
+
+const (
+	// 卡片消息体最大 30KB，留出消息其他字段的空间
+	defaultMarkdownCardSize     = 28 * 1024
+	defaultMarkdownCardElements = 50
+)
+
+var inlineImageRe = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+
+// MarkdownImageResolver 把图片地址转换为卡片使用的 img_key，如下载后上传图片。
+type MarkdownImageResolver func(url, alt string) (imgKey string, err error)
+
+type markdownCardConverter struct {
+	title       string
+	template    string
+	size        int
+	maxElements int
+	resolver    MarkdownImageResolver
+}
+
+type MarkdownCardOptionFunc func(c *markdownCardConverter)
+
+// WithMarkdownCardHeader 卡片标题和颜色，标题为空时使用第一个一级标题。
+func WithMarkdownCardHeader(title, template string) MarkdownCardOptionFunc {
+	return func(c *markdownCardConverter) {
+		c.title, c.template = title, template
+	}
+}
+
+// WithMarkdownCardLimit 每张卡片的最大字节数和元素数，超过时拆分为多张卡片。
+func WithMarkdownCardLimit(size, elements int) MarkdownCardOptionFunc {
+	return func(c *markdownCardConverter) {
+		if size > 0 {
+			c.size = size
+		}
+		if elements > 0 {
+			c.maxElements = elements
+		}
+	}
+}
+
+// WithMarkdownImageResolver 图片转换为卡片的图片元素，未设置时图片显示为链接。
+func WithMarkdownImageResolver(resolver MarkdownImageResolver) MarkdownCardOptionFunc {
+	return func(c *markdownCardConverter) {
+		c.resolver = resolver
+	}
+}
+
+// MarkdownToCards 把 markdown 转换为卡片：文本、列表、代码块为 markdown 元素，表格为 column_set，
+// 引用为 note，分割线为 hr。内容过大时拆分为多张卡片，标题加上序号。
+// 应用消息使用 BotCardOption.ToAppCard 转换。
+func MarkdownToCards(md string, options ...MarkdownCardOptionFunc) ([]*BotCardOption, error) {
+	c := &markdownCardConverter{
+		template:    TemplateBlue,
+		size:        defaultMarkdownCardSize,
+		maxElements: defaultMarkdownCardElements,
+	}
+	for _, fn := range options {
+		if fn != nil {
+			fn(c)
+		}
+	}
+
+	blocks := markdown.Parse(md)
+	if len(c.title) == 0 && len(blocks) > 0 && blocks[0].Kind == markdown.KindHeading && blocks[0].Level == 1 {
+		c.title = blocks[0].Text
+		blocks = blocks[1:]
+	}
+
+	units, err := c.convert(blocks)
+	if err != nil {
+		return nil, err
+	}
+	return c.split(units), nil
+}
+
+// cardUnit 一个元素，表格行记录表头，拆分后在新卡片中重复表头。
+type cardUnit struct {
+	element interface{}
+	size    int
+	header  *cardUnit
+}
+
+func newCardUnit(element interface{}) *cardUnit {
+	buf, _ := json.Marshal(element)
+	return &cardUnit{element: element, size: len(buf) + 1}
+}
+
+func (c *markdownCardConverter) convert(blocks []markdown.Block) ([]*cardUnit, error) {
+	var (
+		units []*cardUnit
+		text  []string
+	)
+	// 连续的文本合并为一个 markdown 元素
+	flush := func() {
+		if len(text) == 0 {
+			return
+		}
+		for _, chunk := range c.chunks(strings.Join(text, "\n"), false, "") {
+			units = append(units, newCardUnit(NewMarkdownElement(chunk)))
+		}
+		text = nil
+	}
+
+	for _, b := range blocks {
+		switch b.Kind {
+		case markdown.KindParagraph:
+			text = append(text, cardInline(b.Text))
+		case markdown.KindHeading:
+			text = append(text, "**"+cardInline(b.Text)+"**")
+		case markdown.KindList:
+			text = append(text, cardList(b.Items))
+		case markdown.KindCode:
+			flush()
+			for _, chunk := range c.chunks(b.Text, true, b.Lang) {
+				units = append(units, newCardUnit(NewMarkdownElement(chunk)))
+			}
+		case markdown.KindQuote:
+			flush()
+			for _, chunk := range c.chunks(cardInline(b.Text), false, "") {
+				units = append(units, newCardUnit(NewNoteElement(&TitleOption{Tag: TextTagLarkMd, Content: chunk})))
+			}
+		case markdown.KindHr:
+			flush()
+			units = append(units, newCardUnit(NewHrElement()))
+		case markdown.KindImage:
+			if c.resolver == nil {
+				text = append(text, cardInline(fmt.Sprintf("![%s](%s)", b.Alt, b.URL)))
+				continue
+			}
+			flush()
+			imgKey, err := c.resolver(b.URL, b.Alt)
+			if err != nil {
+				return nil, err
+			}
+			units = append(units, newCardUnit(NewImageElement(imgKey, b.Alt)))
+		case markdown.KindTable:
+			flush()
+			units = append(units, cardTable(&b)...)
+		}
+	}
+	flush()
+	return units, nil
+}
+
+// chunks 按行拆分过长的内容，代码块的每段都包含代码块标记。
+func (c *markdownCardConverter) chunks(content string, code bool, lang string) []string {
+	max := c.size / 2
+	fence := func(s string) string { return s }
+	if code {
+		fence = func(s string) string { return "```" + lang + "\n" + s + "\n```" }
+	}
+
+	var (
+		chunks []string
+		cur    []string
+		size   int
+	)
+	for _, line := range strings.Split(content, "\n") {
+		for len(line) > max {
+			// 单行过长时按字符截断
+			cut := max
+			for cut > 1 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			if len(cur) > 0 {
+				chunks = append(chunks, fence(strings.Join(cur, "\n")))
+				cur, size = nil, 0
+			}
+			chunks = append(chunks, fence(line[:cut]))
+			line = line[cut:]
+		}
+		if size+len(line) > max && len(cur) > 0 {
+			chunks = append(chunks, fence(strings.Join(cur, "\n")))
+			cur, size = nil, 0
+		}
+		cur = append(cur, line)
+		size += len(line) + 1
+	}
+	if len(cur) > 0 {
+		chunks = append(chunks, fence(strings.Join(cur, "\n")))
+	}
+	return chunks
+}
+
+func (c *markdownCardConverter) split(units []*cardUnit) []*BotCardOption {
+	newCard := func() *BotCardOption {
+		return &BotCardOption{
+			Config: CardConfigOption{WideScreenMode: true, EnableForward: true},
+			Header: HeadOption{Title: TitleOption{Tag: TextTagPlain, Content: c.title}, Template: c.template},
+		}
+	}
+	base := newCardUnit(newCard()).size
+
+	card := newCard()
+	cards := []*BotCardOption{card}
+	size := base
+	for _, u := range units {
+		if len(card.Elements) > 0 && (size+u.size > c.size || len(card.Elements)+1 > c.maxElements) {
+			card = newCard()
+			cards = append(cards, card)
+			size = base
+			if u.header != nil && u.header != u {
+				card.Elements = append(card.Elements, u.header.element)
+				size += u.header.size
+			}
+		}
+		card.Elements = append(card.Elements, u.element)
+		size += u.size
+	}
+
+	if len(cards) > 1 {
+		for i, card := range cards {
+			card.Header.Title.Content = strings.TrimSpace(fmt.Sprintf("%s (%d/%d)", c.title, i+1, len(cards)))
+		}
+	}
+	return cards
+}
+
+// cardInline 卡片 markdown 的图片只支持 img_key，图片地址转换为链接。
+func cardInline(s string) string {
+	return inlineImageRe.ReplaceAllStringFunc(s, func(image string) string {
+		m := inlineImageRe.FindStringSubmatch(image)
+		alt := m[1]
+		if len(alt) == 0 {
+			alt = m[2]
+		}
+		return fmt.Sprintf("[%s](%s)", alt, m[2])
+	})
+}
+
+func cardList(items []markdown.ListItem) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		marker := "-"
+		if item.Ordered {
+			marker = fmt.Sprintf("%d.", item.Number)
+		}
+		text := cardInline(item.Text)
+		if item.Checked != nil {
+			if *item.Checked {
+				text = "☑ " + text
+			} else {
+				text = "☐ " + text
+			}
+		}
+		lines = append(lines, strings.Repeat("    ", item.Indent)+marker+" "+text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+var cardTextAlign = map[markdown.Align]string{
+	markdown.AlignLeft:   "left",
+	markdown.AlignCenter: "center",
+	markdown.AlignRight:  "right",
+}
+
+// cardTable 每行一个 column_set，表头加粗并使用灰色背景。
+func cardTable(b *markdown.Block) []*cardUnit {
+	row := func(cells []string, bold bool) *ColumnSetElement {
+		columns := make([]*ColumnElement, len(cells))
+		for i, cell := range cells {
+			content := cardInline(cell)
+			if bold && len(content) > 0 {
+				content = "**" + content + "**"
+			}
+			md := NewMarkdownElement(content)
+			if i < len(b.Align) {
+				md.TextAlign = cardTextAlign[b.Align[i]]
+			}
+			columns[i] = NewColumnElement(1, md)
+		}
+		return NewColumnSetElement(columns...)
+	}
+
+	header := row(b.Header, true)
+	header.BackgroundStyle = TemplateGrey
+	headerUnit := newCardUnit(header)
+	headerUnit.header = headerUnit
+
+	units := []*cardUnit{headerUnit}
+	for _, cells := range b.Rows {
+		u := newCardUnit(row(cells, false))
+		u.header = headerUnit
+		units = append(units, u)
+	}
+	return units
+}
@@ -0,0 +1,108 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const markdownReport = "# Nightly Report\n" +
+	"\n" +
+	"## Summary\n" +
+	"Run on **staging**, see [CI](https://ci.example.com).\n" +
+	"\n" +
+	"- [x] api\n" +
+	"- [ ] web\n" +
+	"  1. login\n" +
+	"\n" +
+	"| Case | Result |\n" +
+	"| ---- | :----: |\n" +
+	"| login | pass |\n" +
+	"| logout | fail |\n" +
+	"\n" +
+	"```\n" +
+	"panic: nil\n" +
+	"```\n" +
+	"\n" +
+	"> retried 2 times\n" +
+	"\n" +
+	"---\n" +
+	"\n" +
+	"![trend](https://example.com/trend.png)\n"
+
+func TestMarkdownToCards(t *testing.T) {
+	Convey("test MarkdownToCards", t, func() {
+		cards, err := MarkdownToCards(markdownReport)
+		So(err, ShouldBeNil)
+		So(cards, ShouldHaveLength, 1)
+
+		card := cards[0]
+		So(card.Header.Title.Content, ShouldEqual, "Nightly Report")
+		So(card.Header.Template, ShouldEqual, TemplateBlue)
+		So(card.Elements, ShouldHaveLength, 8)
+
+		text := card.Elements[0].(*MarkdownElement)
+		So(text.Content, ShouldEqual, "**Summary**\n"+
+			"Run on **staging**, see [CI](https://ci.example.com).\n"+
+			"- ☑ api\n- ☐ web\n    1. login")
+
+		header := card.Elements[1].(*ColumnSetElement)
+		So(header.BackgroundStyle, ShouldEqual, TemplateGrey)
+		So(header.Columns[0].Elements[0].(*MarkdownElement).Content, ShouldEqual, "**Case**")
+		row := card.Elements[3].(*ColumnSetElement)
+		So(row.Columns[1].Elements[0].(*MarkdownElement).Content, ShouldEqual, "fail")
+		So(row.Columns[1].Elements[0].(*MarkdownElement).TextAlign, ShouldEqual, "center")
+
+		So(card.Elements[4].(*MarkdownElement).Content, ShouldEqual, "```\npanic: nil\n```")
+		So(card.Elements[5], ShouldHaveSameTypeAs, &NoteElement{})
+		So(card.Elements[6], ShouldHaveSameTypeAs, &HrElement{})
+		So(card.Elements[7].(*MarkdownElement).Content, ShouldEqual, "[trend](https://example.com/trend.png)")
+
+		app := card.ToAppCard()
+		So(app.Elements, ShouldResemble, card.Elements)
+	})
+
+	Convey("test MarkdownToCards image resolver", t, func() {
+		cards, err := MarkdownToCards("![trend](https://example.com/trend.png)", WithMarkdownImageResolver(func(url, alt string) (string, error) {
+			return "img_v2_" + alt, nil
+		}))
+		So(err, ShouldBeNil)
+		So(cards[0].Elements[0].(*ImageElement).ImgKey, ShouldEqual, "img_v2_trend")
+
+		_, err = MarkdownToCards("![trend](https://example.com/trend.png)", WithMarkdownImageResolver(func(url, alt string) (string, error) {
+			return "", fmt.Errorf("download %s failed", url)
+		}))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("test MarkdownToCards split", t, func() {
+		var b strings.Builder
+		b.WriteString("| Case | Result |\n| --- | --- |\n")
+		for i := 0; i < 30; i++ {
+			fmt.Fprintf(&b, "| case-%d | pass |\n", i)
+		}
+		b.WriteString("\n```go\n" + strings.Repeat("fmt.Println(\"中文\")\n", 300) + "```\n")
+
+		cards, err := MarkdownToCards(b.String(), WithMarkdownCardHeader("Report", TemplateGreen), WithMarkdownCardLimit(4096, 20))
+		So(err, ShouldBeNil)
+		So(len(cards), ShouldBeGreaterThan, 2)
+		So(cards[0].Header.Title.Content, ShouldEqual, fmt.Sprintf("Report (1/%d)", len(cards)))
+
+		// 表格拆分后在新卡片中重复表头
+		So(cards[1].Elements[0].(*ColumnSetElement).BackgroundStyle, ShouldEqual, TemplateGrey)
+		for _, card := range cards {
+			buf, _ := json.Marshal(card)
+			So(len(buf), ShouldBeLessThanOrEqualTo, 4096)
+			So(len(card.Elements), ShouldBeLessThanOrEqualTo, 20)
+			for _, e := range card.Elements {
+				if md, ok := e.(*MarkdownElement); ok && strings.Contains(md.Content, "Println") {
+					So(md.Content, ShouldStartWith, "```go\n")
+					So(md.Content, ShouldEndWith, "\n```")
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,373 @@
+package feishu
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// 卡片搭建工具导出的变量占位符，如 ${name}
+var cardVariablePattern = regexp.MustCompile(`\$\{([A-Za-z0-9_.\-]+)\}`)
+
+// CardRaw 不做 markdown 转义的变量值，如已经是 markdown 的内容。
+type CardRaw string
+
+// TemplateCardContent 使用卡片搭建工具中的模板发送，服务端渲染。
+type TemplateCardContent struct {
+	Type string           `json:"type"`
+	Data TemplateCardData `json:"data"`
+}
+
+type TemplateCardData struct {
+	TemplateId          string                 `json:"template_id"`
+	TemplateVersionName string                 `json:"template_version_name,omitempty"`
+	TemplateVariable    map[string]interface{} `json:"template_variable,omitempty"`
+}
+
+// UnresolvedVariablesError 渲染时缺少变量。
+type UnresolvedVariablesError struct {
+	Template string
+	Names    []string
+}
+
+func (e *UnresolvedVariablesError) Error() string {
+	return fmt.Sprintf("card template %s: unresolved variables %s", e.Template, strings.Join(e.Names, ", "))
+}
+
+// CardTemplate 卡片模板。
+//
+// 卡片 JSON 在客户端渲染：字符串中的 ${name} 替换为变量，包含 {{ }} 的字符串按 text/template 执行，
+// markdown、lark_md 的内容中变量会做 markdown 转义，CardRaw 类型的值除外。
+//
+// TemplateId 不为空时为服务端模板，渲染为 {"type":"template","data":{...}}，变量原样发送。
+type CardTemplate struct {
+	Name string
+
+	TemplateId          string
+	TemplateVersionName string
+	// 服务端模板的变量，值为空的变量必须在渲染时提供
+	Variables map[string]interface{}
+
+	card interface{}
+	tmpl *template.Template
+}
+
+var cardTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+	// 包含 {{ }} 的字符串中的 ${name}，渲染时替换为 cardRenderer.variable
+	"cardVar": func(vars map[string]interface{}, name string) (interface{}, error) {
+		return vars[name], nil
+	},
+}
+
+// NewTemplateCard 服务端模板。
+func NewTemplateCard(templateId string, variables map[string]interface{}) *CardTemplate {
+	return &CardTemplate{Name: templateId, TemplateId: templateId, Variables: variables}
+}
+
+// ParseCardTemplate 解析卡片 JSON，支持卡片本身、{"msg_type":"interactive","card":{...}}
+// 以及 {"type":"template","data":{"template_id":...,"template_variable":{...}}}。
+func ParseCardTemplate(name string, data []byte) (*CardTemplate, error) {
+	var card interface{}
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, errors.Wrapf(err, "card template %s", name)
+	}
+
+	if m, ok := card.(map[string]interface{}); ok {
+		if inner, ok := m["card"]; ok && m["msg_type"] != nil {
+			card = inner
+			m, _ = inner.(map[string]interface{})
+		}
+		if m["type"] == "template" {
+			content := new(TemplateCardContent)
+			buf, _ := json.Marshal(m)
+			if err := json.Unmarshal(buf, content); err != nil {
+				return nil, errors.Wrapf(err, "card template %s", name)
+			}
+			if len(content.Data.TemplateId) == 0 {
+				return nil, errors.Errorf("card template %s: template_id is empty", name)
+			}
+			t := NewTemplateCard(content.Data.TemplateId, content.Data.TemplateVariable)
+			t.Name, t.TemplateVersionName = name, content.Data.TemplateVersionName
+			return t, nil
+		}
+	}
+
+	t := &CardTemplate{
+		Name: name,
+		card: card,
+		tmpl: template.New(name).Funcs(cardTemplateFuncs).Option("missingkey=error"),
+	}
+	// 预先解析，语法错误在加载时发现
+	if err := walkCardStrings(card, func(s string) error {
+		if !strings.Contains(s, "{{") || t.tmpl.Lookup(s) != nil {
+			return nil
+		}
+		// ${name} 转换为模板中的调用，变量的值不会再次替换
+		_, err := t.tmpl.New(s).Parse(cardVariablePattern.ReplaceAllString(s, `{{cardVar $$ "$1"}}`))
+		return err
+	}); err != nil {
+		return nil, errors.Wrapf(err, "card template %s", name)
+	}
+	return t, nil
+}
+
+// LoadCardTemplates 加载匹配 pattern 的文件，如 templates/*.json，模板名为去掉扩展名的文件名。
+func LoadCardTemplates(pattern string) (map[string]*CardTemplate, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]*CardTemplate, len(files))
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if templates[name], err = ParseCardTemplate(name, data); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// Render 渲染卡片，返回的内容可以作为 AppCardMessageOption.Content 或 WebhookBot.SendCard 的参数。
+func (t *CardTemplate) Render(vars map[string]interface{}) (json.RawMessage, error) {
+	var card interface{}
+	if len(t.TemplateId) > 0 {
+		content, err := t.templateContent(vars)
+		if err != nil {
+			return nil, err
+		}
+		card = content
+	} else {
+		r := &cardRenderer{vars: vars, escaped: escapeCardVariables(vars)}
+		tmpl, err := t.tmpl.Clone()
+		if err != nil {
+			return nil, err
+		}
+		r.tmpl = tmpl.Funcs(template.FuncMap{"cardVar": r.variable})
+		if card, err = r.render(t.card, false); err != nil {
+			return nil, errors.Wrapf(err, "card template %s", t.Name)
+		}
+		if len(r.unresolved) > 0 {
+			return nil, &UnresolvedVariablesError{Template: t.Name, Names: uniqueSorted(r.unresolved)}
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(card); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(bytes.TrimSpace(buf.Bytes())), nil
+}
+
+// Validate 检查变量是否完整以及模板能否执行。
+func (t *CardTemplate) Validate(vars map[string]interface{}) error {
+	_, err := t.Render(vars)
+	return err
+}
+
+// AppCardMessage 渲染后生成应用发送卡片消息的参数，用于 AppService.SendAppCardMessage。
+func (t *CardTemplate) AppCardMessage(receiveId string, vars map[string]interface{}) (*AppCardMessageOption, error) {
+	content, err := t.Render(vars)
+	if err != nil {
+		return nil, err
+	}
+	return &AppCardMessageOption{MsgType: MsgTypeInteractive, ReceiveID: receiveId, Content: string(content)}, nil
+}
+
+func (t *CardTemplate) templateContent(vars map[string]interface{}) (*TemplateCardContent, error) {
+	merged := make(map[string]interface{}, len(t.Variables)+len(vars))
+	for k, v := range t.Variables {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+
+	var unresolved []string
+	for k := range t.Variables {
+		if v := merged[k]; v == nil || v == "" {
+			unresolved = append(unresolved, k)
+		}
+	}
+	if len(unresolved) > 0 {
+		return nil, &UnresolvedVariablesError{Template: t.Name, Names: uniqueSorted(unresolved)}
+	}
+
+	return &TemplateCardContent{
+		Type: "template",
+		Data: TemplateCardData{
+			TemplateId:          t.TemplateId,
+			TemplateVersionName: t.TemplateVersionName,
+			TemplateVariable:    merged,
+		},
+	}, nil
+}
+
+type cardRenderer struct {
+	tmpl       *template.Template
+	vars       map[string]interface{}
+	escaped    map[string]interface{}
+	unresolved []string
+}
+
+func (r *cardRenderer) render(node interface{}, markdown bool) (interface{}, error) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		tag, _ := v["tag"].(string)
+		out := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			var err error
+			md := k == "content" && (tag == "markdown" || tag == TextTagLarkMd)
+			if out[k], err = r.render(child, md); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			var err error
+			if out[i], err = r.render(child, false); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case string:
+		return r.renderString(v, markdown)
+	default:
+		return v, nil
+	}
+}
+
+// renderString 包含 {{ }} 的字符串按 text/template 执行，否则替换 ${name}，只替换一次。
+func (r *cardRenderer) renderString(s string, markdown bool) (interface{}, error) {
+	vars := r.vars
+	if markdown {
+		vars = r.escaped
+	}
+
+	if tmpl := r.tmpl.Lookup(s); tmpl != nil && strings.Contains(s, "{{") {
+		buf := new(bytes.Buffer)
+		if err := tmpl.Execute(buf, vars); err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	}
+
+	// 整个字符串就是一个变量时保留变量的类型，如数组、数字
+	if m := cardVariablePattern.FindStringSubmatch(s); m != nil && m[0] == s {
+		return r.variable(vars, m[1])
+	}
+
+	return cardVariablePattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		v, _ := r.variable(vars, placeholder[2:len(placeholder)-1])
+		return fmt.Sprint(v)
+	}), nil
+}
+
+// variable 返回变量的值，缺少的变量记录在 unresolved 中并保留占位符。
+func (r *cardRenderer) variable(vars map[string]interface{}, name string) (interface{}, error) {
+	v, ok := vars[name]
+	if !ok {
+		r.unresolved = append(r.unresolved, name)
+		return "${" + name + "}", nil
+	}
+	if raw, ok := v.(CardRaw); ok {
+		return string(raw), nil
+	}
+	return v, nil
+}
+
+// escapeCardVariables 返回转义后的变量，只转义字符串、map 和 slice 中的字符串。
+func escapeCardVariables(vars map[string]interface{}) map[string]interface{} {
+	escaped := make(map[string]interface{}, len(vars))
+	for k, v := range vars {
+		escaped[k] = escapeCardValue(v)
+	}
+	return escaped
+}
+
+func escapeCardValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return EscapeMarkdown(v)
+	case CardRaw:
+		return string(v)
+	case map[string]interface{}:
+		return escapeCardVariables(v)
+	case map[string]string:
+		m := make(map[string]string, len(v))
+		for k, s := range v {
+			m[k] = EscapeMarkdown(s)
+		}
+		return m
+	case []string:
+		s := make([]string, len(v))
+		for i := range v {
+			s[i] = EscapeMarkdown(v[i])
+		}
+		return s
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i := range v {
+			s[i] = escapeCardValue(v[i])
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+func walkCardStrings(node interface{}, fn func(s string) error) error {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			if err := walkCardStrings(child, fn); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if err := walkCardStrings(child, fn); err != nil {
+				return err
+			}
+		}
+	case string:
+		return fn(v)
+	}
+	return nil
+}
+
+func uniqueSorted(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var out []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
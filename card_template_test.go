@@ -0,0 +1,124 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const deployCardTemplate = `{
+  "config": {"wide_screen_mode": true},
+  "header": {"title": {"tag": "plain_text", "content": "Deploy ${service}"}, "template": "{{if .ok}}green{{else}}red{{end}}"},
+  "elements": [
+    {"tag": "markdown", "content": "**${service}** deployed by {{.user}}\n${notes}"},
+    {"tag": "div", "text": {"tag": "plain_text", "content": "${service}"}},
+    {"tag": "action", "actions": [{"tag": "button", "text": {"tag": "plain_text", "content": "Open"}, "url": "${url}", "type": "default"}]}
+  ]
+}`
+
+func TestCardTemplate_Render(t *testing.T) {
+	Convey("test CardTemplate_Render", t, func() {
+		tmpl, err := ParseCardTemplate("deploy", []byte(deployCardTemplate))
+		So(err, ShouldBeNil)
+
+		content, err := tmpl.Render(map[string]interface{}{
+			"service": "api_server",
+			"user":    "*bob*",
+			"ok":      true,
+			"notes":   CardRaw("- fixed [bug](https://example.com)"),
+			"url":     "https://ci.example.com/?a=1&b=2",
+		})
+		So(err, ShouldBeNil)
+
+		card := new(BotCardOption)
+		So(json.Unmarshal(content, card), ShouldBeNil)
+		So(card.Header.Title.Content, ShouldEqual, "Deploy api_server")
+		So(card.Header.Template, ShouldEqual, "green")
+
+		// markdown 中的变量被转义，CardRaw 和其他元素保持原样
+		md := card.Elements[0].(map[string]interface{})
+		So(md["content"], ShouldEqual, "**api\\_server** deployed by \\*bob\\*\n- fixed [bug](https://example.com)")
+		div := card.Elements[1].(map[string]interface{})["text"].(map[string]interface{})
+		So(div["content"], ShouldEqual, "api_server")
+		So(string(content), ShouldContainSubstring, `"url":"https://ci.example.com/?a=1&b=2"`)
+
+		Convey("variable values are not expanded again", func() {
+			content, err := tmpl.Render(map[string]interface{}{
+				"service": "${url}",
+				"user":    "echo ${HOME}",
+				"ok":      true,
+				"notes":   CardRaw("run `${x}`"),
+				"url":     "https://ci.example.com",
+			})
+			So(err, ShouldBeNil)
+			card := new(BotCardOption)
+			So(json.Unmarshal(content, card), ShouldBeNil)
+			So(card.Header.Title.Content, ShouldEqual, "Deploy ${url}")
+			md := card.Elements[0].(map[string]interface{})
+			So(md["content"], ShouldEqual, "**${url}** deployed by echo ${HOME}\nrun `${x}`")
+		})
+
+		Convey("unresolved variables", func() {
+			err := tmpl.Validate(map[string]interface{}{"user": "bob", "ok": false})
+			e, ok := err.(*UnresolvedVariablesError)
+			So(ok, ShouldBeTrue)
+			So(e.Names, ShouldResemble, []string{"notes", "service", "url"})
+
+			// text/template 中缺少的变量
+			err = tmpl.Validate(map[string]interface{}{"service": "api", "notes": "", "url": ""})
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("test ParseCardTemplate invalid", t, func() {
+		_, err := ParseCardTemplate("bad", []byte(`{"header": {"title": {"content": "{{.name"}}}`))
+		So(err, ShouldNotBeNil)
+		_, err = ParseCardTemplate("bad", []byte(`{`))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestCardTemplate_ServerTemplate(t *testing.T) {
+	Convey("test CardTemplate_ServerTemplate", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/open-apis/auth/v3/tenant_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"code": 0, "expire": 7200, "msg": "ok", "tenant_access_token": "t-caecc734c2e3328a62489fe0648c4b98779515d3"}`)
+		})
+		mux.HandleFunc("/open-apis/im/v1/messages", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "receive_id_type=chat_id")
+			testBody(t, r, `{"msg_type":"interactive","receive_id":"oc_a0553eda9014c201e6969b478895c230","content":"{\"type\":\"template\",\"data\":{\"template_id\":\"ctp_AA1Xm5Ix6W2n\",\"template_variable\":{\"env\":\"prod\",\"service\":\"api\"}}}"}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"message_id": "om_1"}}`)
+		})
+
+		dir, err := ioutil.TempDir("", "feishu-card")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		content := `{"type": "template", "data": {"template_id": "ctp_AA1Xm5Ix6W2n", "template_variable": {"service": "", "env": "prod"}}}`
+		So(ioutil.WriteFile(filepath.Join(dir, "release.json"), []byte(content), 0600), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(dir, "deploy.json"), []byte(deployCardTemplate), 0600), ShouldBeNil)
+
+		templates, err := LoadCardTemplates(filepath.Join(dir, "*.json"))
+		So(err, ShouldBeNil)
+		So(templates, ShouldHaveLength, 2)
+		release := templates["release"]
+		So(release.TemplateId, ShouldEqual, "ctp_AA1Xm5Ix6W2n")
+
+		_, err = release.AppCardMessage("oc_a0553eda9014c201e6969b478895c230", nil)
+		So(err, ShouldHaveSameTypeAs, &UnresolvedVariablesError{})
+
+		opt, err := release.AppCardMessage("oc_a0553eda9014c201e6969b478895c230", map[string]interface{}{"service": "api"})
+		So(err, ShouldBeNil)
+		rsp, _, err := client.App.SendAppCardMessage("chat_id", opt)
+		So(err, ShouldBeNil)
+		So(rsp.Code, ShouldEqual, 0)
+	})
+}
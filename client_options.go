@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
 )
 
 // ClientOptionFunc can be used to customize a new GitLab API client.
@@ -92,37 +93,93 @@ func WithoutRetries() ClientOptionFunc {
 }
 
 func WithTenantAccessTokenInternal(appId, appSecret string) ClientOptionFunc {
+	return withAccessTokenInternal(appId, StaticCredential(appSecret), WithLocalCache())
+}
+
+func WithTenantAccessTokenInternalRedis(appId, appSecret, addr, password string) ClientOptionFunc {
+	return withAccessTokenInternal(appId, StaticCredential(appSecret), WithRedisCache(addr, password))
+}
+
+// WithTenantAccessTokenProvider 每次刷新 token 时从 provider 获取 appSecret，支持 secret 轮换。
+func WithTenantAccessTokenProvider(appId string, provider CredentialProvider) ClientOptionFunc {
+	return withAccessTokenInternal(appId, provider, WithLocalCache())
+}
+
+func WithTenantAccessTokenProviderRedis(appId string, provider CredentialProvider, addr, password string) ClientOptionFunc {
+	return withAccessTokenInternal(appId, provider, WithRedisCache(addr, password))
+}
+
+// WithBotSecretProvider 为 botKey 对应的机器人配置签名 secret，调用发送接口时 secret 传空即可。
+func WithBotSecretProvider(botKey string, provider CredentialProvider) ClientOptionFunc {
 	return func(c *Client) error {
-		refreshFunc := func() (*TenantAccessToken, error) {
-			opt := &GetAccessTokenOptions{
-				AppId:     appId,
-				AppSecret: appSecret,
-			}
-			if t, _, err := c.Auth.GetTenantAccessTokenInternal(opt); err != nil {
-				return nil, err
-			} else {
-				return t, nil
-			}
+		if c.botSecrets == nil {
+			c.botSecrets = make(map[string]CredentialProvider)
 		}
-		c.accessTokenManager = NewAccessTokenManager(appId, _tenantAccessTokenInternal, refreshFunc, WithLocalCache())
+		c.botSecrets[botKey] = provider
 		return nil
 	}
 }
 
-func WithTenantAccessTokenInternalRedis(appId, appSecret, addr, password string) ClientOptionFunc {
+// withAccessTokenInternal 同时管理 tenant_access_token、app_access_token 和 jsapi_ticket，并使用同一个缓存保存用户 token。
+func withAccessTokenInternal(appId string, provider CredentialProvider, cacheOption CacheOptionFunc) ClientOptionFunc {
 	return func(c *Client) error {
-		refreshFunc := func() (*TenantAccessToken, error) {
-			opt := &GetAccessTokenOptions{
+		// 每次刷新都重新获取 secret
+		accessTokenOptions := func() (*GetAccessTokenOptions, error) {
+			appSecret, err := provider.Secret()
+			if err != nil {
+				return nil, err
+			}
+			return &GetAccessTokenOptions{
 				AppId:     appId,
 				AppSecret: appSecret,
+			}, nil
+		}
+		tenantRefreshFunc := func() (*TenantAccessToken, error) {
+			opt, err := accessTokenOptions()
+			if err != nil {
+				return nil, err
+			}
+			t, _, err := c.Auth.GetTenantAccessTokenInternal(opt)
+			if err != nil {
+				return nil, err
+			}
+			if t.Code != 0 {
+				return nil, errors.Errorf("get tenant access token failed: %d %s", t.Code, t.Message)
+			}
+			return t, nil
+		}
+		appRefreshFunc := func() (*TenantAccessToken, error) {
+			opt, err := accessTokenOptions()
+			if err != nil {
+				return nil, err
+			}
+			t, _, err := c.Auth.GetAppAccessTokenInternal(opt)
+			if err != nil {
+				return nil, err
+			}
+			if t.Code != 0 {
+				return nil, errors.Errorf("get app access token failed: %d %s", t.Code, t.Message)
 			}
-			if t, _, err := c.Auth.GetTenantAccessTokenInternal(opt); err != nil {
+			return &TenantAccessToken{CodeMsg: t.CodeMsg, AccessToken: t.AccessToken, Expire: t.Expire}, nil
+		}
+
+		ticketRefreshFunc := func() (*TenantAccessToken, error) {
+			t, _, err := c.Auth.GetJsapiTicket()
+			if err != nil {
 				return nil, err
-			} else {
-				return t, nil
 			}
+			if t.Code != 0 {
+				return nil, errors.Errorf("get jsapi ticket failed: %d %s", t.Code, t.Message)
+			}
+			return &TenantAccessToken{CodeMsg: t.CodeMsg, AccessToken: t.Data.Ticket, Expire: t.Data.ExpireIn}, nil
 		}
-		c.accessTokenManager = NewAccessTokenManager(appId, _tenantAccessTokenInternal, refreshFunc, WithRedisCache(addr, password))
+
+		atms := NewAccessTokenManager(appId, _tenantAccessTokenInternal, tenantRefreshFunc, cacheOption)
+		c.appId = appId
+		c.accessTokenManager = atms
+		c.appAccessTokenManager = NewAccessTokenManager(appId, _appAccessTokenInternal, appRefreshFunc, WithTokenCache(atms.Cache))
+		c.jsapiTicketManager = NewAccessTokenManager(appId, _jsapiTicket, ticketRefreshFunc, WithTokenCache(&separateLockCache{TokenCache: atms.Cache}))
+		c.UserAccessTokens = NewUserAccessTokenStore(c, appId, atms.Cache)
 		return nil
 	}
 }
@@ -0,0 +1,90 @@
+package feishu
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CredentialProvider 提供 appSecret、机器人签名 secret 等凭证。
+// token manager 每次刷新 token、机器人每次签名时都会调用，轮换 secret 无需重建 Client。
+type CredentialProvider interface {
+	Secret() (string, error)
+}
+
+// StaticCredential 固定的 secret。
+type StaticCredential string
+
+func (c StaticCredential) Secret() (string, error) {
+	return string(c), nil
+}
+
+// CredentialFunc 通过回调获取 secret，例如从配置中心或 KMS 读取。
+type CredentialFunc func() (string, error)
+
+func (fn CredentialFunc) Secret() (string, error) {
+	return fn()
+}
+
+type envCredential struct {
+	name string
+}
+
+// EnvCredential 每次从环境变量 name 读取 secret。
+func EnvCredential(name string) CredentialProvider {
+	return &envCredential{name: name}
+}
+
+func (c *envCredential) Secret() (string, error) {
+	secret, ok := os.LookupEnv(c.name)
+	if !ok || len(secret) == 0 {
+		return "", errors.Errorf("credential env %s not set", c.name)
+	}
+	return secret, nil
+}
+
+type fileCredential struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	secret  string
+}
+
+// FileCredential 从文件读取 secret（去掉首尾空白），文件修改后自动重新读取，适用于挂载的 k8s Secret 等。
+func FileCredential(path string) CredentialProvider {
+	return &fileCredential{path: path}
+}
+
+func (c *fileCredential) Secret() (string, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 文件未变化，使用上次读取的内容
+	if len(c.secret) > 0 && info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.secret, nil
+	}
+
+	buf, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimSpace(string(buf))
+	if len(secret) == 0 {
+		return "", errors.Errorf("credential file %s is empty", c.path)
+	}
+	c.secret = secret
+	c.modTime = info.ModTime()
+	c.size = info.Size()
+	return c.secret, nil
+}
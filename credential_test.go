@@ -0,0 +1,117 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFileCredential_Secret(t *testing.T) {
+	Convey("test FileCredential_Secret", t, func() {
+		dir, err := ioutil.TempDir("", "feishu-credential")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "app_secret")
+		So(ioutil.WriteFile(path, []byte("secret-v1\n"), 0600), ShouldBeNil)
+
+		provider := FileCredential(path)
+		secret, err := provider.Secret()
+		So(err, ShouldBeNil)
+		So(secret, ShouldEqual, "secret-v1")
+
+		// 轮换 secret
+		So(ioutil.WriteFile(path, []byte("secret-v2-rotated\n"), 0600), ShouldBeNil)
+		later := time.Now().Add(time.Minute)
+		So(os.Chtimes(path, later, later), ShouldBeNil)
+		secret, err = provider.Secret()
+		So(err, ShouldBeNil)
+		So(secret, ShouldEqual, "secret-v2-rotated")
+	})
+}
+
+func TestEnvCredential_Secret(t *testing.T) {
+	Convey("test EnvCredential_Secret", t, func() {
+		os.Setenv("FEISHU_TEST_APP_SECRET", "env-secret")
+		defer os.Unsetenv("FEISHU_TEST_APP_SECRET")
+
+		secret, err := EnvCredential("FEISHU_TEST_APP_SECRET").Secret()
+		So(err, ShouldBeNil)
+		So(secret, ShouldEqual, "env-secret")
+
+		_, err = EnvCredential("FEISHU_TEST_NOT_SET").Secret()
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestWithTenantAccessTokenProvider(t *testing.T) {
+	Convey("test WithTenantAccessTokenProvider", t, func() {
+		mux, server, _ := setup(t)
+		defer teardown(server)
+
+		secret := "rotating-secret"
+		client, err := NewProviderClient("cli_provider_test", CredentialFunc(func() (string, error) {
+			return secret, nil
+		}), WithBaseURL(server.URL))
+		So(err, ShouldBeNil)
+
+		mux.HandleFunc("/open-apis/auth/v3/tenant_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"app_id":"cli_provider_test","app_secret":"rotating-secret"}`)
+			fmt.Fprint(w, `{
+				"code": 0,
+				"expire": 7200,
+				"msg": "ok",
+				"tenant_access_token": "t-provider"
+			}`)
+		})
+
+		err, accessToken := client.accessTokenManager.GetAccessToken()
+		So(err, ShouldBeNil)
+		So(accessToken, ShouldEqual, "t-provider")
+	})
+}
+
+func TestWithBotSecretProvider(t *testing.T) {
+	Convey("test WithBotSecretProvider", t, func() {
+		mux, server, _ := setup(t)
+		defer teardown(server)
+
+		botKey := "891105b7-1234-4567-7890-c4c372235090"
+		client, err := NewClient(WithBaseURL(server.URL), WithBotSecretProvider(botKey, StaticCredential("bot-secret")))
+		So(err, ShouldBeNil)
+
+		mux.HandleFunc("/open-apis/bot/v2/hook/"+botKey, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			opt := new(BotMessageOption)
+			if err := json.NewDecoder(r.Body).Decode(opt); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			timestamp, _ := strconv.ParseInt(opt.Timestamp, 10, 64)
+			if sign, _ := GenSign("bot-secret", timestamp); sign != opt.Sign {
+				t.Errorf("Sign: %s, want %s", opt.Sign, sign)
+			}
+			fmt.Fprint(w, `{
+				"StatusCode": 0,
+				"StatusMessage": "success"
+			}`)
+		})
+
+		opt := &BotMessageOption{
+			MsgType: "text",
+			Content: map[string]string{"text": "hello"},
+		}
+		rsp, _, err := client.Bot.SendBotMessage(botKey, "", opt)
+		So(err, ShouldBeNil)
+		So(rsp.StatusMessage, ShouldEqual, "success")
+		So(opt.Sign, ShouldNotBeEmpty)
+	})
+}
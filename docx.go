@@ -0,0 +1,373 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+type DocxService struct {
+	client *Client
+}
+
+type DocxDocument struct {
+	DocumentId string `json:"document_id"`
+	RevisionId int    `json:"revision_id"`
+	Title      string `json:"title"`
+}
+
+// CreateDocumentOptions FolderToken 为空时创建在根目录。
+type CreateDocumentOptions struct {
+	FolderToken string `json:"folder_token,omitempty"`
+	Title       string `json:"title,omitempty"`
+}
+
+type DocumentResponse struct {
+	CodeMsg
+	Data struct {
+		Document DocxDocument `json:"document"`
+	} `json:"data"`
+}
+
+type RawContentResponse struct {
+	CodeMsg
+	Data struct {
+		Content string `json:"content"`
+	} `json:"data"`
+}
+
+// DocxQueryOptions DocumentRevisionId 为空时是最新版本，ClientToken 用于幂等更新。
+type DocxQueryOptions struct {
+	DocumentRevisionId int    `url:"document_revision_id,omitempty"`
+	ClientToken        string `url:"client_token,omitempty"`
+	UserIdType         string `url:"user_id_type,omitempty"`
+}
+
+type ListBlocksOptions struct {
+	PageSize           int    `url:"page_size,omitempty"`
+	PageToken          string `url:"page_token,omitempty"`
+	DocumentRevisionId int    `url:"document_revision_id,omitempty"`
+	UserIdType         string `url:"user_id_type,omitempty"`
+}
+
+type ListBlocksResponse struct {
+	CodeMsg
+	Data struct {
+		Items     []DocxBlock `json:"items"`
+		PageToken string      `json:"page_token"`
+		HasMore   bool        `json:"has_more"`
+	} `json:"data"`
+}
+
+type BlockResponse struct {
+	CodeMsg
+	Data struct {
+		Block              DocxBlock `json:"block"`
+		DocumentRevisionId int       `json:"document_revision_id"`
+		ClientToken        string    `json:"client_token"`
+	} `json:"data"`
+}
+
+// CreateBlocksOptions Index 为插入的位置，-1 为末尾。
+type CreateBlocksOptions struct {
+	Children []*DocxBlock `json:"children"`
+	Index    int          `json:"index"`
+}
+
+type CreateBlocksResponse struct {
+	CodeMsg
+	Data struct {
+		Children           []DocxBlock `json:"children"`
+		DocumentRevisionId int         `json:"document_revision_id"`
+		ClientToken        string      `json:"client_token"`
+	} `json:"data"`
+}
+
+// UpdateBlockOptions 每次只能有一种更新。
+type UpdateBlockOptions struct {
+	BlockId             string                   `json:"block_id,omitempty"`
+	UpdateTextElements  *DocxUpdateTextElements  `json:"update_text_elements,omitempty"`
+	UpdateTextStyle     *DocxUpdateTextStyle     `json:"update_text_style,omitempty"`
+	UpdateTableProperty *DocxUpdateTableProperty `json:"update_table_property,omitempty"`
+	InsertTableRow      *DocxInsertTableRow      `json:"insert_table_row,omitempty"`
+	InsertTableColumn   *DocxInsertTableColumn   `json:"insert_table_column,omitempty"`
+	DeleteTableRows     *DocxDeleteTableRows     `json:"delete_table_rows,omitempty"`
+	DeleteTableColumns  *DocxDeleteTableColumns  `json:"delete_table_columns,omitempty"`
+	ReplaceImage        *DocxReplaceImage        `json:"replace_image,omitempty"`
+}
+
+type DocxUpdateTextElements struct {
+	Elements []DocxTextElement `json:"elements"`
+}
+
+// DocxUpdateTextStyle Fields 为更新的字段，1 对齐方式、2 完成状态、3 折叠、4 代码语言、5 自动换行。
+type DocxUpdateTextStyle struct {
+	Style  DocxTextStyle `json:"style"`
+	Fields []int         `json:"fields"`
+}
+
+type DocxUpdateTableProperty struct {
+	ColumnWidth int `json:"column_width"`
+	ColumnIndex int `json:"column_index"`
+}
+
+type DocxInsertTableRow struct {
+	RowIndex int `json:"row_index"`
+}
+
+type DocxInsertTableColumn struct {
+	ColumnIndex int `json:"column_index"`
+}
+
+type DocxDeleteTableRows struct {
+	RowStartIndex int `json:"row_start_index"`
+	RowEndIndex   int `json:"row_end_index"`
+}
+
+type DocxDeleteTableColumns struct {
+	ColumnStartIndex int `json:"column_start_index"`
+	ColumnEndIndex   int `json:"column_end_index"`
+}
+
+type DocxReplaceImage struct {
+	Token string `json:"token"`
+}
+
+type BatchUpdateBlocksOptions struct {
+	Requests []*UpdateBlockOptions `json:"requests"`
+}
+
+type BatchUpdateBlocksResponse struct {
+	CodeMsg
+	Data struct {
+		Blocks             []DocxBlock `json:"blocks"`
+		DocumentRevisionId int         `json:"document_revision_id"`
+		ClientToken        string      `json:"client_token"`
+	} `json:"data"`
+}
+
+// DeleteBlocksOptions 删除 [StartIndex, EndIndex) 的子块。
+type DeleteBlocksOptions struct {
+	StartIndex int `json:"start_index"`
+	EndIndex   int `json:"end_index"`
+}
+
+type DeleteBlocksResponse struct {
+	CodeMsg
+	Data struct {
+		DocumentRevisionId int    `json:"document_revision_id"`
+		ClientToken        string `json:"client_token"`
+	} `json:"data"`
+}
+
+// CreateDocument 创建文档，文档的根块 id 即 DocumentId。
+func (s *DocxService) CreateDocument(opt *CreateDocumentOptions, options ...RequestOptionFunc) (*DocumentResponse, *Response, error) {
+	u := "docx/v1/documents"
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(DocumentResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// GetDocument 查询文档的标题和最新版本。
+func (s *DocxService) GetDocument(documentId string, options ...RequestOptionFunc) (*DocumentResponse, *Response, error) {
+	u := fmt.Sprintf("docx/v1/documents/%s", documentId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(DocumentResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// RawContent 查询文档的纯文本内容。
+func (s *DocxService) RawContent(documentId string, options ...RequestOptionFunc) (*RawContentResponse, *Response, error) {
+	u := fmt.Sprintf("docx/v1/documents/%s/raw_content", documentId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(RawContentResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListBlocks 查询文档的所有块，按文档顺序返回。
+func (s *DocxService) ListBlocks(documentId string, opt *ListBlocksOptions, options ...RequestOptionFunc) (*ListBlocksResponse, *Response, error) {
+	u := fmt.Sprintf("docx/v1/documents/%s/blocks", documentId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListBlocksResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AllBlocks 翻页查询文档的所有块。
+func (s *DocxService) AllBlocks(documentId string, options ...RequestOptionFunc) ([]DocxBlock, error) {
+	opt := &ListBlocksOptions{PageSize: 500}
+
+	var blocks []DocxBlock
+	for {
+		rsp, _, err := s.ListBlocks(documentId, opt, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("list blocks of %s: %d %s", documentId, rsp.Code, rsp.Message)
+		}
+		blocks = append(blocks, rsp.Data.Items...)
+		if !rsp.Data.HasMore || len(rsp.Data.PageToken) == 0 {
+			return blocks, nil
+		}
+		opt.PageToken = rsp.Data.PageToken
+	}
+}
+
+// GetBlock 查询块。
+func (s *DocxService) GetBlock(documentId, blockId string, options ...RequestOptionFunc) (*BlockResponse, *Response, error) {
+	u := fmt.Sprintf("docx/v1/documents/%s/blocks/%s", documentId, blockId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BlockResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListChildren 查询块的子块。
+func (s *DocxService) ListChildren(documentId, blockId string, opt *ListBlocksOptions, options ...RequestOptionFunc) (*ListBlocksResponse, *Response, error) {
+	u := fmt.Sprintf("docx/v1/documents/%s/blocks/%s/children", documentId, blockId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListBlocksResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// CreateBlocks 在 blockId 中插入子块，每次最多 50 个，blockId 为 DocumentId 时插入到文档中。
+func (s *DocxService) CreateBlocks(documentId, blockId string, query *DocxQueryOptions, opt *CreateBlocksOptions, options ...RequestOptionFunc) (*CreateBlocksResponse, *Response, error) {
+	u := fmt.Sprintf("docx/v1/documents/%s/blocks/%s/children", documentId, blockId)
+	if query != nil {
+		options = append(options, WithQuery(query))
+	}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(CreateBlocksResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UpdateBlock 更新块的内容。
+func (s *DocxService) UpdateBlock(documentId, blockId string, query *DocxQueryOptions, opt *UpdateBlockOptions, options ...RequestOptionFunc) (*BlockResponse, *Response, error) {
+	u := fmt.Sprintf("docx/v1/documents/%s/blocks/%s", documentId, blockId)
+	if query != nil {
+		options = append(options, WithQuery(query))
+	}
+
+	req, err := s.client.NewServerRequest(http.MethodPatch, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BlockResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// BatchUpdateBlocks 批量更新块，每个请求需要 BlockId，每次最多 200 个。
+func (s *DocxService) BatchUpdateBlocks(documentId string, query *DocxQueryOptions, opt *BatchUpdateBlocksOptions, options ...RequestOptionFunc) (*BatchUpdateBlocksResponse, *Response, error) {
+	u := fmt.Sprintf("docx/v1/documents/%s/blocks/batch_update", documentId)
+	if query != nil {
+		options = append(options, WithQuery(query))
+	}
+
+	req, err := s.client.NewServerRequest(http.MethodPatch, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BatchUpdateBlocksResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteBlocks 删除 blockId 的部分子块。
+func (s *DocxService) DeleteBlocks(documentId, blockId string, query *DocxQueryOptions, opt *DeleteBlocksOptions, options ...RequestOptionFunc) (*DeleteBlocksResponse, *Response, error) {
+	u := fmt.Sprintf("docx/v1/documents/%s/blocks/%s/children/batch_delete", documentId, blockId)
+	options = append(options, WithJSONBody(opt))
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, query, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(DeleteBlocksResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
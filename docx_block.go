@@ -0,0 +1,348 @@
+package feishu
+
+import (
+	"strings"
+)
+
+// 块类型
+const (
+	DocxBlockPage      = 1
+	DocxBlockText      = 2
+	DocxBlockHeading1  = 3
+	DocxBlockHeading9  = 11
+	DocxBlockBullet    = 12
+	DocxBlockOrdered   = 13
+	DocxBlockCode      = 14
+	DocxBlockQuote     = 15
+	DocxBlockTodo      = 17
+	DocxBlockCallout   = 19
+	DocxBlockDivider   = 22
+	DocxBlockImage     = 27
+	DocxBlockTable     = 31
+	DocxBlockTableCell = 32
+)
+
+// 对齐方式
+const (
+	DocxAlignLeft   = 1
+	DocxAlignCenter = 2
+	DocxAlignRight  = 3
+)
+
+// DocxBlock 文档中的块，BlockType 决定哪个字段有内容。
+type DocxBlock struct {
+	BlockId   string   `json:"block_id,omitempty"`
+	ParentId  string   `json:"parent_id,omitempty"`
+	Children  []string `json:"children,omitempty"`
+	BlockType int      `json:"block_type"`
+
+	Page      *DocxText    `json:"page,omitempty"`
+	Text      *DocxText    `json:"text,omitempty"`
+	Heading1  *DocxText    `json:"heading1,omitempty"`
+	Heading2  *DocxText    `json:"heading2,omitempty"`
+	Heading3  *DocxText    `json:"heading3,omitempty"`
+	Heading4  *DocxText    `json:"heading4,omitempty"`
+	Heading5  *DocxText    `json:"heading5,omitempty"`
+	Heading6  *DocxText    `json:"heading6,omitempty"`
+	Heading7  *DocxText    `json:"heading7,omitempty"`
+	Heading8  *DocxText    `json:"heading8,omitempty"`
+	Heading9  *DocxText    `json:"heading9,omitempty"`
+	Bullet    *DocxText    `json:"bullet,omitempty"`
+	Ordered   *DocxText    `json:"ordered,omitempty"`
+	Code      *DocxText    `json:"code,omitempty"`
+	Quote     *DocxText    `json:"quote,omitempty"`
+	Todo      *DocxText    `json:"todo,omitempty"`
+	Callout   *DocxCallout `json:"callout,omitempty"`
+	Divider   *struct{}    `json:"divider,omitempty"`
+	Image     *DocxImage   `json:"image,omitempty"`
+	Table     *DocxTable   `json:"table,omitempty"`
+	TableCell *struct{}    `json:"table_cell,omitempty"`
+}
+
+type DocxText struct {
+	Style    *DocxTextStyle    `json:"style,omitempty"`
+	Elements []DocxTextElement `json:"elements"`
+}
+
+// DocxTextStyle Language 为代码块的语言，Done 为待办是否完成。
+type DocxTextStyle struct {
+	Align    int  `json:"align,omitempty"`
+	Done     bool `json:"done,omitempty"`
+	Folded   bool `json:"folded,omitempty"`
+	Language int  `json:"language,omitempty"`
+	Wrap     bool `json:"wrap,omitempty"`
+}
+
+type DocxTextElement struct {
+	TextRun     *DocxTextRun     `json:"text_run,omitempty"`
+	MentionUser *DocxMentionUser `json:"mention_user,omitempty"`
+	Equation    *DocxTextRun     `json:"equation,omitempty"`
+}
+
+type DocxTextRun struct {
+	Content          string                `json:"content"`
+	TextElementStyle *DocxTextElementStyle `json:"text_element_style,omitempty"`
+}
+
+type DocxMentionUser struct {
+	UserId           string                `json:"user_id"`
+	TextElementStyle *DocxTextElementStyle `json:"text_element_style,omitempty"`
+}
+
+type DocxTextElementStyle struct {
+	Bold          bool      `json:"bold,omitempty"`
+	Italic        bool      `json:"italic,omitempty"`
+	Strikethrough bool      `json:"strikethrough,omitempty"`
+	Underline     bool      `json:"underline,omitempty"`
+	InlineCode    bool      `json:"inline_code,omitempty"`
+	Link          *DocxLink `json:"link,omitempty"`
+}
+
+// DocxLink Url 需要 url 编码。
+type DocxLink struct {
+	Url string `json:"url"`
+}
+
+// DocxCallout 高亮块，内容为子块。
+type DocxCallout struct {
+	BackgroundColor int    `json:"background_color,omitempty"`
+	BorderColor     int    `json:"border_color,omitempty"`
+	TextColor       int    `json:"text_color,omitempty"`
+	EmojiId         string `json:"emoji_id,omitempty"`
+}
+
+// DocxImage 创建时为空，上传图片后通过 ReplaceImage 设置 Token。
+type DocxImage struct {
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
+// DocxTable 创建表格时自动创建单元格，单元格内容为子块。
+type DocxTable struct {
+	Cells    []string          `json:"cells,omitempty"`
+	Property DocxTableProperty `json:"property"`
+}
+
+type DocxTableProperty struct {
+	RowSize     int   `json:"row_size"`
+	ColumnSize  int   `json:"column_size"`
+	ColumnWidth []int `json:"column_width,omitempty"`
+	HeaderRow   bool  `json:"header_row,omitempty"`
+}
+
+// NewDocxTextRun 文本元素，style 可以为空。
+func NewDocxTextRun(content string, style *DocxTextElementStyle) DocxTextElement {
+	return DocxTextElement{TextRun: &DocxTextRun{Content: content, TextElementStyle: style}}
+}
+
+func NewDocxText(elements ...DocxTextElement) *DocxText {
+	return &DocxText{Elements: elements}
+}
+
+func NewDocxTextBlock(elements ...DocxTextElement) *DocxBlock {
+	return &DocxBlock{BlockType: DocxBlockText, Text: NewDocxText(elements...)}
+}
+
+// NewDocxHeadingBlock level 为 1 到 9。
+func NewDocxHeadingBlock(level int, elements ...DocxTextElement) *DocxBlock {
+	if level < 1 {
+		level = 1
+	}
+	if level > 9 {
+		level = 9
+	}
+	b := &DocxBlock{BlockType: DocxBlockHeading1 + level - 1}
+	*b.heading(level) = NewDocxText(elements...)
+	return b
+}
+
+func NewDocxBulletBlock(elements ...DocxTextElement) *DocxBlock {
+	return &DocxBlock{BlockType: DocxBlockBullet, Bullet: NewDocxText(elements...)}
+}
+
+func NewDocxOrderedBlock(elements ...DocxTextElement) *DocxBlock {
+	return &DocxBlock{BlockType: DocxBlockOrdered, Ordered: NewDocxText(elements...)}
+}
+
+func NewDocxQuoteBlock(elements ...DocxTextElement) *DocxBlock {
+	return &DocxBlock{BlockType: DocxBlockQuote, Quote: NewDocxText(elements...)}
+}
+
+func NewDocxTodoBlock(done bool, elements ...DocxTextElement) *DocxBlock {
+	text := NewDocxText(elements...)
+	text.Style = &DocxTextStyle{Done: done}
+	return &DocxBlock{BlockType: DocxBlockTodo, Todo: text}
+}
+
+// NewDocxCodeBlock language 为语言名称，如 go、python，见 DocxCodeLanguage。
+func NewDocxCodeBlock(language, code string) *DocxBlock {
+	text := NewDocxText(NewDocxTextRun(code, nil))
+	text.Style = &DocxTextStyle{Language: DocxCodeLanguage(language), Wrap: true}
+	return &DocxBlock{BlockType: DocxBlockCode, Code: text}
+}
+
+// NewDocxCalloutBlock 高亮块，创建后再添加子块作为内容。
+func NewDocxCalloutBlock(emojiId string, backgroundColor int) *DocxBlock {
+	return &DocxBlock{BlockType: DocxBlockCallout, Callout: &DocxCallout{EmojiId: emojiId, BackgroundColor: backgroundColor}}
+}
+
+func NewDocxDividerBlock() *DocxBlock {
+	return &DocxBlock{BlockType: DocxBlockDivider, Divider: &struct{}{}}
+}
+
+func NewDocxImageBlock() *DocxBlock {
+	return &DocxBlock{BlockType: DocxBlockImage, Image: &DocxImage{}}
+}
+
+// NewDocxTableBlock rows 行 columns 列的表格，第一行为表头。
+func NewDocxTableBlock(rows, columns int) *DocxBlock {
+	return &DocxBlock{BlockType: DocxBlockTable, Table: &DocxTable{
+		Property: DocxTableProperty{RowSize: rows, ColumnSize: columns, HeaderRow: true},
+	}}
+}
+
+func (b *DocxBlock) heading(level int) **DocxText {
+	switch level {
+	case 1:
+		return &b.Heading1
+	case 2:
+		return &b.Heading2
+	case 3:
+		return &b.Heading3
+	case 4:
+		return &b.Heading4
+	case 5:
+		return &b.Heading5
+	case 6:
+		return &b.Heading6
+	case 7:
+		return &b.Heading7
+	case 8:
+		return &b.Heading8
+	default:
+		return &b.Heading9
+	}
+}
+
+// TextContent 返回文本类块的内容，其他块为 nil。
+func (b *DocxBlock) TextContent() *DocxText {
+	switch b.BlockType {
+	case DocxBlockPage:
+		return b.Page
+	case DocxBlockText:
+		return b.Text
+	case DocxBlockBullet:
+		return b.Bullet
+	case DocxBlockOrdered:
+		return b.Ordered
+	case DocxBlockCode:
+		return b.Code
+	case DocxBlockQuote:
+		return b.Quote
+	case DocxBlockTodo:
+		return b.Todo
+	}
+	if b.BlockType >= DocxBlockHeading1 && b.BlockType <= DocxBlockHeading9 {
+		return *b.heading(b.BlockType - DocxBlockHeading1 + 1)
+	}
+	return nil
+}
+
+// PlainText 文本类块的纯文本内容。
+func (b *DocxBlock) PlainText() string {
+	text := b.TextContent()
+	if text == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, e := range text.Elements {
+		switch {
+		case e.TextRun != nil:
+			sb.WriteString(e.TextRun.Content)
+		case e.Equation != nil:
+			sb.WriteString(e.Equation.Content)
+		}
+	}
+	return sb.String()
+}
+
+var docxCodeLanguages = map[string]int{
+	"plaintext":  1,
+	"text":       1,
+	"bash":       7,
+	"csharp":     8,
+	"c#":         8,
+	"c++":        9,
+	"cpp":        9,
+	"c":          10,
+	"css":        12,
+	"dart":       15,
+	"dockerfile": 18,
+	"erlang":     19,
+	"go":         22,
+	"golang":     22,
+	"groovy":     23,
+	"html":       24,
+	"http":       26,
+	"haskell":    27,
+	"json":       28,
+	"java":       29,
+	"javascript": 30,
+	"js":         30,
+	"kotlin":     32,
+	"latex":      33,
+	"lua":        36,
+	"makefile":   38,
+	"markdown":   39,
+	"md":         39,
+	"nginx":      40,
+	"objc":       41,
+	"php":        43,
+	"perl":       44,
+	"powershell": 46,
+	"protobuf":   48,
+	"proto":      48,
+	"python":     49,
+	"py":         49,
+	"r":          50,
+	"ruby":       52,
+	"rust":       53,
+	"scss":       55,
+	"sql":        56,
+	"scala":      57,
+	"shell":      60,
+	"sh":         60,
+	"swift":      61,
+	"thrift":     62,
+	"typescript": 63,
+	"ts":         63,
+	"xml":        66,
+	"yaml":       67,
+	"yml":        67,
+}
+
+// DocxCodeLanguage 代码块语言名称转换为语言类型，未知的语言为纯文本。
+func DocxCodeLanguage(name string) int {
+	if lang, ok := docxCodeLanguages[strings.ToLower(name)]; ok {
+		return lang
+	}
+	return 1
+}
+
+var docxCodeLanguageNames = func() map[int]string {
+	names := make(map[int]string, len(docxCodeLanguages))
+	for name, lang := range docxCodeLanguages {
+		// 同一语言有多个名称时使用最短的
+		if old, ok := names[lang]; !ok || len(name) < len(old) || (len(name) == len(old) && name < old) {
+			names[lang] = name
+		}
+	}
+	names[1] = ""
+	return names
+}()
+
+// DocxCodeLanguageName 代码块语言类型转换为名称，纯文本和未知的语言为空。
+func DocxCodeLanguageName(lang int) string {
+	return docxCodeLanguageNames[lang]
+}
@@ -0,0 +1,691 @@
+package feishu
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/eyotang/go-feishu/internal/markdown"
+	"github.com/pkg/errors"
+)
+
+// 每次最多创建 50 个子块
+const docxCreateBlocksMax = 50
+
+// GitHub 风格的提示 > [!NOTE] 转换为高亮块
+var (
+	docxAlertRe = regexp.MustCompile(`^\[!(NOTE|TIP|IMPORTANT|WARNING|CAUTION)\][ \t]*`)
+
+	docxCallouts = map[string]DocxCallout{
+		"NOTE":      {EmojiId: "memo", BackgroundColor: 5},
+		"TIP":       {EmojiId: "bulb", BackgroundColor: 4},
+		"IMPORTANT": {EmojiId: "pushpin", BackgroundColor: 6},
+		"WARNING":   {EmojiId: "warning", BackgroundColor: 3},
+		"CAUTION":   {EmojiId: "no_entry", BackgroundColor: 1},
+	}
+)
+
+var docxAligns = map[markdown.Align]int{
+	markdown.AlignLeft:   DocxAlignLeft,
+	markdown.AlignCenter: DocxAlignCenter,
+	markdown.AlignRight:  DocxAlignRight,
+}
+
+var docxMarkdownAligns = map[int]string{
+	0:               "---",
+	DocxAlignLeft:   ":---",
+	DocxAlignCenter: ":---:",
+	DocxAlignRight:  "---:",
+}
+
+var docxMarkdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`~`, `\~`,
+	"`", "\\`",
+	`[`, `\[`,
+	`]`, `\]`,
+)
+
+// DocxBlockNode 待创建的块和子块，表格的子节点为按行排列的单元格。
+type DocxBlockNode struct {
+	Block    *DocxBlock
+	Children []*DocxBlockNode
+	// 图片块的图片地址，创建图片块后读取并上传
+	ImageURL string
+	ImageAlt string
+}
+
+// DocxImageLoader 读取 markdown 中的图片，返回图片内容和文件名。
+type DocxImageLoader func(url string) (content []byte, fileName string, err error)
+
+// DocxImageSaver 保存文档中的图片，返回 markdown 中使用的图片地址。
+type DocxImageSaver func(token string, content []byte) (url string, err error)
+
+// MarkdownToDocxBlocks 把 markdown 转换为块：列表的嵌套转换为子块，表格转换为表格和单元格，
+// GitHub 风格的提示转换为高亮块，其他引用为引用块。
+func MarkdownToDocxBlocks(md string) []*DocxBlockNode {
+	var nodes []*DocxBlockNode
+	for _, b := range markdown.Parse(md) {
+		nodes = append(nodes, docxNodes(&b)...)
+	}
+	return nodes
+}
+
+func docxNodes(b *markdown.Block) []*DocxBlockNode {
+	leaf := func(block *DocxBlock) []*DocxBlockNode {
+		return []*DocxBlockNode{{Block: block}}
+	}
+
+	switch b.Kind {
+	case markdown.KindParagraph:
+		return leaf(NewDocxTextBlock(docxElements(b.Text)...))
+	case markdown.KindHeading:
+		return leaf(NewDocxHeadingBlock(b.Level, docxElements(b.Text)...))
+	case markdown.KindList:
+		return docxList(b.Items)
+	case markdown.KindCode:
+		return leaf(NewDocxCodeBlock(b.Lang, b.Text))
+	case markdown.KindQuote:
+		return docxQuote(b.Text)
+	case markdown.KindTable:
+		return []*DocxBlockNode{docxTable(b)}
+	case markdown.KindHr:
+		return leaf(NewDocxDividerBlock())
+	case markdown.KindImage:
+		return []*DocxBlockNode{{Block: NewDocxImageBlock(), ImageURL: b.URL, ImageAlt: b.Alt}}
+	}
+	return nil
+}
+
+// docxElements 行内 markdown 转换为文本元素，链接地址需要 url 编码。
+func docxElements(text string) []DocxTextElement {
+	spans := markdown.ParseInline(text)
+	elements := make([]DocxTextElement, 0, len(spans))
+	for _, span := range spans {
+		var style *DocxTextElementStyle
+		if span.Bold || span.Italic || span.Strike || span.Code || len(span.Link) > 0 {
+			style = &DocxTextElementStyle{
+				Bold:          span.Bold,
+				Italic:        span.Italic,
+				Strikethrough: span.Strike,
+				InlineCode:    span.Code,
+			}
+			if len(span.Link) > 0 {
+				style.Link = &DocxLink{Url: url.QueryEscape(span.Link)}
+			}
+		}
+		elements = append(elements, NewDocxTextRun(span.Text, style))
+	}
+	return elements
+}
+
+// docxList 缩进的列表项为上一层列表项的子块。
+func docxList(items []markdown.ListItem) []*DocxBlockNode {
+	var (
+		roots []*DocxBlockNode
+		// 每一层最后的列表项
+		stack []*DocxBlockNode
+	)
+	for _, item := range items {
+		elements := docxElements(item.Text)
+		var block *DocxBlock
+		switch {
+		case item.Checked != nil:
+			block = NewDocxTodoBlock(*item.Checked, elements...)
+		case item.Ordered:
+			block = NewDocxOrderedBlock(elements...)
+		default:
+			block = NewDocxBulletBlock(elements...)
+		}
+
+		node := &DocxBlockNode{Block: block}
+		indent := item.Indent
+		if indent > len(stack) {
+			indent = len(stack)
+		}
+		stack = stack[:indent]
+		if indent == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := stack[indent-1]
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, node)
+	}
+	return roots
+}
+
+func docxQuote(text string) []*DocxBlockNode {
+	var alert string
+	if m := docxAlertRe.FindStringSubmatch(text); m != nil {
+		alert, text = m[1], text[len(m[0]):]
+	}
+
+	var nodes []*DocxBlockNode
+	for _, line := range strings.Split(text, "\n") {
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		if len(alert) > 0 {
+			nodes = append(nodes, &DocxBlockNode{Block: NewDocxTextBlock(docxElements(line)...)})
+		} else {
+			nodes = append(nodes, &DocxBlockNode{Block: NewDocxQuoteBlock(docxElements(line)...)})
+		}
+	}
+	if len(alert) == 0 {
+		return nodes
+	}
+
+	callout := docxCallouts[alert]
+	block := &DocxBlock{BlockType: DocxBlockCallout, Callout: &callout}
+	return []*DocxBlockNode{{Block: block, Children: nodes}}
+}
+
+func docxTable(b *markdown.Block) *DocxBlockNode {
+	rows := append([][]string{b.Header}, b.Rows...)
+	node := &DocxBlockNode{Block: NewDocxTableBlock(len(rows), len(b.Header))}
+	for _, row := range rows {
+		for i, cell := range row {
+			cellNode := &DocxBlockNode{Block: &DocxBlock{BlockType: DocxBlockTableCell, TableCell: &struct{}{}}}
+			if len(strings.TrimSpace(cell)) > 0 {
+				text := NewDocxTextBlock(docxElements(cell)...)
+				if i < len(b.Align) && docxAligns[b.Align[i]] > 0 {
+					text.Text.Style = &DocxTextStyle{Align: docxAligns[b.Align[i]]}
+				}
+				cellNode.Children = []*DocxBlockNode{{Block: text}}
+			}
+			node.Children = append(node.Children, cellNode)
+		}
+	}
+	return node
+}
+
+// CreateBlockTree 在 blockId 末尾创建块和子块。图片块创建后通过 loader 读取图片并上传，
+// loader 为空时图片转换为链接。
+func (s *DocxService) CreateBlockTree(documentId, blockId string, nodes []*DocxBlockNode, loader DocxImageLoader, options ...RequestOptionFunc) error {
+	for i := 0; i < len(nodes); i += docxCreateBlocksMax {
+		end := i + docxCreateBlocksMax
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+		batch := nodes[i:end]
+
+		children := make([]*DocxBlock, len(batch))
+		for j, node := range batch {
+			children[j] = node.Block
+			if node.Block.BlockType == DocxBlockImage && loader == nil {
+				alt := node.ImageAlt
+				if len(alt) == 0 {
+					alt = node.ImageURL
+				}
+				children[j] = NewDocxTextBlock(NewDocxTextRun(alt, &DocxTextElementStyle{Link: &DocxLink{Url: url.QueryEscape(node.ImageURL)}}))
+			}
+		}
+
+		rsp, _, err := s.CreateBlocks(documentId, blockId, nil, &CreateBlocksOptions{Children: children, Index: -1}, options...)
+		if err != nil {
+			return err
+		}
+		if rsp.Code != 0 {
+			return errors.Errorf("create blocks in %s: %d %s", blockId, rsp.Code, rsp.Message)
+		}
+		if len(rsp.Data.Children) != len(batch) {
+			return errors.Errorf("create blocks in %s: got %d blocks, want %d", blockId, len(rsp.Data.Children), len(batch))
+		}
+
+		for j, node := range batch {
+			if err = s.fillBlockNode(documentId, &rsp.Data.Children[j], node, loader, options...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fillBlockNode 创建块后创建子块、表格单元格的内容，上传图片。
+func (s *DocxService) fillBlockNode(documentId string, created *DocxBlock, node *DocxBlockNode, loader DocxImageLoader, options ...RequestOptionFunc) error {
+	switch node.Block.BlockType {
+	case DocxBlockTable:
+		// 创建表格时自动创建单元格
+		if created.Table == nil || len(created.Table.Cells) < len(node.Children) {
+			return errors.Errorf("table %s: cells not match", created.BlockId)
+		}
+		for i, cell := range node.Children {
+			if err := s.fillContainer(documentId, created.Table.Cells[i], cell.Children, loader, options...); err != nil {
+				return err
+			}
+		}
+		return nil
+	case DocxBlockCallout:
+		return s.fillContainer(documentId, created.BlockId, node.Children, loader, options...)
+	case DocxBlockImage:
+		if loader == nil {
+			return nil
+		}
+		return s.uploadImage(documentId, created.BlockId, node.ImageURL, loader, options...)
+	}
+	return s.CreateBlockTree(documentId, created.BlockId, node.Children, loader, options...)
+}
+
+// fillContainer 表格单元格和高亮块创建时自带一个空的文本块，第一个文本块写入其中，其余的块追加在后面。
+func (s *DocxService) fillContainer(documentId, blockId string, nodes []*DocxBlockNode, loader DocxImageLoader, options ...RequestOptionFunc) error {
+	if len(nodes) == 0 || nodes[0].Block.BlockType != DocxBlockText {
+		return s.CreateBlockTree(documentId, blockId, nodes, loader, options...)
+	}
+
+	children, _, err := s.ListChildren(documentId, blockId, nil, options...)
+	if err != nil {
+		return err
+	}
+	if children.Code != 0 {
+		return errors.Errorf("list children of %s: %d %s", blockId, children.Code, children.Message)
+	}
+	items := children.Data.Items
+	if len(items) != 1 || items[0].BlockType != DocxBlockText || len(items[0].PlainText()) > 0 {
+		return s.CreateBlockTree(documentId, blockId, nodes, loader, options...)
+	}
+
+	empty, text := &items[0], nodes[0].Block.Text
+	requests := []*UpdateBlockOptions{{BlockId: empty.BlockId, UpdateTextElements: &DocxUpdateTextElements{Elements: text.Elements}}}
+	if text.Style != nil && text.Style.Align > 0 {
+		requests = append(requests, &UpdateBlockOptions{BlockId: empty.BlockId, UpdateTextStyle: &DocxUpdateTextStyle{Style: *text.Style, Fields: []int{1}}})
+	}
+	updated, _, err := s.BatchUpdateBlocks(documentId, nil, &BatchUpdateBlocksOptions{Requests: requests}, options...)
+	if err != nil {
+		return err
+	}
+	if updated.Code != 0 {
+		return errors.Errorf("update block %s: %d %s", empty.BlockId, updated.Code, updated.Message)
+	}
+
+	if err = s.fillBlockNode(documentId, empty, nodes[0], loader, options...); err != nil {
+		return err
+	}
+	return s.CreateBlockTree(documentId, blockId, nodes[1:], loader, options...)
+}
+
+func (s *DocxService) uploadImage(documentId, blockId, imageURL string, loader DocxImageLoader, options ...RequestOptionFunc) error {
+	content, fileName, err := loader(imageURL)
+	if err != nil {
+		return errors.Wrapf(err, "load image %s", imageURL)
+	}
+	extra, _ := json.Marshal(map[string]string{"drive_route_token": documentId})
+
+	uploaded, _, err := s.client.Drive.UploadMedia(&UploadMediaOptions{
+		FileName:   fileName,
+		ParentType: DriveParentTypeDocxImage,
+		ParentNode: blockId,
+		Size:       int64(len(content)),
+		Checksum:   Adler32Checksum(content),
+		Extra:      string(extra),
+	}, bytes.NewReader(content), options...)
+	if err != nil {
+		return err
+	}
+	if uploaded.Code != 0 {
+		return errors.Errorf("upload image %s: %d %s", imageURL, uploaded.Code, uploaded.Message)
+	}
+
+	updated, _, err := s.UpdateBlock(documentId, blockId, nil, &UpdateBlockOptions{
+		ReplaceImage: &DocxReplaceImage{Token: uploaded.Data.FileToken},
+	}, options...)
+	if err != nil {
+		return err
+	}
+	if updated.Code != 0 {
+		return errors.Errorf("replace image %s: %d %s", blockId, updated.Code, updated.Message)
+	}
+	return nil
+}
+
+// ImportMarkdown 把 markdown 追加到文档末尾。
+func (s *DocxService) ImportMarkdown(documentId, md string, loader DocxImageLoader, options ...RequestOptionFunc) error {
+	return s.CreateBlockTree(documentId, documentId, MarkdownToDocxBlocks(md), loader, options...)
+}
+
+// CreateDocumentFromMarkdown 创建文档并写入 markdown，title 为空时使用第一个一级标题，如发布说明。
+func (s *DocxService) CreateDocumentFromMarkdown(folderToken, title, md string, loader DocxImageLoader, options ...RequestOptionFunc) (*DocxDocument, error) {
+	nodes := MarkdownToDocxBlocks(md)
+	if len(title) == 0 && len(nodes) > 0 && nodes[0].Block.BlockType == DocxBlockHeading1 {
+		title = nodes[0].Block.PlainText()
+		nodes = nodes[1:]
+	}
+
+	created, _, err := s.CreateDocument(&CreateDocumentOptions{FolderToken: folderToken, Title: title}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if created.Code != 0 {
+		return nil, errors.Errorf("create document %s: %d %s", title, created.Code, created.Message)
+	}
+
+	doc := &created.Data.Document
+	return doc, s.CreateBlockTree(doc.DocumentId, doc.DocumentId, nodes, loader, options...)
+}
+
+// ExportMarkdown 把文档转换为 markdown。图片下载后通过 saver 保存，saver 为空时图片地址为图片的 token。
+func (s *DocxService) ExportMarkdown(documentId string, saver DocxImageSaver, options ...RequestOptionFunc) (string, error) {
+	blocks, err := s.AllBlocks(documentId, options...)
+	if err != nil {
+		return "", err
+	}
+
+	return DocxBlocksToMarkdown(blocks, func(token string) (string, error) {
+		if saver == nil {
+			return token, nil
+		}
+		var buf bytes.Buffer
+		if _, err := s.client.Drive.DownloadMedia(token, &buf, options...); err != nil {
+			return "", err
+		}
+		return saver(token, buf.Bytes())
+	})
+}
+
+// DocxBlocksToMarkdown 把 ListBlocks 返回的块转换为 markdown，文档标题为一级标题，
+// imageURL 返回图片 token 对应的图片地址。
+func DocxBlocksToMarkdown(blocks []DocxBlock, imageURL func(token string) (string, error)) (string, error) {
+	w := &docxMarkdownWriter{blocks: make(map[string]*DocxBlock, len(blocks)), imageURL: imageURL}
+	var page *DocxBlock
+	for i := range blocks {
+		b := &blocks[i]
+		w.blocks[b.BlockId] = b
+		if b.BlockType == DocxBlockPage && page == nil {
+			page = b
+		}
+	}
+	if page == nil {
+		return "", errors.New("page block not found")
+	}
+
+	var segments []mdSegment
+	if title := page.PlainText(); len(title) > 0 {
+		segments = append(segments, mdSegment{text: "# " + title})
+	}
+	children, err := w.render(page.Children)
+	if err != nil {
+		return "", err
+	}
+	segments = append(segments, children...)
+	return joinSegments(segments) + "\n", nil
+}
+
+// mdSegment 一个 markdown 块，相邻的同类列表项之间不空行。
+type mdSegment struct {
+	text string
+	list int
+}
+
+func joinSegments(segments []mdSegment) string {
+	var b strings.Builder
+	for i, seg := range segments {
+		if i > 0 {
+			if seg.list > 0 && seg.list == segments[i-1].list {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(seg.text)
+	}
+	return b.String()
+}
+
+type docxMarkdownWriter struct {
+	blocks   map[string]*DocxBlock
+	imageURL func(token string) (string, error)
+}
+
+func (w *docxMarkdownWriter) render(ids []string) ([]mdSegment, error) {
+	var (
+		segments []mdSegment
+		number   int
+	)
+	for _, id := range ids {
+		b, ok := w.blocks[id]
+		if !ok {
+			continue
+		}
+		// 连续的有序列表项编号
+		if b.BlockType == DocxBlockOrdered {
+			number++
+		} else {
+			number = 0
+		}
+		seg, err := w.renderBlock(b, number)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg...)
+	}
+	return segments, nil
+}
+
+func (w *docxMarkdownWriter) renderBlock(b *DocxBlock, number int) ([]mdSegment, error) {
+	switch b.BlockType {
+	case DocxBlockText:
+		return []mdSegment{{text: docxInline(b.Text)}}, nil
+	case DocxBlockBullet, DocxBlockOrdered, DocxBlockTodo:
+		return w.renderListItem(b, number)
+	case DocxBlockCode:
+		lang := ""
+		if b.Code.Style != nil {
+			lang = DocxCodeLanguageName(b.Code.Style.Language)
+		}
+		return []mdSegment{{text: "```" + lang + "\n" + b.PlainText() + "\n```"}}, nil
+	case DocxBlockQuote:
+		return []mdSegment{{text: quoteLines(docxInline(b.Quote))}}, nil
+	case DocxBlockCallout:
+		return w.renderCallout(b)
+	case DocxBlockDivider:
+		return []mdSegment{{text: "---"}}, nil
+	case DocxBlockImage:
+		if b.Image == nil {
+			return nil, nil
+		}
+		u, err := w.imageURL(b.Image.Token)
+		if err != nil {
+			return nil, err
+		}
+		return []mdSegment{{text: fmt.Sprintf("![](%s)", u)}}, nil
+	case DocxBlockTable:
+		return w.renderTable(b)
+	}
+	if b.BlockType >= DocxBlockHeading1 && b.BlockType <= DocxBlockHeading9 {
+		level := b.BlockType - DocxBlockHeading1 + 1
+		if level > 6 {
+			level = 6
+		}
+		return []mdSegment{{text: strings.Repeat("#", level) + " " + docxInline(b.TextContent())}}, nil
+	}
+	// 其他块只输出子块
+	return w.render(b.Children)
+}
+
+func (w *docxMarkdownWriter) renderListItem(b *DocxBlock, number int) ([]mdSegment, error) {
+	marker := "-"
+	switch b.BlockType {
+	case DocxBlockOrdered:
+		marker = fmt.Sprintf("%d.", number)
+	case DocxBlockTodo:
+		if b.Todo.Style != nil && b.Todo.Style.Done {
+			marker = "- [x]"
+		} else {
+			marker = "- [ ]"
+		}
+	}
+	lines := []string{marker + " " + docxInline(b.TextContent())}
+
+	children, err := w.render(b.Children)
+	if err != nil {
+		return nil, err
+	}
+	if len(children) > 0 {
+		for _, line := range strings.Split(joinSegments(children), "\n") {
+			if len(line) > 0 {
+				line = "    " + line
+			}
+			lines = append(lines, line)
+		}
+	}
+	return []mdSegment{{text: strings.Join(lines, "\n"), list: b.BlockType}}, nil
+}
+
+func (w *docxMarkdownWriter) renderCallout(b *DocxBlock) ([]mdSegment, error) {
+	alert := "NOTE"
+	for name, callout := range docxCallouts {
+		if callout.EmojiId == b.Callout.EmojiId {
+			alert = name
+			break
+		}
+	}
+	children, err := w.render(b.Children)
+	if err != nil {
+		return nil, err
+	}
+	text := "[!" + alert + "]"
+	if len(children) > 0 {
+		text += "\n" + joinSegments(children)
+	}
+	return []mdSegment{{text: quoteLines(text)}}, nil
+}
+
+func (w *docxMarkdownWriter) renderTable(b *DocxBlock) ([]mdSegment, error) {
+	columns := b.Table.Property.ColumnSize
+	cells := b.Table.Cells
+	if len(cells) == 0 {
+		cells = b.Children
+	}
+	if columns == 0 || len(cells) < columns {
+		return nil, nil
+	}
+
+	var (
+		rows   []string
+		aligns []string
+	)
+	for r := 0; r+columns <= len(cells); r += columns {
+		row := make([]string, columns)
+		for c := 0; c < columns; c++ {
+			text, align := w.renderCell(cells[r+c])
+			row[c] = strings.ReplaceAll(text, "|", `\|`)
+			if r == 0 {
+				aligns = append(aligns, docxMarkdownAligns[align])
+			}
+		}
+		rows = append(rows, "| "+strings.Join(row, " | ")+" |")
+		if r == 0 {
+			rows = append(rows, "| "+strings.Join(aligns, " | ")+" |")
+		}
+	}
+	return []mdSegment{{text: strings.Join(rows, "\n")}}, nil
+}
+
+// renderCell 单元格中的多个块用 <br> 连接，忽略空的文本块，对齐方式为第一个设置了对齐的块。
+func (w *docxMarkdownWriter) renderCell(id string) (string, int) {
+	cell, ok := w.blocks[id]
+	if !ok {
+		return "", 0
+	}
+	var (
+		texts []string
+		align int
+	)
+	for _, childId := range cell.Children {
+		child, ok := w.blocks[childId]
+		if !ok || child.TextContent() == nil || len(child.PlainText()) == 0 {
+			continue
+		}
+		text := child.TextContent()
+		texts = append(texts, docxInline(text))
+		if text.Style != nil && align == 0 {
+			align = text.Style.Align
+		}
+	}
+	return strings.Join(texts, "<br>"), align
+}
+
+func quoteLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if len(line) == 0 {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// docxInline 文本元素转换为行内 markdown。
+func docxInline(text *DocxText) string {
+	if text == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, e := range text.Elements {
+		switch {
+		case e.TextRun != nil:
+			b.WriteString(docxTextRun(e.TextRun))
+		case e.MentionUser != nil:
+			b.WriteString("@" + e.MentionUser.UserId)
+		case e.Equation != nil:
+			b.WriteString("$" + strings.TrimSpace(e.Equation.Content) + "$")
+		}
+	}
+	// 换行转换为硬换行
+	return strings.ReplaceAll(b.String(), "\n", "\\\n")
+}
+
+func docxTextRun(run *DocxTextRun) string {
+	style := run.TextElementStyle
+	if style == nil {
+		return docxMarkdownEscaper.Replace(run.Content)
+	}
+
+	content := run.Content
+	if style.InlineCode {
+		fence := "`"
+		for strings.Contains(content, fence) {
+			fence += "`"
+		}
+		if len(fence) > 1 {
+			content = " " + content + " "
+		}
+		content = fence + content + fence
+	} else {
+		content = docxMarkdownEscaper.Replace(content)
+	}
+	if style.Strikethrough {
+		content = wrapMarker(content, "~~")
+	}
+	if style.Italic {
+		content = wrapMarker(content, "*")
+	}
+	if style.Bold {
+		content = wrapMarker(content, "**")
+	}
+	if style.Link != nil && len(style.Link.Url) > 0 {
+		link, err := url.QueryUnescape(style.Link.Url)
+		if err != nil {
+			link = style.Link.Url
+		}
+		content = "[" + content + "](" + link + ")"
+	}
+	return content
+}
+
+// wrapMarker 首尾的空白放在标记外面。
+func wrapMarker(content, marker string) string {
+	trimmed := strings.TrimSpace(content)
+	if len(trimmed) == 0 {
+		return content
+	}
+	start := strings.Index(content, trimmed)
+	return content[:start] + marker + trimmed + marker + content[start+len(trimmed):]
+}
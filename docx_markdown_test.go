@@ -0,0 +1,270 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const releaseNotes = "# Release v1.2.0\n" +
+	"\n" +
+	"Highlights for **this** release, see [changelog](https://example.com/changelog?a=1&b=2).\n" +
+	"\n" +
+	"## Features\n" +
+	"\n" +
+	"- Drive *chunked* upload\n" +
+	"    - resumable `download`\n" +
+	"- Docx ~~beta~~ support\n" +
+	"\n" +
+	"1. first\n" +
+	"2. second\n" +
+	"\n" +
+	"- [x] tests\n" +
+	"- [ ] docs\n" +
+	"\n" +
+	"```go\n" +
+	"func main() {}\n" +
+	"```\n" +
+	"\n" +
+	"> quoted line\n" +
+	"\n" +
+	"> [!WARNING]\n" +
+	"> breaking change\n" +
+	"\n" +
+	"| Name | Status |\n" +
+	"| :--- | :---: |\n" +
+	"| drive | done |\n" +
+	"| docx |  |\n" +
+	"\n" +
+	"---\n" +
+	"\n" +
+	"![](https://example.com/chart.png)\n"
+
+// docxStore 模拟文档的块，按创建顺序返回。
+type docxStore struct {
+	mu     sync.Mutex
+	blocks map[string]*DocxBlock
+	order  []string
+	media  map[string][]byte
+	seq    int
+}
+
+func newDocxStore(t *testing.T, mux *http.ServeMux) *docxStore {
+	s := &docxStore{blocks: make(map[string]*DocxBlock), media: make(map[string][]byte)}
+	prefix := "/open-apis/docx/v1/documents"
+
+	mux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		opt := new(CreateDocumentOptions)
+		json.NewDecoder(r.Body).Decode(opt)
+		page := &DocxBlock{BlockId: testDocumentId, BlockType: DocxBlockPage, Page: NewDocxText(NewDocxTextRun(opt.Title, nil))}
+		s.add(page)
+		fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"document": {"document_id": "%s", "revision_id": 1, "title": "%s"}}}`, testDocumentId, opt.Title)
+	})
+	mux.HandleFunc(prefix+"/"+testDocumentId+"/blocks", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		items := make([]*DocxBlock, 0, len(s.order))
+		for _, id := range s.order {
+			items = append(items, s.blocks[id])
+		}
+		buf, _ := json.Marshal(items)
+		fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "items": %s}}`, buf)
+	})
+	mux.HandleFunc(prefix+"/"+testDocumentId+"/blocks/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, prefix+"/"+testDocumentId+"/blocks/")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/children"):
+			opt := new(CreateBlocksOptions)
+			json.NewDecoder(r.Body).Decode(opt)
+			created := s.createChildren(t, strings.TrimSuffix(path, "/children"), opt.Children)
+			buf, _ := json.Marshal(created)
+			fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"document_revision_id": 2, "children": %s}}`, buf)
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/children"):
+			s.mu.Lock()
+			var items []*DocxBlock
+			for _, id := range s.blocks[strings.TrimSuffix(path, "/children")].Children {
+				items = append(items, s.blocks[id])
+			}
+			buf, _ := json.Marshal(items)
+			s.mu.Unlock()
+			fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "items": %s}}`, buf)
+		case r.Method == http.MethodPatch && path == "batch_update":
+			opt := new(BatchUpdateBlocksOptions)
+			json.NewDecoder(r.Body).Decode(opt)
+			s.mu.Lock()
+			for _, req := range opt.Requests {
+				text := s.blocks[req.BlockId].Text
+				if req.UpdateTextElements != nil {
+					text.Elements = req.UpdateTextElements.Elements
+				}
+				if req.UpdateTextStyle != nil {
+					style := req.UpdateTextStyle.Style
+					text.Style = &style
+				}
+			}
+			s.mu.Unlock()
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+		case r.Method == http.MethodPatch:
+			opt := new(UpdateBlockOptions)
+			json.NewDecoder(r.Body).Decode(opt)
+			s.mu.Lock()
+			s.blocks[path].Image.Token = opt.ReplaceImage.Token
+			s.mu.Unlock()
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	mux.HandleFunc("/open-apis/drive/v1/medias/upload_all", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse multipart: %v", err)
+		}
+		if r.FormValue("parent_type") != DriveParentTypeDocxImage || r.FormValue("extra") != `{"drive_route_token":"`+testDocumentId+`"}` {
+			t.Errorf("form %v", r.MultipartForm.Value)
+		}
+		f, _, _ := r.FormFile("file")
+		content, _ := ioutil.ReadAll(f)
+		token := "boximg_" + r.FormValue("parent_node")
+		s.mu.Lock()
+		s.media[token] = content
+		s.mu.Unlock()
+		fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"file_token": "%s"}}`, token)
+	})
+	mux.HandleFunc("/open-apis/drive/v1/medias/", func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/open-apis/drive/v1/medias/"), "/download")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Write(s.media[token])
+	})
+	return s
+}
+
+func (s *docxStore) add(b *DocxBlock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocks[b.BlockId] = b
+	s.order = append(s.order, b.BlockId)
+}
+
+// addEmptyText 单元格和高亮块创建时自带一个空的文本块。
+func (s *docxStore) addEmptyText(parent *DocxBlock) {
+	text := &DocxBlock{BlockId: s.newId(), ParentId: parent.BlockId, BlockType: DocxBlockText, Text: NewDocxText()}
+	s.add(text)
+	parent.Children = append(parent.Children, text.BlockId)
+}
+
+func (s *docxStore) newId() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	return fmt.Sprintf("blk_%d", s.seq)
+}
+
+func (s *docxStore) createChildren(t *testing.T, parentId string, children []*DocxBlock) []*DocxBlock {
+	s.mu.Lock()
+	parent, ok := s.blocks[parentId]
+	s.mu.Unlock()
+	if !ok {
+		t.Errorf("parent %s not found", parentId)
+		return nil
+	}
+	for _, b := range children {
+		b.BlockId, b.ParentId = s.newId(), parentId
+		s.add(b)
+		parent.Children = append(parent.Children, b.BlockId)
+		if b.BlockType == DocxBlockTable {
+			for i := 0; i < b.Table.Property.RowSize*b.Table.Property.ColumnSize; i++ {
+				cell := &DocxBlock{BlockId: s.newId(), ParentId: b.BlockId, BlockType: DocxBlockTableCell, TableCell: &struct{}{}}
+				s.add(cell)
+				s.addEmptyText(cell)
+				b.Table.Cells = append(b.Table.Cells, cell.BlockId)
+				b.Children = append(b.Children, cell.BlockId)
+			}
+		}
+		if b.BlockType == DocxBlockCallout {
+			s.addEmptyText(b)
+		}
+	}
+	return children
+}
+
+func TestMarkdownToDocxBlocks(t *testing.T) {
+	Convey("test MarkdownToDocxBlocks", t, func() {
+		nodes := MarkdownToDocxBlocks(releaseNotes)
+		var types []int
+		for _, node := range nodes {
+			types = append(types, node.Block.BlockType)
+		}
+		So(types, ShouldResemble, []int{
+			DocxBlockHeading1, DocxBlockText, DocxBlockHeading1 + 1,
+			DocxBlockBullet, DocxBlockBullet, DocxBlockOrdered, DocxBlockOrdered, DocxBlockTodo, DocxBlockTodo,
+			DocxBlockCode, DocxBlockQuote, DocxBlockCallout, DocxBlockTable, DocxBlockDivider, DocxBlockImage,
+		})
+
+		text := nodes[1].Block.Text.Elements
+		So(text[1].TextRun.TextElementStyle.Bold, ShouldBeTrue)
+		So(text[3].TextRun.TextElementStyle.Link.Url, ShouldEqual, "https%3A%2F%2Fexample.com%2Fchangelog%3Fa%3D1%26b%3D2")
+
+		So(nodes[3].Children, ShouldHaveLength, 1)
+		So(nodes[3].Children[0].Block.PlainText(), ShouldEqual, "resumable download")
+		So(nodes[7].Block.Todo.Style.Done, ShouldBeTrue)
+		So(nodes[9].Block.Code.Style.Language, ShouldEqual, DocxCodeLanguage("go"))
+		So(nodes[11].Block.Callout.EmojiId, ShouldEqual, "warning")
+		So(nodes[11].Children[0].Block.PlainText(), ShouldEqual, "breaking change")
+
+		table := nodes[12]
+		So(table.Block.Table.Property, ShouldResemble, DocxTableProperty{RowSize: 3, ColumnSize: 2, HeaderRow: true})
+		So(table.Children, ShouldHaveLength, 6)
+		So(table.Children[3].Children[0].Block.Text.Style.Align, ShouldEqual, DocxAlignCenter)
+		So(table.Children[5].Children, ShouldBeEmpty)
+
+		So(nodes[14].ImageURL, ShouldEqual, "https://example.com/chart.png")
+	})
+}
+
+func TestDocxService_MarkdownRoundTrip(t *testing.T) {
+	Convey("test DocxService_MarkdownRoundTrip", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+		store := newDocxStore(t, mux)
+
+		chart := []byte("\x89PNG chart")
+		loader := func(url string) ([]byte, string, error) {
+			if url != "https://example.com/chart.png" {
+				return nil, "", fmt.Errorf("unexpected image %s", url)
+			}
+			return chart, "chart.png", nil
+		}
+
+		doc, err := client.Docx.CreateDocumentFromMarkdown("fldbc3", "", releaseNotes, loader)
+		So(err, ShouldBeNil)
+		So(doc.Title, ShouldEqual, "Release v1.2.0")
+
+		var saved []string
+		md, err := client.Docx.ExportMarkdown(doc.DocumentId, func(token string, content []byte) (string, error) {
+			saved = append(saved, token)
+			if string(content) != string(chart) {
+				return "", fmt.Errorf("unexpected content of %s", token)
+			}
+			return "https://example.com/chart.png", nil
+		})
+		So(err, ShouldBeNil)
+		So(md, ShouldEqual, releaseNotes)
+		So(saved, ShouldHaveLength, 1)
+		So(store.media, ShouldContainKey, saved[0])
+
+		Convey("images as links without loader", func() {
+			So(client.Docx.ImportMarkdown(doc.DocumentId, "![chart](https://example.com/chart.png)", nil), ShouldBeNil)
+			md, err := client.Docx.ExportMarkdown(doc.DocumentId, nil)
+			So(err, ShouldBeNil)
+			So(md, ShouldEndWith, "![]("+saved[0]+")\n\n[chart](https://example.com/chart.png)\n")
+		})
+	})
+}
@@ -0,0 +1,135 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocumentId = "doxcni6mOy7jLRWbEylaKKabcef"
+
+func TestDocxService_Document(t *testing.T) {
+	Convey("test DocxService_Document", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/docx/v1/documents", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"folder_token":"fldbc3","title":"周报"}`)
+			fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"document": {"document_id": "%s", "revision_id": 1, "title": "周报"}}}`, testDocumentId)
+		})
+		mux.HandleFunc("/open-apis/docx/v1/documents/"+testDocumentId+"/raw_content", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"content": "周报\n本周完成\n"}}`)
+		})
+
+		doc, _, err := client.Docx.CreateDocument(&CreateDocumentOptions{FolderToken: "fldbc3", Title: "周报"})
+		So(err, ShouldBeNil)
+		So(doc.Data.Document.DocumentId, ShouldEqual, testDocumentId)
+		So(doc.Data.Document.RevisionId, ShouldEqual, 1)
+
+		raw, _, err := client.Docx.RawContent(testDocumentId)
+		So(err, ShouldBeNil)
+		So(raw.Data.Content, ShouldEqual, "周报\n本周完成\n")
+	})
+}
+
+func TestDocxService_Blocks(t *testing.T) {
+	Convey("test DocxService_Blocks", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		blocksPath := "/open-apis/docx/v1/documents/" + testDocumentId + "/blocks"
+		mux.HandleFunc(blocksPath, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			if r.URL.Query().Get("page_token") == "" {
+				testParams(t, r, "page_size=500")
+				fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"has_more": true, "page_token": "p2", "items": [
+					{"block_id": "%s", "block_type": 1, "children": ["b1", "b2"], "page": {"elements": [{"text_run": {"content": "周报"}}]}},
+					{"block_id": "b1", "parent_id": "%s", "block_type": 4, "heading2": {"elements": [{"text_run": {"content": "本周"}}, {"text_run": {"content": "完成", "text_element_style": {"bold": true}}}]}}
+				]}}`, testDocumentId, testDocumentId)
+				return
+			}
+			fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "items": [
+				{"block_id": "b2", "parent_id": "%s", "block_type": 31, "children": ["c1", "c2"], "table": {"cells": ["c1", "c2"], "property": {"row_size": 1, "column_size": 2}}}
+			]}}`, testDocumentId)
+		})
+		mux.HandleFunc(blocksPath+"/b1", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"block": {"block_id": "b1", "block_type": 2, "text": {"elements": [{"text_run": {"content": "text"}}]}}}}`)
+			case http.MethodPatch:
+				testParams(t, r, "document_revision_id=-1")
+				testBody(t, r, `{"update_text_elements":{"elements":[{"text_run":{"content":"new"}}]}}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"document_revision_id": 3, "block": {"block_id": "b1", "block_type": 2, "text": {"elements": [{"text_run": {"content": "new"}}]}}}}`)
+			default:
+				t.Errorf("unexpected method %s", r.Method)
+			}
+		})
+		mux.HandleFunc(blocksPath+"/"+testDocumentId+"/children", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "client_token=0e2633a3-aa1a-4171-af9e-0b2f5a8ab3a2")
+			testBody(t, r, `{"children":[{"block_type":4,"heading2":{"elements":[{"text_run":{"content":"下周计划"}}]}},{"block_type":12,"bullet":{"elements":[{"text_run":{"content":"发布"}},{"text_run":{"content":"v1.0","text_element_style":{"inline_code":true}}}]}},{"block_type":14,"code":{"style":{"language":22,"wrap":true},"elements":[{"text_run":{"content":"go test ./..."}}]}},{"block_type":19,"callout":{"background_color":2,"emoji_id":"bulb"}},{"block_type":27,"image":{}},{"block_type":31,"table":{"property":{"row_size":2,"column_size":3,"header_row":true}}}],"index":-1}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"document_revision_id": 2, "children": [{"block_id": "b3", "block_type": 4}, {"block_id": "b4", "block_type": 12}]}}`)
+		})
+		mux.HandleFunc(blocksPath+"/batch_update", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPatch)
+			testBody(t, r, `{"requests":[{"block_id":"b5","replace_image":{"token":"boxbcimg"}},{"block_id":"b6","update_text_style":{"style":{"done":true},"fields":[2]}}]}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"document_revision_id": 4, "blocks": [{"block_id": "b5", "block_type": 27, "image": {"token": "boxbcimg"}}]}}`)
+		})
+		mux.HandleFunc(blocksPath+"/"+testDocumentId+"/children/batch_delete", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodDelete)
+			testParams(t, r, "document_revision_id=4")
+			testBody(t, r, `{"start_index":0,"end_index":2}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"document_revision_id": 5}}`)
+		})
+
+		blocks, err := client.Docx.AllBlocks(testDocumentId)
+		So(err, ShouldBeNil)
+		So(blocks, ShouldHaveLength, 3)
+		So(blocks[0].PlainText(), ShouldEqual, "周报")
+		So(blocks[1].PlainText(), ShouldEqual, "本周完成")
+		So(blocks[1].Heading2.Elements[1].TextRun.TextElementStyle.Bold, ShouldBeTrue)
+		So(blocks[2].Table.Property.ColumnSize, ShouldEqual, 2)
+		So(blocks[2].PlainText(), ShouldEqual, "")
+
+		block, _, err := client.Docx.GetBlock(testDocumentId, "b1")
+		So(err, ShouldBeNil)
+		So(block.Data.Block.PlainText(), ShouldEqual, "text")
+
+		created, _, err := client.Docx.CreateBlocks(testDocumentId, testDocumentId, &DocxQueryOptions{ClientToken: "0e2633a3-aa1a-4171-af9e-0b2f5a8ab3a2"}, &CreateBlocksOptions{
+			Index: -1,
+			Children: []*DocxBlock{
+				NewDocxHeadingBlock(2, NewDocxTextRun("下周计划", nil)),
+				NewDocxBulletBlock(NewDocxTextRun("发布", nil), NewDocxTextRun("v1.0", &DocxTextElementStyle{InlineCode: true})),
+				NewDocxCodeBlock("Go", "go test ./..."),
+				NewDocxCalloutBlock("bulb", 2),
+				NewDocxImageBlock(),
+				NewDocxTableBlock(2, 3),
+			},
+		})
+		So(err, ShouldBeNil)
+		So(created.Data.Children[1].BlockId, ShouldEqual, "b4")
+
+		updated, _, err := client.Docx.UpdateBlock(testDocumentId, "b1", &DocxQueryOptions{DocumentRevisionId: -1}, &UpdateBlockOptions{
+			UpdateTextElements: &DocxUpdateTextElements{Elements: []DocxTextElement{NewDocxTextRun("new", nil)}},
+		})
+		So(err, ShouldBeNil)
+		So(updated.Data.DocumentRevisionId, ShouldEqual, 3)
+
+		batch, _, err := client.Docx.BatchUpdateBlocks(testDocumentId, nil, &BatchUpdateBlocksOptions{Requests: []*UpdateBlockOptions{
+			{BlockId: "b5", ReplaceImage: &DocxReplaceImage{Token: "boxbcimg"}},
+			{BlockId: "b6", UpdateTextStyle: &DocxUpdateTextStyle{Style: DocxTextStyle{Done: true}, Fields: []int{2}}},
+		}})
+		So(err, ShouldBeNil)
+		So(batch.Data.Blocks[0].Image.Token, ShouldEqual, "boxbcimg")
+
+		deleted, _, err := client.Docx.DeleteBlocks(testDocumentId, testDocumentId, &DocxQueryOptions{DocumentRevisionId: 4}, &DeleteBlocksOptions{StartIndex: 0, EndIndex: 2})
+		So(err, ShouldBeNil)
+		So(deleted.Data.DocumentRevisionId, ShouldEqual, 5)
+	})
+}
@@ -0,0 +1,574 @@
+package feishu
+
+import (
+	"bytes"
+	"fmt"
+	"hash/adler32"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// 文件类型
+const (
+	DriveFileTypeFile     = "file"
+	DriveFileTypeDoc      = "doc"
+	DriveFileTypeDocx     = "docx"
+	DriveFileTypeSheet    = "sheet"
+	DriveFileTypeBitable  = "bitable"
+	DriveFileTypeMindnote = "mindnote"
+	DriveFileTypeFolder   = "folder"
+	DriveFileTypeShortcut = "shortcut"
+)
+
+// 上传点类型，explorer 为云空间文件夹，docx_image 为文档中的图片块
+const (
+	DriveParentTypeExplorer  = "explorer"
+	DriveParentTypeDocxImage = "docx_image"
+	DriveParentTypeDocxFile  = "docx_file"
+)
+
+// 异步任务状态
+const (
+	DriveTaskSuccess = "success"
+	DriveTaskFail    = "fail"
+	DriveTaskProcess = "process"
+)
+
+// 小于 20MB 的文件一次上传，否则分片上传
+var driveUploadAllMaxSize int64 = 20 * 1024 * 1024
+
+type DriveService struct {
+	client *Client
+}
+
+type DriveFile struct {
+	Token        string `json:"token"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	ParentToken  string `json:"parent_token"`
+	URL          string `json:"url"`
+	ShortcutInfo *struct {
+		TargetType  string `json:"target_type"`
+		TargetToken string `json:"target_token"`
+	} `json:"shortcut_info,omitempty"`
+	CreatedTime  string `json:"created_time"`
+	ModifiedTime string `json:"modified_time"`
+	OwnerId      string `json:"owner_id"`
+}
+
+// ListFilesOptions FolderToken 为空时查询根目录，OrderBy 为 EditedTime、CreatedTime，Direction 为 ASC、DESC。
+type ListFilesOptions struct {
+	FolderToken string `url:"folder_token,omitempty"`
+	PageSize    int    `url:"page_size,omitempty"`
+	PageToken   string `url:"page_token,omitempty"`
+	OrderBy     string `url:"order_by,omitempty"`
+	Direction   string `url:"direction,omitempty"`
+}
+
+type ListFilesResponse struct {
+	CodeMsg
+	Data struct {
+		Files         []DriveFile `json:"files"`
+		NextPageToken string      `json:"next_page_token"`
+		HasMore       bool        `json:"has_more"`
+	} `json:"data"`
+}
+
+// ListFiles 查询文件夹中的文件。
+func (s *DriveService) ListFiles(opt *ListFilesOptions, options ...RequestOptionFunc) (*ListFilesResponse, *Response, error) {
+	u := "drive/v1/files"
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListFilesResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AllFiles 翻页查询文件夹中的所有文件。
+func (s *DriveService) AllFiles(folderToken string, options ...RequestOptionFunc) ([]DriveFile, error) {
+	opt := &ListFilesOptions{FolderToken: folderToken, PageSize: 200}
+
+	var files []DriveFile
+	for {
+		rsp, _, err := s.ListFiles(opt, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("list files of %s: %d %s", folderToken, rsp.Code, rsp.Message)
+		}
+		files = append(files, rsp.Data.Files...)
+		if !rsp.Data.HasMore || len(rsp.Data.NextPageToken) == 0 {
+			return files, nil
+		}
+		opt.PageToken = rsp.Data.NextPageToken
+	}
+}
+
+type CreateFolderOptions struct {
+	Name        string `json:"name"`
+	FolderToken string `json:"folder_token"`
+}
+
+type CreateFolderResponse struct {
+	CodeMsg
+	Data struct {
+		Token string `json:"token"`
+		URL   string `json:"url"`
+	} `json:"data"`
+}
+
+// CreateFolder 在 FolderToken 中创建文件夹。
+func (s *DriveService) CreateFolder(opt *CreateFolderOptions, options ...RequestOptionFunc) (*CreateFolderResponse, *Response, error) {
+	u := "drive/v1/files/create_folder"
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(CreateFolderResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DriveTaskResponse 移动、删除文件夹是异步任务，通过 TaskCheck 查询任务状态。
+type DriveTaskResponse struct {
+	CodeMsg
+	Data struct {
+		TaskId string `json:"task_id"`
+	} `json:"data"`
+}
+
+type MoveFileOptions struct {
+	Type        string `json:"type"`
+	FolderToken string `json:"folder_token"`
+}
+
+// MoveFile 移动文件或文件夹到 FolderToken 中。
+func (s *DriveService) MoveFile(fileToken string, opt *MoveFileOptions, options ...RequestOptionFunc) (*DriveTaskResponse, *Response, error) {
+	u := fmt.Sprintf("drive/v1/files/%s/move", fileToken)
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(DriveTaskResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+type CopyFileOptions struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	FolderToken string `json:"folder_token"`
+}
+
+type CopyFileResponse struct {
+	CodeMsg
+	Data struct {
+		File DriveFile `json:"file"`
+	} `json:"data"`
+}
+
+// CopyFile 复制文件到 FolderToken 中，不支持复制文件夹。
+func (s *DriveService) CopyFile(fileToken string, opt *CopyFileOptions, options ...RequestOptionFunc) (*CopyFileResponse, *Response, error) {
+	u := fmt.Sprintf("drive/v1/files/%s/copy", fileToken)
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(CopyFileResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+type DeleteFileOptions struct {
+	Type string `url:"type"`
+}
+
+// DeleteFile 删除文件或文件夹，fileType 为 DriveFileType*。
+func (s *DriveService) DeleteFile(fileToken, fileType string, options ...RequestOptionFunc) (*DriveTaskResponse, *Response, error) {
+	u := fmt.Sprintf("drive/v1/files/%s", fileToken)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, &DeleteFileOptions{Type: fileType}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(DriveTaskResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+type TaskCheckOptions struct {
+	TaskId string `url:"task_id"`
+}
+
+type TaskCheckResponse struct {
+	CodeMsg
+	Data struct {
+		Status string `json:"status"`
+	} `json:"data"`
+}
+
+// TaskCheck 查询异步任务状态，Status 为 DriveTask*。
+func (s *DriveService) TaskCheck(taskId string, options ...RequestOptionFunc) (*TaskCheckResponse, *Response, error) {
+	u := "drive/v1/files/task_check"
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, &TaskCheckOptions{TaskId: taskId}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(TaskCheckResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UploadAllOptions Checksum 为文件的 Adler-32 校验和，可选。
+type UploadAllOptions struct {
+	FileName   string `url:"file_name"`
+	ParentType string `url:"parent_type"`
+	ParentNode string `url:"parent_node"`
+	Size       int64  `url:"size"`
+	Checksum   string `url:"checksum,omitempty"`
+}
+
+type UploadResponse struct {
+	CodeMsg
+	Data struct {
+		FileToken string `json:"file_token"`
+	} `json:"data"`
+}
+
+// UploadAll 一次上传不超过 20MB 的文件。
+func (s *DriveService) UploadAll(opt *UploadAllOptions, content io.Reader, options ...RequestOptionFunc) (*UploadResponse, *Response, error) {
+	u := "drive/v1/files/upload_all"
+	if opt == nil {
+		return nil, nil, errors.New("upload options are required")
+	}
+
+	req, err := s.client.NewServerUploadRequest(http.MethodPost, u, opt, "file", opt.FileName, content, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(UploadResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+type UploadPrepareOptions struct {
+	FileName   string `json:"file_name"`
+	ParentType string `json:"parent_type"`
+	ParentNode string `json:"parent_node"`
+	Size       int64  `json:"size"`
+}
+
+type UploadPrepareResponse struct {
+	CodeMsg
+	Data struct {
+		UploadId  string `json:"upload_id"`
+		BlockSize int64  `json:"block_size"`
+		BlockNum  int    `json:"block_num"`
+	} `json:"data"`
+}
+
+// UploadPrepare 分片上传预上传，返回分片大小和分片数。
+func (s *DriveService) UploadPrepare(opt *UploadPrepareOptions, options ...RequestOptionFunc) (*UploadPrepareResponse, *Response, error) {
+	u := "drive/v1/files/upload_prepare"
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(UploadPrepareResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UploadPartOptions Seq 从 0 开始，除最后一片外 Size 为 BlockSize。
+type UploadPartOptions struct {
+	UploadId string `url:"upload_id"`
+	Seq      int    `url:"seq"`
+	Size     int64  `url:"size"`
+	Checksum string `url:"checksum,omitempty"`
+}
+
+// UploadPart 上传一个分片，失败时可以重新上传该分片。
+func (s *DriveService) UploadPart(opt *UploadPartOptions, content io.Reader, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := "drive/v1/files/upload_part"
+
+	req, err := s.client.NewServerUploadRequest(http.MethodPost, u, opt, "file", strconv.Itoa(opt.Seq), content, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+type UploadFinishOptions struct {
+	UploadId string `json:"upload_id"`
+	BlockNum int    `json:"block_num"`
+}
+
+// UploadFinish 完成分片上传，返回文件 token。
+func (s *DriveService) UploadFinish(opt *UploadFinishOptions, options ...RequestOptionFunc) (*UploadResponse, *Response, error) {
+	u := "drive/v1/files/upload_finish"
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(UploadResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UploadFile 上传 size 字节的文件到文件夹，小文件一次上传，大文件分片上传，返回文件 token。
+func (s *DriveService) UploadFile(folderToken, fileName string, r io.Reader, size int64, options ...RequestOptionFunc) (string, error) {
+	if size <= driveUploadAllMaxSize {
+		buf, err := ioutil.ReadAll(io.LimitReader(r, size))
+		if err != nil {
+			return "", err
+		}
+		if int64(len(buf)) != size {
+			return "", errors.Errorf("upload %s: read %d bytes, want %d", fileName, len(buf), size)
+		}
+		rsp, _, err := s.UploadAll(&UploadAllOptions{
+			FileName:   fileName,
+			ParentType: DriveParentTypeExplorer,
+			ParentNode: folderToken,
+			Size:       size,
+			Checksum:   Adler32Checksum(buf),
+		}, bytes.NewReader(buf), options...)
+		if err != nil {
+			return "", err
+		}
+		if rsp.Code != 0 {
+			return "", errors.Errorf("upload %s: %d %s", fileName, rsp.Code, rsp.Message)
+		}
+		return rsp.Data.FileToken, nil
+	}
+
+	prepared, _, err := s.UploadPrepare(&UploadPrepareOptions{
+		FileName:   fileName,
+		ParentType: DriveParentTypeExplorer,
+		ParentNode: folderToken,
+		Size:       size,
+	}, options...)
+	if err != nil {
+		return "", err
+	}
+	if prepared.Code != 0 {
+		return "", errors.Errorf("upload prepare %s: %d %s", fileName, prepared.Code, prepared.Message)
+	}
+
+	uploadId, blockNum := prepared.Data.UploadId, prepared.Data.BlockNum
+	buf := make([]byte, prepared.Data.BlockSize)
+	for seq := 0; seq < blockNum; seq++ {
+		n, err := io.ReadFull(r, buf)
+		// 最后一片可以不满
+		if err != nil && !(err == io.ErrUnexpectedEOF && seq == blockNum-1) {
+			return "", errors.Wrapf(err, "upload %s: read part %d", fileName, seq)
+		}
+		part := buf[:n]
+		rsp, _, err := s.UploadPart(&UploadPartOptions{
+			UploadId: uploadId,
+			Seq:      seq,
+			Size:     int64(n),
+			Checksum: Adler32Checksum(part),
+		}, bytes.NewReader(part), options...)
+		if err != nil {
+			return "", err
+		}
+		if rsp.Code != 0 {
+			return "", errors.Errorf("upload part %d of %s: %d %s", seq, fileName, rsp.Code, rsp.Message)
+		}
+	}
+
+	finished, _, err := s.UploadFinish(&UploadFinishOptions{UploadId: uploadId, BlockNum: blockNum}, options...)
+	if err != nil {
+		return "", err
+	}
+	if finished.Code != 0 {
+		return "", errors.Errorf("upload finish %s: %d %s", fileName, finished.Code, finished.Message)
+	}
+	return finished.Data.FileToken, nil
+}
+
+// DownloadFile 下载文件写入 w，使用 WithRange 下载部分内容。
+func (s *DriveService) DownloadFile(fileToken string, w io.Writer, options ...RequestOptionFunc) (*Response, error) {
+	u := fmt.Sprintf("drive/v1/files/%s/download", fileToken)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, w)
+}
+
+// DownloadToFile 下载文件到 path，path 已存在时从文件末尾继续下载，大小和远端不一致时重新下载。
+func (s *DriveService) DownloadToFile(fileToken, path string, options ...RequestOptionFunc) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if offset == 0 {
+		_, err = s.DownloadFile(fileToken, f, options...)
+		return err
+	}
+
+	w := &resumeWriter{f: f}
+	resp, err := s.DownloadFile(fileToken, w, append(options, WithRange(offset, -1))...)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		return err
+	}
+	// 本地文件和远端大小相同时已经下载完成，否则本地文件不是同一个文件，重新下载
+	var size int64
+	if _, e := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes */%d", &size); e == nil && size == offset {
+		return nil
+	}
+	if err = w.restart(); err != nil {
+		return err
+	}
+	_, err = s.DownloadFile(fileToken, f, options...)
+	return err
+}
+
+// resumeWriter 续传时写入文件，服务端不支持 Range 返回完整内容时先清空文件，避免写入重复的内容。
+type resumeWriter struct {
+	f *os.File
+}
+
+func (w *resumeWriter) receive(resp *Response) error {
+	if resp.StatusCode == http.StatusPartialContent {
+		return nil
+	}
+	return w.restart()
+}
+
+func (w *resumeWriter) restart() error {
+	if err := w.f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := w.f.Seek(0, io.SeekStart)
+	return err
+}
+
+func (w *resumeWriter) Write(p []byte) (int, error) {
+	return w.f.Write(p)
+}
+
+// UploadMediaOptions 上传素材，ParentNode 为上传点的 token，如图片块的 BlockId，
+// Extra 为额外信息，上传到文档时为 {"drive_route_token":"<DocumentId>"}。
+type UploadMediaOptions struct {
+	FileName   string `url:"file_name"`
+	ParentType string `url:"parent_type"`
+	ParentNode string `url:"parent_node"`
+	Size       int64  `url:"size"`
+	Checksum   string `url:"checksum,omitempty"`
+	Extra      string `url:"extra,omitempty"`
+}
+
+// UploadMedia 上传不超过 20MB 的素材，如文档中的图片。
+func (s *DriveService) UploadMedia(opt *UploadMediaOptions, content io.Reader, options ...RequestOptionFunc) (*UploadResponse, *Response, error) {
+	u := "drive/v1/medias/upload_all"
+	if opt == nil {
+		return nil, nil, errors.New("upload options are required")
+	}
+
+	req, err := s.client.NewServerUploadRequest(http.MethodPost, u, opt, "file", opt.FileName, content, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(UploadResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DownloadMedia 下载素材写入 w。
+func (s *DriveService) DownloadMedia(fileToken string, w io.Writer, options ...RequestOptionFunc) (*Response, error) {
+	u := fmt.Sprintf("drive/v1/medias/%s/download", fileToken)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, w)
+}
+
+// Adler32Checksum 上传文件时的校验和。
+func Adler32Checksum(b []byte) string {
+	return strconv.FormatUint(uint64(adler32.Checksum(b)), 10)
+}
@@ -0,0 +1,340 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// 协作者类型
+const (
+	MemberTypeEmail      = "email"
+	MemberTypeOpenId     = "openid"
+	MemberTypeUnionId    = "unionid"
+	MemberTypeUserId     = "userid"
+	MemberTypeOpenChat   = "openchat"
+	MemberTypeDepartment = "opendepartmentid"
+)
+
+// 协作者权限
+const (
+	PermView       = "view"
+	PermEdit       = "edit"
+	PermFullAccess = "full_access"
+)
+
+// userIdMemberType user_id_type 对应的协作者类型。
+var userIdMemberType = map[string]string{
+	"open_id":  MemberTypeOpenId,
+	"union_id": MemberTypeUnionId,
+	"user_id":  MemberTypeUserId,
+}
+
+type PermissionMember struct {
+	MemberType string `json:"member_type"`
+	MemberId   string `json:"member_id,omitempty"`
+	Perm       string `json:"perm,omitempty"`
+	Type       string `json:"type,omitempty"` // user、chat、department、group
+	Name       string `json:"name,omitempty"`
+	Avatar     string `json:"avatar,omitempty"`
+}
+
+// NewPermissionMembers 把 ContactService.BatchGetId 查询到的用户转换为协作者，忽略未找到的用户。
+func NewPermissionMembers(userIdType, perm string, users []User) ([]PermissionMember, error) {
+	memberType, ok := userIdMemberType[userIdType]
+	if !ok {
+		return nil, errors.Errorf("unsupported user id type %s", userIdType)
+	}
+	members := make([]PermissionMember, 0, len(users))
+	for _, user := range users {
+		if len(user.UserId) == 0 {
+			continue
+		}
+		members = append(members, PermissionMember{MemberType: memberType, MemberId: user.UserId, Perm: perm})
+	}
+	return members, nil
+}
+
+// PermissionQueryOptions Type 为文件类型 DriveFileType*。
+type PermissionQueryOptions struct {
+	Type             string `url:"type"`
+	NeedNotification bool   `url:"need_notification,omitempty"`
+	MemberType       string `url:"member_type,omitempty"`
+}
+
+type PermissionMemberResponse struct {
+	CodeMsg
+	Data struct {
+		Member PermissionMember `json:"member"`
+	} `json:"data"`
+}
+
+type PermissionMembersResponse struct {
+	CodeMsg
+	Data struct {
+		Items []PermissionMember `json:"items"`
+	} `json:"data"`
+}
+
+// ListPermissionMembers 查询协作者。
+func (s *DriveService) ListPermissionMembers(token, fileType string, options ...RequestOptionFunc) (*PermissionMembersResponse, *Response, error) {
+	u := fmt.Sprintf("drive/v1/permissions/%s/members", token)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, &PermissionQueryOptions{Type: fileType}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(PermissionMembersResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AddPermissionMember 添加协作者，已是协作者时更新权限。
+func (s *DriveService) AddPermissionMember(token string, query *PermissionQueryOptions, opt *PermissionMember, options ...RequestOptionFunc) (*PermissionMemberResponse, *Response, error) {
+	u := fmt.Sprintf("drive/v1/permissions/%s/members", token)
+	options = append(options, WithQuery(query))
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(PermissionMemberResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+type BatchPermissionMembersOptions struct {
+	Members []PermissionMember `json:"members"`
+}
+
+// BatchAddPermissionMembers 批量添加协作者。
+func (s *DriveService) BatchAddPermissionMembers(token string, query *PermissionQueryOptions, opt *BatchPermissionMembersOptions, options ...RequestOptionFunc) (*PermissionMembersResponse, *Response, error) {
+	u := fmt.Sprintf("drive/v1/permissions/%s/members/batch_create", token)
+	options = append(options, WithQuery(query))
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(PermissionMembersResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UpdatePermissionMember 更新协作者权限，opt 中需要 MemberType 和 Perm。
+func (s *DriveService) UpdatePermissionMember(token, memberId string, query *PermissionQueryOptions, opt *PermissionMember, options ...RequestOptionFunc) (*PermissionMemberResponse, *Response, error) {
+	u := fmt.Sprintf("drive/v1/permissions/%s/members/%s", token, memberId)
+	options = append(options, WithQuery(query))
+
+	req, err := s.client.NewServerRequest(http.MethodPut, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(PermissionMemberResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// RemovePermissionMember 移除协作者，query 中需要 MemberType。
+func (s *DriveService) RemovePermissionMember(token, memberId string, query *PermissionQueryOptions, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := fmt.Sprintf("drive/v1/permissions/%s/members/%s", token, memberId)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, query, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AddPermissionMembersByEmail 通过 ContactService.BatchGetId 把邮箱转换为 open_id 后批量添加协作者，
+// 返回未找到的邮箱。
+func (s *DriveService) AddPermissionMembersByEmail(token, fileType, perm string, emails []string, options ...RequestOptionFunc) ([]string, error) {
+	users, err := s.batchGetUsersByEmail(emails, options...)
+	if err != nil {
+		return nil, err
+	}
+	members, err := NewPermissionMembers("open_id", perm, users)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, user := range users {
+		if len(user.UserId) == 0 {
+			missing = append(missing, user.Email)
+		}
+	}
+	if len(members) == 0 {
+		return missing, nil
+	}
+
+	rsp, _, err := s.BatchAddPermissionMembers(token, &PermissionQueryOptions{Type: fileType},
+		&BatchPermissionMembersOptions{Members: members}, options...)
+	if err != nil {
+		return missing, err
+	}
+	if rsp.Code != 0 {
+		return missing, errors.Errorf("add members to %s: %d %s", token, rsp.Code, rsp.Message)
+	}
+	return missing, nil
+}
+
+// 每次最多查询 50 个邮箱
+const batchGetIdMaxEmails = 50
+
+func (s *DriveService) batchGetUsersByEmail(emails []string, options ...RequestOptionFunc) ([]User, error) {
+	var users []User
+	for i := 0; i < len(emails); i += batchGetIdMaxEmails {
+		end := i + batchGetIdMaxEmails
+		if end > len(emails) {
+			end = len(emails)
+		}
+		rsp, _, err := s.client.Contact.BatchGetId("open_id", &BatchGetIdOptions{Emails: emails[i:end]}, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("batch get id: %d %s", rsp.Code, rsp.Message)
+		}
+		users = append(users, rsp.Data.UserList...)
+	}
+	return users, nil
+}
+
+// PermissionPublic 公共设置。
+type PermissionPublic struct {
+	ExternalAccess  *bool  `json:"external_access,omitempty"`
+	SecurityEntity  string `json:"security_entity,omitempty"`   // anyone_can_view、anyone_can_edit、only_full_access
+	CommentEntity   string `json:"comment_entity,omitempty"`    // anyone_can_view、anyone_can_edit
+	ShareEntity     string `json:"share_entity,omitempty"`      // anyone、same_tenant、only_full_access
+	LinkShareEntity string `json:"link_share_entity,omitempty"` // tenant_readable、tenant_editable、anyone_readable、anyone_editable、closed
+	InviteExternal  *bool  `json:"invite_external,omitempty"`
+}
+
+type PermissionPublicResponse struct {
+	CodeMsg
+	Data struct {
+		PermissionPublic PermissionPublic `json:"permission_public"`
+	} `json:"data"`
+}
+
+// GetPermissionPublic 查询公共设置。
+func (s *DriveService) GetPermissionPublic(token, fileType string, options ...RequestOptionFunc) (*PermissionPublicResponse, *Response, error) {
+	u := fmt.Sprintf("drive/v1/permissions/%s/public", token)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, &PermissionQueryOptions{Type: fileType}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(PermissionPublicResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UpdatePermissionPublic 更新公共设置，只更新非空字段，如设置链接分享。
+func (s *DriveService) UpdatePermissionPublic(token, fileType string, opt *PermissionPublic, options ...RequestOptionFunc) (*PermissionPublicResponse, *Response, error) {
+	u := fmt.Sprintf("drive/v1/permissions/%s/public", token)
+	options = append(options, WithQuery(&PermissionQueryOptions{Type: fileType}))
+
+	req, err := s.client.NewServerRequest(http.MethodPatch, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(PermissionPublicResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// TransferOwnerQueryOptions RemoveOldOwner 为 false 时原所有者保留 OldOwnerPerm 权限，
+// StayPut 为 true 时文件留在原所有者的空间中。
+type TransferOwnerQueryOptions struct {
+	Type             string `url:"type"`
+	NeedNotification bool   `url:"need_notification"`
+	RemoveOldOwner   bool   `url:"remove_old_owner"`
+	StayPut          bool   `url:"stay_put"`
+	OldOwnerPerm     string `url:"old_owner_perm,omitempty"`
+}
+
+// TransferOwner 转移所有者，opt 中需要 MemberType 和 MemberId，如员工离职时转移文档。
+func (s *DriveService) TransferOwner(token string, query *TransferOwnerQueryOptions, opt *PermissionMember, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := fmt.Sprintf("drive/v1/permissions/%s/members/transfer_owner", token)
+	options = append(options, WithQuery(query))
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// TransferOwnerByEmail 转移所有者给邮箱对应的用户，原所有者保留编辑权限。
+func (s *DriveService) TransferOwnerByEmail(token, fileType, email string, options ...RequestOptionFunc) error {
+	users, err := s.batchGetUsersByEmail([]string{email}, options...)
+	if err != nil {
+		return err
+	}
+	members, err := NewPermissionMembers("open_id", "", users)
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return errors.Errorf("user of %s not found", email)
+	}
+
+	rsp, _, err := s.TransferOwner(token, &TransferOwnerQueryOptions{
+		Type:             fileType,
+		NeedNotification: true,
+		OldOwnerPerm:     PermEdit,
+	}, &members[0], options...)
+	if err != nil {
+		return err
+	}
+	if rsp.Code != 0 {
+		return errors.Errorf("transfer owner of %s to %s: %d %s", token, email, rsp.Code, rsp.Message)
+	}
+	return nil
+}
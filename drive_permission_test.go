@@ -0,0 +1,121 @@
+package feishu
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocToken = "doxcnAJ9VRRJqVMYZ1MyKnavXWe"
+
+func TestDriveService_PermissionMembers(t *testing.T) {
+	Convey("test DriveService_PermissionMembers", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		membersPath := "/open-apis/drive/v1/permissions/" + testDocToken + "/members"
+		mux.HandleFunc(membersPath, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				testParams(t, r, "type=docx")
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"items": [{"member_type": "openid", "member_id": "ou_1", "perm": "full_access", "type": "user"}]}}`)
+			case http.MethodPost:
+				testParams(t, r, "need_notification=true&type=docx")
+				testBody(t, r, `{"member_type":"email","member_id":"zhangsan@a.com","perm":"edit"}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"member": {"member_type": "email", "member_id": "zhangsan@a.com", "perm": "edit"}}}`)
+			default:
+				t.Errorf("unexpected method %s", r.Method)
+			}
+		})
+		mux.HandleFunc(membersPath+"/ou_1", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPut:
+				testParams(t, r, "type=docx")
+				testBody(t, r, `{"member_type":"openid","perm":"view"}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"member": {"member_type": "openid", "member_id": "ou_1", "perm": "view"}}}`)
+			case http.MethodDelete:
+				testParams(t, r, "member_type=openid&type=docx")
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+			default:
+				t.Errorf("unexpected method %s", r.Method)
+			}
+		})
+
+		list, _, err := client.Drive.ListPermissionMembers(testDocToken, DriveFileTypeDocx)
+		So(err, ShouldBeNil)
+		So(list.Data.Items[0].Perm, ShouldEqual, PermFullAccess)
+
+		added, _, err := client.Drive.AddPermissionMember(testDocToken, &PermissionQueryOptions{Type: DriveFileTypeDocx, NeedNotification: true},
+			&PermissionMember{MemberType: MemberTypeEmail, MemberId: "zhangsan@a.com", Perm: PermEdit})
+		So(err, ShouldBeNil)
+		So(added.Data.Member.Perm, ShouldEqual, PermEdit)
+
+		updated, _, err := client.Drive.UpdatePermissionMember(testDocToken, "ou_1", &PermissionQueryOptions{Type: DriveFileTypeDocx},
+			&PermissionMember{MemberType: MemberTypeOpenId, Perm: PermView})
+		So(err, ShouldBeNil)
+		So(updated.Data.Member.Perm, ShouldEqual, PermView)
+
+		removed, _, err := client.Drive.RemovePermissionMember(testDocToken, "ou_1", &PermissionQueryOptions{Type: DriveFileTypeDocx, MemberType: MemberTypeOpenId})
+		So(err, ShouldBeNil)
+		So(removed.Code, ShouldEqual, 0)
+	})
+}
+
+func TestDriveService_PermissionsByEmail(t *testing.T) {
+	Convey("test DriveService_PermissionsByEmail", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/contact/v3/users/batch_get_id", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "user_id_type=open_id")
+			body, _ := ioutil.ReadAll(r.Body)
+			switch string(body) {
+			case `{"emails":["zhangsan@a.com","nobody@a.com"]}`:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"user_list": [{"email": "zhangsan@a.com", "user_id": "ou_1"}, {"email": "nobody@a.com"}]}}`)
+			case `{"emails":["lisi@a.com"]}`:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"user_list": [{"email": "lisi@a.com", "user_id": "ou_2"}]}}`)
+			default:
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"user_list": [{"email": "left@a.com"}]}}`)
+			}
+		})
+		mux.HandleFunc("/open-apis/drive/v1/permissions/"+testDocToken+"/members/batch_create", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "type=docx")
+			testBody(t, r, `{"members":[{"member_type":"openid","member_id":"ou_1","perm":"edit"}]}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"items": [{"member_type": "openid", "member_id": "ou_1", "perm": "edit"}]}}`)
+		})
+		mux.HandleFunc("/open-apis/drive/v1/permissions/"+testDocToken+"/members/transfer_owner", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "need_notification=true&old_owner_perm=edit&remove_old_owner=false&stay_put=false&type=docx")
+			testBody(t, r, `{"member_type":"openid","member_id":"ou_2"}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+		})
+		mux.HandleFunc("/open-apis/drive/v1/permissions/"+testDocToken+"/public", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPatch)
+			testParams(t, r, "type=docx")
+			testBody(t, r, `{"external_access":false,"link_share_entity":"tenant_readable"}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"permission_public": {"external_access": false, "link_share_entity": "tenant_readable", "share_entity": "anyone"}}}`)
+		})
+
+		missing, err := client.Drive.AddPermissionMembersByEmail(testDocToken, DriveFileTypeDocx, PermEdit, []string{"zhangsan@a.com", "nobody@a.com"})
+		So(err, ShouldBeNil)
+		So(missing, ShouldResemble, []string{"nobody@a.com"})
+
+		So(client.Drive.TransferOwnerByEmail(testDocToken, DriveFileTypeDocx, "lisi@a.com"), ShouldBeNil)
+		So(client.Drive.TransferOwnerByEmail(testDocToken, DriveFileTypeDocx, "left@a.com"), ShouldNotBeNil)
+
+		external := false
+		public, _, err := client.Drive.UpdatePermissionPublic(testDocToken, DriveFileTypeDocx, &PermissionPublic{ExternalAccess: &external, LinkShareEntity: "tenant_readable"})
+		So(err, ShouldBeNil)
+		So(public.Data.PermissionPublic.ShareEntity, ShouldEqual, "anyone")
+
+		_, err = NewPermissionMembers("email", PermView, nil)
+		So(err, ShouldNotBeNil)
+	})
+}
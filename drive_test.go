@@ -0,0 +1,235 @@
+package feishu
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDriveService_Files(t *testing.T) {
+	Convey("test DriveService_Files", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/drive/v1/files", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			if r.URL.Query().Get("page_token") == "" {
+				testParams(t, r, "folder_token=fldbcO1UuPz8VwnpPx5a92abcef&page_size=200")
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"files": [{"token": "boxbc1", "name": "a.tar.gz", "type": "file"}], "next_page_token": "p2", "has_more": true}}`)
+				return
+			}
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"files": [{"token": "fldbc2", "name": "logs", "type": "folder"}], "has_more": false}}`)
+		})
+		mux.HandleFunc("/open-apis/drive/v1/files/create_folder", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"name":"v1.0.0","folder_token":"fldbcO1UuPz8VwnpPx5a92abcef"}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"token": "fldbc3", "url": "https://feishu.cn/drive/folder/fldbc3"}}`)
+		})
+		mux.HandleFunc("/open-apis/drive/v1/files/boxbc1/move", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"type":"file","folder_token":"fldbc3"}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"task_id": "12345"}}`)
+		})
+		mux.HandleFunc("/open-apis/drive/v1/files/boxbc1/copy", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"file": {"token": "boxbc4", "name": "b.tar.gz", "type": "file", "parent_token": "fldbc3"}}}`)
+		})
+		mux.HandleFunc("/open-apis/drive/v1/files/fldbc2", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodDelete)
+			testParams(t, r, "type=folder")
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"task_id": "12346"}}`)
+		})
+		mux.HandleFunc("/open-apis/drive/v1/files/task_check", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "task_id=12346")
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"status": "success"}}`)
+		})
+
+		files, err := client.Drive.AllFiles("fldbcO1UuPz8VwnpPx5a92abcef")
+		So(err, ShouldBeNil)
+		So(files, ShouldHaveLength, 2)
+		So(files[1].Type, ShouldEqual, DriveFileTypeFolder)
+
+		folder, _, err := client.Drive.CreateFolder(&CreateFolderOptions{Name: "v1.0.0", FolderToken: "fldbcO1UuPz8VwnpPx5a92abcef"})
+		So(err, ShouldBeNil)
+		So(folder.Data.Token, ShouldEqual, "fldbc3")
+
+		moved, _, err := client.Drive.MoveFile("boxbc1", &MoveFileOptions{Type: DriveFileTypeFile, FolderToken: "fldbc3"})
+		So(err, ShouldBeNil)
+		So(moved.Data.TaskId, ShouldEqual, "12345")
+
+		copied, _, err := client.Drive.CopyFile("boxbc1", &CopyFileOptions{Name: "b.tar.gz", Type: DriveFileTypeFile, FolderToken: "fldbc3"})
+		So(err, ShouldBeNil)
+		So(copied.Data.File.Token, ShouldEqual, "boxbc4")
+
+		deleted, _, err := client.Drive.DeleteFile("fldbc2", DriveFileTypeFolder)
+		So(err, ShouldBeNil)
+		task, _, err := client.Drive.TaskCheck(deleted.Data.TaskId)
+		So(err, ShouldBeNil)
+		So(task.Data.Status, ShouldEqual, DriveTaskSuccess)
+	})
+}
+
+func TestDriveService_UploadFile(t *testing.T) {
+	Convey("test DriveService_UploadFile", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		content := bytes.Repeat([]byte("0123456789"), 100)
+
+		_, _, err := client.Drive.UploadAll(nil, bytes.NewReader(content))
+		So(err, ShouldNotBeNil)
+		_, _, err = client.Drive.UploadMedia(nil, bytes.NewReader(content))
+		So(err, ShouldNotBeNil)
+
+		mux.HandleFunc("/open-apis/drive/v1/files/upload_all", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("parse multipart: %v", err)
+			}
+			f, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("form file: %v", err)
+			}
+			buf, _ := ioutil.ReadAll(f)
+			if header.Filename != "small.txt" || !bytes.Equal(buf, content) {
+				t.Errorf("file %s: %d bytes", header.Filename, len(buf))
+			}
+			if got, want := r.FormValue("checksum"), Adler32Checksum(content); got != want {
+				t.Errorf("checksum %s, want %s", got, want)
+			}
+			if r.FormValue("parent_type") != DriveParentTypeExplorer || r.FormValue("parent_node") != "fldbc3" || r.FormValue("size") != "1000" {
+				t.Errorf("form %v", r.MultipartForm.Value)
+			}
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"file_token": "boxsmall"}}`)
+		})
+
+		var parts [][]byte
+		mux.HandleFunc("/open-apis/drive/v1/files/upload_prepare", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"file_name":"big.tar.gz","parent_type":"explorer","parent_node":"fldbc3","size":1000}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"upload_id": "u1", "block_size": 400, "block_num": 3}}`)
+		})
+		mux.HandleFunc("/open-apis/drive/v1/files/upload_part", func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("parse multipart: %v", err)
+			}
+			f, _, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("form file: %v", err)
+			}
+			buf, _ := ioutil.ReadAll(f)
+			if r.FormValue("upload_id") != "u1" || r.FormValue("seq") != fmt.Sprint(len(parts)) ||
+				r.FormValue("size") != fmt.Sprint(len(buf)) || r.FormValue("checksum") != Adler32Checksum(buf) {
+				t.Errorf("part form %v", r.MultipartForm.Value)
+			}
+			parts = append(parts, buf)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+		})
+		mux.HandleFunc("/open-apis/drive/v1/files/upload_finish", func(w http.ResponseWriter, r *http.Request) {
+			testBody(t, r, `{"upload_id":"u1","block_num":3}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"file_token": "boxbig"}}`)
+		})
+
+		token, err := client.Drive.UploadFile("fldbc3", "small.txt", bytes.NewReader(content), int64(len(content)))
+		So(err, ShouldBeNil)
+		So(token, ShouldEqual, "boxsmall")
+
+		_, err = client.Drive.UploadFile("fldbc3", "small.txt", bytes.NewReader(content[:10]), int64(len(content)))
+		So(err, ShouldNotBeNil)
+
+		max := driveUploadAllMaxSize
+		driveUploadAllMaxSize = 100
+		defer func() { driveUploadAllMaxSize = max }()
+
+		token, err = client.Drive.UploadFile("fldbc3", "big.tar.gz", bytes.NewReader(content), int64(len(content)))
+		So(err, ShouldBeNil)
+		So(token, ShouldEqual, "boxbig")
+		So(parts, ShouldHaveLength, 3)
+		So(parts[2], ShouldHaveLength, 200)
+		So(bytes.Join(parts, nil), ShouldResemble, content)
+	})
+}
+
+func TestDriveService_DownloadToFile(t *testing.T) {
+	Convey("test DriveService_DownloadToFile", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		content := []byte("build artifact content")
+		var ranges []string
+		mux.HandleFunc("/open-apis/drive/v1/files/boxbc1/download", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			rng := r.Header.Get("Range")
+			ranges = append(ranges, rng)
+			if rng == "" {
+				w.Write(content)
+				return
+			}
+			var start int
+			fmt.Sscanf(rng, "bytes=%d-", &start)
+			if start >= len(content) {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(content)))
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "msg": "range not satisfiable"})
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(content[start:])
+		})
+
+		path := filepath.Join(t.TempDir(), "artifact")
+		So(ioutil.WriteFile(path, content[:6], 0644), ShouldBeNil)
+
+		So(client.Drive.DownloadToFile("boxbc1", path), ShouldBeNil)
+		got, _ := ioutil.ReadFile(path)
+		So(string(got), ShouldEqual, string(content))
+
+		// 已经下载完成
+		So(client.Drive.DownloadToFile("boxbc1", path), ShouldBeNil)
+		got, _ = ioutil.ReadFile(path)
+		So(string(got), ShouldEqual, string(content))
+
+		So(os.Remove(path), ShouldBeNil)
+		So(client.Drive.DownloadToFile("boxbc1", path), ShouldBeNil)
+		got, _ = ioutil.ReadFile(path)
+		So(string(got), ShouldEqual, string(content))
+		So(ranges, ShouldResemble, []string{"bytes=6-", "bytes=22-", ""})
+
+		// 本地文件比远端大，不是同一个文件，重新下载
+		So(ioutil.WriteFile(path, []byte("stale artifact with more bytes"), 0644), ShouldBeNil)
+		So(client.Drive.DownloadToFile("boxbc1", path), ShouldBeNil)
+		got, _ = ioutil.ReadFile(path)
+		So(string(got), ShouldEqual, string(content))
+		So(ranges[3:], ShouldResemble, []string{"bytes=30-", ""})
+
+		// 服务端忽略 Range 返回完整内容时只下载一次，不会追加到已有内容后面
+		full := 0
+		mux.HandleFunc("/open-apis/drive/v1/files/boxbc2/download", func(w http.ResponseWriter, r *http.Request) {
+			full++
+			w.Write(content)
+		})
+		So(ioutil.WriteFile(path, content[:6], 0644), ShouldBeNil)
+		So(client.Drive.DownloadToFile("boxbc2", path), ShouldBeNil)
+		got, _ = ioutil.ReadFile(path)
+		So(string(got), ShouldEqual, string(content))
+		So(full, ShouldEqual, 1)
+
+		var buf bytes.Buffer
+		resp, err := client.Drive.DownloadFile("boxbc1", &buf, WithRange(6, -1))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusPartialContent)
+		So(buf.String(), ShouldEqual, "artifact content")
+	})
+}
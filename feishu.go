@@ -1,6 +1,7 @@
 package feishu
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"io"
 	"io/ioutil"
 	"math/rand"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"sort"
@@ -54,14 +56,37 @@ type Client struct {
 	// Server API use access token.
 	accessTokenManager AccessTokenManager
 
+	// App API use app access token, e.g. exchange OAuth code for user access token.
+	appAccessTokenManager AccessTokenManager
+
+	// JS-SDK jsapi_ticket, cached like tenant access token.
+	jsapiTicketManager AccessTokenManager
+
+	// Bot webhook secrets by bot key, consulted on every signed send.
+	botSecrets map[string]CredentialProvider
+
+	// appId of the self-built app, used to build OAuth authorize URL.
+	appId string
+
+	// UserAccessTokens stores user access tokens obtained from OAuth login.
+	UserAccessTokens *UserAccessTokenStore
+
 	// User agent used when communicating with the GitLab API.
 	UserAgent string
 
 	// Services used for talking to different parts of the GitLab API.
-	Auth    *AuthService
-	Contact *ContactService
-	Bot     *BotService
-	App     *AppService
+	Auth        *AuthService
+	Contact     *ContactService
+	Bot         *BotService
+	App         *AppService
+	Mina        *MinaService
+	Message     *MessageService
+	Calendar    *CalendarService
+	MeetingRoom *MeetingRoomService
+	Drive       *DriveService
+	Docx        *DocxService
+	Sheets      *SheetsService
+	Bitable     *BitableService
 }
 
 // RateLimiter describes the interface that all (custom) rate limiters must implement.
@@ -89,6 +114,11 @@ func NewRedisCacheClient(appId, appSecret, addr, password string, options ...Cli
 	return NewClient(options...)
 }
 
+func NewProviderClient(appId string, provider CredentialProvider, options ...ClientOptionFunc) (*Client, error) {
+	options = append(options, WithTenantAccessTokenProvider(appId, provider))
+	return NewClient(options...)
+}
+
 func newClient(options ...ClientOptionFunc) (*Client, error) {
 	c := &Client{UserAgent: userAgent}
 
@@ -121,6 +151,14 @@ func newClient(options ...ClientOptionFunc) (*Client, error) {
 	c.Contact = &ContactService{client: c}
 	c.Bot = &BotService{client: c}
 	c.App = &AppService{client: c}
+	c.Mina = &MinaService{client: c}
+	c.Message = &MessageService{client: c}
+	c.Calendar = &CalendarService{client: c}
+	c.MeetingRoom = &MeetingRoomService{client: c}
+	c.Drive = &DriveService{client: c}
+	c.Docx = &DocxService{client: c}
+	c.Sheets = &SheetsService{client: c}
+	c.Bitable = &BitableService{client: c}
 
 	return c, nil
 }
@@ -279,7 +317,7 @@ func (c *Client) NewRequest(method, path string, opt interface{}, options []Requ
 
 	var body interface{}
 	switch {
-	case method == http.MethodPost || method == http.MethodPut:
+	case method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch:
 		reqHeaders.Set("Content-Type", "application/json")
 
 		if opt != nil {
@@ -319,6 +357,85 @@ func (c *Client) NewRequest(method, path string, opt interface{}, options []Requ
 	return req, nil
 }
 
+// NewUploadRequest creates a new multipart/form-data API request. The fields
+// of opt are encoded as form fields by their url tags, followed by content as
+// the file field named fieldName.
+func (c *Client) NewUploadRequest(method, path string, opt interface{}, fieldName, filename string, content io.Reader, options []RequestOptionFunc) (*retryablehttp.Request, error) {
+	u := *c.baseURL
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the encoded path data
+	u.RawPath = c.baseURL.Path + path
+	u.Path = c.baseURL.Path + unescaped
+
+	// Create a request specific headers map.
+	reqHeaders := make(http.Header)
+	reqHeaders.Set("Accept", "application/json")
+
+	if c.UserAgent != "" {
+		reqHeaders.Set("User-Agent", c.UserAgent)
+	}
+
+	b := new(bytes.Buffer)
+	w := multipart.NewWriter(b)
+
+	if opt != nil {
+		q, err := query.Values(opt)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(q))
+		for k := range q {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, v := range q[k] {
+				if err = w.WriteField(k, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// 文件必须是最后一个字段
+	fw, err := w.CreateFormFile(fieldName, filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(fw, content); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	reqHeaders.Set("Content-Type", w.FormDataContentType())
+
+	req, err := retryablehttp.NewRequest(method, u.String(), b.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fn := range options {
+		if fn == nil {
+			continue
+		}
+		if err := fn(req); err != nil {
+			return nil, err
+		}
+	}
+
+	// Set the request specific headers.
+	for k, v := range reqHeaders {
+		req.Header[k] = v
+	}
+
+	return req, nil
+}
+
 // Server API request
 func (c *Client) NewServerRequest(method, path string, opt interface{}, options []RequestOptionFunc) (*retryablehttp.Request, error) {
 	if c.accessTokenManager == nil {
@@ -329,7 +446,40 @@ func (c *Client) NewServerRequest(method, path string, opt interface{}, options
 	if err, token := c.accessTokenManager.GetAccessToken(); err != nil {
 		return nil, err
 	} else {
-		options = append(options, WithToken(token))
+		// 放在最前面，调用者可以用 WithToken/WithUserAccessToken 覆盖。
+		options = append([]RequestOptionFunc{WithToken(token)}, options...)
+	}
+
+	return c.NewRequest(method, path, opt, options)
+}
+
+// Server API upload request, authorized by tenant_access_token.
+func (c *Client) NewServerUploadRequest(method, path string, opt interface{}, fieldName, filename string, content io.Reader, options []RequestOptionFunc) (*retryablehttp.Request, error) {
+	if c.accessTokenManager == nil {
+		err := errors.New("NewClient with token manager first! WithTenantAccessTokenInternal(appId, appSecret)")
+		return nil, err
+	}
+
+	if err, token := c.accessTokenManager.GetAccessToken(); err != nil {
+		return nil, err
+	} else {
+		options = append([]RequestOptionFunc{WithToken(token)}, options...)
+	}
+
+	return c.NewUploadRequest(method, path, opt, fieldName, filename, content, options)
+}
+
+// App API request, authorized by app_access_token.
+func (c *Client) NewAppServerRequest(method, path string, opt interface{}, options []RequestOptionFunc) (*retryablehttp.Request, error) {
+	if c.appAccessTokenManager == nil {
+		err := errors.New("NewClient with token manager first! WithTenantAccessTokenInternal(appId, appSecret)")
+		return nil, err
+	}
+
+	if err, token := c.appAccessTokenManager.GetAccessToken(); err != nil {
+		return nil, err
+	} else {
+		options = append([]RequestOptionFunc{WithToken(token)}, options...)
 	}
 
 	return c.NewRequest(method, path, opt, options)
@@ -367,6 +517,11 @@ func (c *Client) Do(req *retryablehttp.Request, v interface{}) (*Response, error
 	}
 
 	if v != nil {
+		if r, ok := v.(responseReceiver); ok {
+			if err = r.receive(response); err != nil {
+				return response, err
+			}
+		}
 		if w, ok := v.(io.Writer); ok {
 			_, err = io.Copy(w, resp.Body)
 		} else {
@@ -377,6 +532,11 @@ func (c *Client) Do(req *retryablehttp.Request, v interface{}) (*Response, error
 	return response, err
 }
 
+// responseReceiver 在写入响应内容之前收到响应，如根据状态码决定写入位置。
+type responseReceiver interface {
+	receive(resp *Response) error
+}
+
 // Response is a GitLab API response. This wraps the standard http.Response
 // returned from GitLab and provides convenient access to things like
 // pagination links.
@@ -431,7 +591,7 @@ func (e *ErrorResponse) Error() string {
 // CheckResponse checks the API response for errors, and returns them if present.
 func CheckResponse(r *http.Response) error {
 	switch r.StatusCode {
-	case 200, 201, 202, 204, 304:
+	case 200, 201, 202, 204, 206, 304:
 		return nil
 	}
 
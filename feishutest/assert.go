@@ -0,0 +1,51 @@
+package feishutest
+
+import (
+	"strings"
+	"testing"
+)
+
+// AssertMessageCount 检查收到的消息总数。
+func (s *Server) AssertMessageCount(t testing.TB, want int) {
+	t.Helper()
+	if got := len(s.Messages()); got != want {
+		t.Errorf("feishutest: received %d messages, want %d", got, want)
+	}
+}
+
+// AssertWebhookMessage 检查机器人 key 收到过包含 substr 的消息，返回匹配的第一条。
+func (s *Server) AssertWebhookMessage(t testing.TB, key, substr string) *Message {
+	t.Helper()
+	messages := s.WebhookMessages(key)
+	if m := findContent(messages, substr); m != nil {
+		return m
+	}
+	t.Errorf("feishutest: webhook %s received no message containing %q, got %d messages", key, substr, len(messages))
+	return nil
+}
+
+// AssertAppMessage 检查 receiveId 收到过包含 substr 的应用消息，返回匹配的第一条。
+func (s *Server) AssertAppMessage(t testing.TB, receiveId, substr string) *Message {
+	t.Helper()
+	messages := s.AppMessages(receiveId)
+	if m := findContent(messages, substr); m != nil {
+		return m
+	}
+	t.Errorf("feishutest: %s received no message containing %q, got %d messages", receiveId, substr, len(messages))
+	return nil
+}
+
+// AssertNoMessages 检查没有收到任何消息。
+func (s *Server) AssertNoMessages(t testing.TB) {
+	t.Helper()
+	s.AssertMessageCount(t, 0)
+}
+
+func findContent(messages []*Message, substr string) *Message {
+	for _, m := range messages {
+		if strings.Contains(m.Content, substr) || strings.Contains(plainText(m.Content), substr) {
+			return m
+		}
+	}
+	return nil
+}
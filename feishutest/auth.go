@@ -0,0 +1,132 @@
+package feishutest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 鉴权相关错误码
+const (
+	CodeAppSecretInvalid   = 10014
+	CodeTokenMissing       = 99991661
+	CodeTenantTokenInvalid = 99991663
+	CodeAppTokenInvalid    = 99991664
+	CodeTokenExpired       = 99991677
+)
+
+// 刷新剩余有效期不足 30 分钟的 token 时才签发新的，和飞书一致
+const tokenReuseThreshold = 30 * time.Minute
+
+type tokenType string
+
+const (
+	tokenTenant tokenType = "tenant"
+	tokenApp    tokenType = "app"
+)
+
+type token struct {
+	value    string
+	appId    string
+	typ      tokenType
+	expireAt time.Time
+}
+
+// AddApp 注册应用，注册后获取 token 时会校验 appSecret；未注册任何应用时接受任意应用。
+func (s *Server) AddApp(appId, appSecret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apps[appId] = appSecret
+}
+
+// ExpireTokens 使已签发的 token 全部失效，用于测试 token 刷新。
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]*token)
+}
+
+func (s *Server) handleAccessToken(typ tokenType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeCode(w, http.StatusMethodNotAllowed, 404, "method not allowed")
+			return
+		}
+
+		var req struct {
+			AppId     string `json:"app_id"`
+			AppSecret string `json:"app_secret"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.AppId) == 0 {
+			writeCode(w, http.StatusBadRequest, 10003, "invalid param")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if len(s.apps) > 0 {
+			if secret, ok := s.apps[req.AppId]; !ok || secret != req.AppSecret {
+				writeCode(w, http.StatusOK, CodeAppSecretInvalid, "app secret invalid")
+				return
+			}
+		}
+
+		t := s.issueToken(req.AppId, typ)
+		expire := int((t.expireAt.Sub(s.Now()) + time.Second - 1) / time.Second)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"code":                        0,
+			"msg":                         "ok",
+			"expire":                      expire,
+			string(typ) + "_access_token": t.value,
+		})
+	}
+}
+
+// issueToken 剩余有效期充足时返回原来的 token，否则签发新的。
+func (s *Server) issueToken(appId string, typ tokenType) *token {
+	now := s.Now()
+	for _, t := range s.tokens {
+		if t.appId == appId && t.typ == typ && t.expireAt.Sub(now) > tokenReuseThreshold {
+			return t
+		}
+	}
+	prefix := "t"
+	if typ == tokenApp {
+		prefix = "a"
+	}
+	t := &token{
+		value:    s.newId(prefix),
+		appId:    appId,
+		typ:      typ,
+		expireAt: now.Add(s.TokenExpire),
+	}
+	s.tokens[t.value] = t
+	return t
+}
+
+// authorized 校验 tenant_access_token。
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") {
+			writeCode(w, http.StatusBadRequest, CodeTokenMissing, "Missing access token for authorization")
+			return
+		}
+
+		s.mu.Lock()
+		t, ok := s.tokens[strings.TrimPrefix(auth, "Bearer ")]
+		now := s.Now()
+		s.mu.Unlock()
+
+		switch {
+		case !ok || t.typ != tokenTenant:
+			writeCode(w, http.StatusBadRequest, CodeTenantTokenInvalid, "Invalid access token for authorization")
+		case !now.Before(t.expireAt):
+			writeCode(w, http.StatusBadRequest, CodeTokenExpired, "token expired")
+		default:
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package feishutest
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// User 通讯录中的用户。
+type User struct {
+	OpenId  string
+	UnionId string
+	UserId  string
+	Name    string
+	Email   string
+	Mobile  string
+}
+
+// AddUser 添加用户，OpenId 为空时自动生成。
+func (s *Server) AddUser(user User) *User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := user
+	if len(u.OpenId) == 0 {
+		u.OpenId = s.newId("ou")
+	}
+	s.users = append(s.users, &u)
+	return &u
+}
+
+func (u *User) id(idType string) string {
+	switch idType {
+	case "union_id":
+		return u.UnionId
+	case "user_id":
+		return u.UserId
+	default:
+		return u.OpenId
+	}
+}
+
+func (s *Server) handleBatchGetId(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeCode(w, http.StatusMethodNotAllowed, 404, "method not allowed")
+		return
+	}
+
+	var req struct {
+		Emails  []string `json:"emails"`
+		Mobiles []string `json:"mobiles"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeCode(w, http.StatusBadRequest, 99992402, "field validation failed")
+		return
+	}
+	if len(req.Emails) > 50 || len(req.Mobiles) > 50 {
+		writeCode(w, http.StatusBadRequest, 99992402, "field validation failed: at most 50 emails and 50 mobiles")
+		return
+	}
+	idType := r.URL.Query().Get("user_id_type")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]interface{}, 0, len(req.Emails)+len(req.Mobiles))
+	lookup := func(field, value string, match func(*User) bool) {
+		item := map[string]interface{}{field: value}
+		for _, u := range s.users {
+			if match(u) {
+				item["user_id"] = u.id(idType)
+				break
+			}
+		}
+		list = append(list, item)
+	}
+	for _, mobile := range req.Mobiles {
+		lookup("mobile", mobile, func(u *User) bool { return u.Mobile == mobile })
+	}
+	for _, email := range req.Emails {
+		lookup("email", email, func(u *User) bool { return u.Email == email })
+	}
+	writeData(w, map[string]interface{}{"user_list": list})
+}
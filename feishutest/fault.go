@@ -0,0 +1,74 @@
+package feishutest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault 注入的故障，匹配的请求直接返回 StatusCode 和飞书错误码。
+type Fault struct {
+	// HTTP 状态码，默认 200
+	StatusCode int
+	// 飞书错误码和错误信息
+	Code int
+	Msg  string
+	// 设置 RateLimit-Reset 头，客户端据此等待
+	RateLimitReset time.Duration
+	// 生效次数，0 表示一直生效
+	Times int
+}
+
+// 常用的故障
+var (
+	FaultRateLimited  = Fault{StatusCode: http.StatusTooManyRequests, Code: 99991400, Msg: "request trigger frequency limit", RateLimitReset: time.Second}
+	FaultServerError  = Fault{StatusCode: http.StatusInternalServerError, Code: 1, Msg: "internal error"}
+	FaultTokenInvalid = Fault{StatusCode: http.StatusBadRequest, Code: CodeTenantTokenInvalid, Msg: "Invalid access token for authorization"}
+)
+
+type fault struct {
+	Fault
+	path  string
+	count int
+}
+
+// InjectFault 为 path（不包含 /open-apis/ 前缀，按前缀匹配）注入故障，后注入的优先。
+func (s *Server) InjectFault(path string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append([]*fault{{Fault: f, path: strings.TrimPrefix(path, "/")}}, s.faults...)
+}
+
+// ClearFaults 清除所有故障。
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+func (s *Server) matchFault(path string) *fault {
+	for i, f := range s.faults {
+		if !strings.HasPrefix(path, f.path) {
+			continue
+		}
+		f.count++
+		if f.Times > 0 && f.count >= f.Times {
+			s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) writeFault(w http.ResponseWriter, f *fault) {
+	if f.RateLimitReset > 0 {
+		reset := s.Now().Add(f.RateLimitReset)
+		w.Header().Set("RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	}
+	status := f.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	writeCode(w, status, f.Code, f.Msg)
+}
@@ -0,0 +1,284 @@
+package feishutest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Message 服务器收到的一条消息。
+type Message struct {
+	MessageId string
+	// webhook 消息为机器人的 key，应用消息为空
+	BotKey string
+	// 应用消息的接收者
+	ReceiveIdType string
+	ReceiveId     string
+
+	MsgType string
+	// 消息内容的 json，webhook 卡片消息为 card 的 json
+	Content string
+	Time    time.Time
+}
+
+// Text 返回文本消息的内容。
+func (m *Message) Text() string {
+	var c struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(m.Content), &c); err != nil {
+		return ""
+	}
+	return c.Text
+}
+
+// Chat 群组，Members 为成员的 open_id。
+type Chat struct {
+	ChatId      string
+	Name        string
+	Description string
+	OwnerId     string
+	Members     []string
+}
+
+// AddChat 添加群组，ChatId 为空时自动生成。
+func (s *Server) AddChat(chat Chat) *Chat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := chat
+	if len(c.ChatId) == 0 {
+		c.ChatId = s.newId("oc")
+	}
+	s.chats = append(s.chats, &c)
+	return &c
+}
+
+// Messages 返回收到的所有消息。
+func (s *Server) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Message(nil), s.messages...)
+}
+
+// AppMessages 返回发给 receiveId 的应用消息。
+func (s *Server) AppMessages(receiveId string) []*Message {
+	return s.filter(func(m *Message) bool { return len(m.BotKey) == 0 && m.ReceiveId == receiveId })
+}
+
+// Message 按 message_id 查找消息。
+func (s *Server) Message(messageId string) *Message {
+	for _, m := range s.Messages() {
+		if m.MessageId == messageId {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *Server) filter(fn func(*Message) bool) []*Message {
+	var messages []*Message
+	for _, m := range s.Messages() {
+		if fn(m) {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
+
+func (s *Server) record(m *Message) {
+	s.messages = append(s.messages, m)
+}
+
+// paginate 使用 offset 作为 page_token。
+func paginate(r *http.Request, total int) (start, end int, pageToken string, hasMore bool, ok bool) {
+	q := r.URL.Query()
+	size := defaultPageSize
+	if v := q.Get("page_size"); len(v) > 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxPageSize {
+			return 0, 0, "", false, false
+		}
+		size = n
+	}
+	if v := q.Get("page_token"); len(v) > 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > total {
+			return 0, 0, "", false, false
+		}
+		start = n
+	}
+	end = start + size
+	if end >= total {
+		return start, total, "", false, true
+	}
+	return start, end, strconv.Itoa(end), true, true
+}
+
+type messageRequest struct {
+	ReceiveId string `json:"receive_id"`
+	MsgType   string `json:"msg_type"`
+	Content   string `json:"content"`
+}
+
+func messageData(m *Message) map[string]interface{} {
+	data := map[string]interface{}{
+		"message_id":  m.MessageId,
+		"msg_type":    m.MsgType,
+		"create_time": strconv.FormatInt(m.Time.UnixNano()/int64(time.Millisecond), 10),
+		"body":        map[string]interface{}{"content": m.Content},
+	}
+	if m.ReceiveIdType == "chat_id" {
+		data["chat_id"] = m.ReceiveId
+	}
+	return data
+}
+
+func (s *Server) handleMessages(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.sendMessage(w, r)
+	case http.MethodGet:
+		s.listMessages(w, r)
+	default:
+		writeCode(w, http.StatusMethodNotAllowed, 404, "method not allowed")
+	}
+}
+
+func (s *Server) sendMessage(w http.ResponseWriter, r *http.Request) {
+	receiveIdType := r.URL.Query().Get("receive_id_type")
+	if len(receiveIdType) == 0 {
+		writeCode(w, http.StatusBadRequest, 99992402, "field validation failed: receive_id_type is required")
+		return
+	}
+
+	req := new(messageRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil || len(req.ReceiveId) == 0 || len(req.MsgType) == 0 {
+		writeCode(w, http.StatusBadRequest, 230001, "invalid message")
+		return
+	}
+	if !json.Valid([]byte(req.Content)) {
+		writeCode(w, http.StatusBadRequest, 230001, "content is not a valid json")
+		return
+	}
+
+	s.mu.Lock()
+	m := &Message{
+		MessageId:     s.newId("om"),
+		ReceiveIdType: receiveIdType,
+		ReceiveId:     req.ReceiveId,
+		MsgType:       req.MsgType,
+		Content:       req.Content,
+		Time:          s.Now(),
+	}
+	s.record(m)
+	s.mu.Unlock()
+
+	writeData(w, messageData(m))
+}
+
+// listMessages 获取群内的历史消息，container_id_type=chat。
+func (s *Server) listMessages(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("container_id_type") != "chat" || len(q.Get("container_id")) == 0 {
+		writeCode(w, http.StatusBadRequest, 99992402, "field validation failed: container_id is required")
+		return
+	}
+	chatId := q.Get("container_id")
+	messages := s.filter(func(m *Message) bool { return m.ReceiveIdType == "chat_id" && m.ReceiveId == chatId })
+
+	start, end, pageToken, hasMore, ok := paginate(r, len(messages))
+	if !ok {
+		writeCode(w, http.StatusBadRequest, 99992402, "field validation failed: invalid page_size or page_token")
+		return
+	}
+	items := make([]interface{}, 0, end-start)
+	for _, m := range messages[start:end] {
+		items = append(items, messageData(m))
+	}
+	writeData(w, map[string]interface{}{"items": items, "page_token": pageToken, "has_more": hasMore})
+}
+
+func chatData(c *Chat) map[string]interface{} {
+	return map[string]interface{}{
+		"chat_id":       c.ChatId,
+		"name":          c.Name,
+		"description":   c.Description,
+		"owner_id":      c.OwnerId,
+		"owner_id_type": "open_id",
+		"external":      false,
+	}
+}
+
+func (s *Server) handleListChats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeCode(w, http.StatusMethodNotAllowed, 404, "method not allowed")
+		return
+	}
+
+	s.mu.Lock()
+	chats := append([]*Chat(nil), s.chats...)
+	s.mu.Unlock()
+
+	start, end, pageToken, hasMore, ok := paginate(r, len(chats))
+	if !ok {
+		writeCode(w, http.StatusBadRequest, 99992402, "field validation failed: invalid page_size or page_token")
+		return
+	}
+	items := make([]interface{}, 0, end-start)
+	for _, c := range chats[start:end] {
+		items = append(items, chatData(c))
+	}
+	writeData(w, map[string]interface{}{"items": items, "page_token": pageToken, "has_more": hasMore})
+}
+
+// handleChat 处理 im/v1/chats/:chat_id 和 im/v1/chats/:chat_id/members。
+func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeCode(w, http.StatusMethodNotAllowed, 404, "method not allowed")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, apiPrefix+"im/v1/chats/"), "/")
+	var chat *Chat
+	s.mu.Lock()
+	for _, c := range s.chats {
+		if c.ChatId == parts[0] {
+			chat = c
+		}
+	}
+	s.mu.Unlock()
+	if chat == nil {
+		writeCode(w, http.StatusBadRequest, 232011, "chat not found")
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		writeData(w, chatData(chat))
+	case len(parts) == 2 && parts[1] == "members":
+		start, end, pageToken, hasMore, ok := paginate(r, len(chat.Members))
+		if !ok {
+			writeCode(w, http.StatusBadRequest, 99992402, "field validation failed: invalid page_size or page_token")
+			return
+		}
+		items := make([]interface{}, 0, end-start)
+		for _, member := range chat.Members[start:end] {
+			items = append(items, map[string]interface{}{"member_id_type": "open_id", "member_id": member})
+		}
+		writeData(w, map[string]interface{}{
+			"items":        items,
+			"page_token":   pageToken,
+			"has_more":     hasMore,
+			"member_total": len(chat.Members),
+		})
+	default:
+		writeCode(w, http.StatusNotFound, 404, "not found")
+	}
+}
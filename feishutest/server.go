@@ -0,0 +1,132 @@
+// Package feishutest 提供一个进程内的飞书假服务器，用于测试调用 go-feishu 的代码。
+//
+//	srv := feishutest.NewServer()
+//	defer srv.Close()
+//	srv.AddWebhookBot(botKey, feishutest.BotConfig{Secret: secret})
+//	client, _ := feishu.NewLocalCacheClient(appId, appSecret, feishu.WithBaseURL(srv.URL))
+//
+// 服务器模拟了鉴权、通讯录、消息、群组和自定义机器人接口，并支持注入故障（限流、5xx、token 失效）。
+// 客户端按 appId 在进程内缓存 token，每个 Server 请使用不同的 appId。
+package feishutest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiPrefix = "/open-apis/"
+
+	// 自定义机器人每分钟最多发送 20 条消息
+	DefaultWebhookRateLimit = 20
+
+	// tenant_access_token 和 app_access_token 的有效期
+	DefaultTokenExpire = 2 * time.Hour
+)
+
+type Server struct {
+	*httptest.Server
+	mux *http.ServeMux
+
+	// 每个机器人每分钟的最大消息数，默认 20，<= 0 表示不限制
+	WebhookRateLimit int
+	// 签发的 token 的有效期，默认 2 小时
+	TokenExpire time.Duration
+	// Now 返回当前时间，测试中可替换以模拟时间流逝
+	Now func() time.Time
+
+	mu       sync.Mutex
+	apps     map[string]string
+	tokens   map[string]*token
+	bots     map[string]*BotConfig
+	sent     map[string][]time.Time
+	messages []*Message
+	users    []*User
+	chats    []*Chat
+	faults   []*fault
+	requests map[string]int
+	nextId   int
+}
+
+func NewServer() *Server {
+	s := &Server{
+		mux:              http.NewServeMux(),
+		WebhookRateLimit: DefaultWebhookRateLimit,
+		TokenExpire:      DefaultTokenExpire,
+		Now:              time.Now,
+		apps:             make(map[string]string),
+		tokens:           make(map[string]*token),
+		bots:             make(map[string]*BotConfig),
+		sent:             make(map[string][]time.Time),
+		requests:         make(map[string]int),
+	}
+	s.mux.HandleFunc(apiPrefix+"auth/v3/tenant_access_token/internal", s.handleAccessToken(tokenTenant))
+	s.mux.HandleFunc(apiPrefix+"auth/v3/app_access_token/internal", s.handleAccessToken(tokenApp))
+	s.mux.HandleFunc(apiPrefix+"contact/v3/users/batch_get_id", s.authorized(s.handleBatchGetId))
+	s.mux.HandleFunc(apiPrefix+"im/v1/messages", s.authorized(s.handleMessages))
+	s.mux.HandleFunc(apiPrefix+"im/v1/chats", s.authorized(s.handleListChats))
+	s.mux.HandleFunc(apiPrefix+"im/v1/chats/", s.authorized(s.handleChat))
+	s.mux.HandleFunc(apiPrefix+"bot/v2/hook/", s.handleWebhook)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// HandleFunc 注册自定义的接口，path 不包含 /open-apis/ 前缀。
+func (s *Server) HandleFunc(path string, handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(apiPrefix+strings.TrimPrefix(path, "/"), handler)
+}
+
+// Requests 返回 path（不包含 /open-apis/ 前缀）收到的请求数，包括注入故障的请求。
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[strings.TrimPrefix(path, "/")]
+}
+
+// Reset 清空收到的消息、限流计数和请求计数。
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+	s.sent = make(map[string][]time.Time)
+	s.requests = make(map[string]int)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+
+	s.mu.Lock()
+	s.requests[path]++
+	f := s.matchFault(path)
+	s.mu.Unlock()
+
+	if f != nil {
+		s.writeFault(w, f)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) newId(prefix string) string {
+	s.nextId++
+	return fmt.Sprintf("%s_%016x", prefix, s.nextId)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeCode(w http.ResponseWriter, status, code int, msg string) {
+	writeJSON(w, status, map[string]interface{}{"code": code, "msg": msg, "data": map[string]interface{}{}})
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"code": 0, "msg": "success", "data": data})
+}
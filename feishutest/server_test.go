@@ -0,0 +1,236 @@
+package feishutest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	feishu "github.com/eyotang/go-feishu"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const botKey = "891105b7-1234-4567-7890-c4c372235090"
+
+func TestServer_Webhook(t *testing.T) {
+	Convey("test Server_Webhook", t, func() {
+		srv := NewServer()
+		defer srv.Close()
+		srv.AddWebhookBot(botKey, BotConfig{Secret: "bot-secret", Keywords: []string{"[alert]"}})
+
+		client, err := feishu.NewClient(feishu.WithBaseURL(srv.URL))
+		So(err, ShouldBeNil)
+
+		bot, _ := client.Bot.NewWebhookBot(botKey, feishu.WithWebhookSecret("bot-secret"))
+		_, _, err = bot.SendText("[alert] disk full")
+		So(err, ShouldBeNil)
+		m := srv.AssertWebhookMessage(t, botKey, "disk full")
+		So(m.Text(), ShouldEqual, "[alert] disk full")
+
+		// 缺少关键词
+		_, _, err = bot.SendText("disk full")
+		So(feishu.IsWebhookKeywordError(err), ShouldBeTrue)
+
+		// 签名错误
+		wrong, _ := client.Bot.NewWebhookBot(botKey, feishu.WithWebhookSecret("wrong"))
+		_, _, err = wrong.SendText("[alert] disk full")
+		So(feishu.IsWebhookSignError(err), ShouldBeTrue)
+
+		// 未注册的机器人
+		unknown, _ := client.Bot.NewWebhookBot("unknown")
+		_, _, err = unknown.SendText("[alert] disk full")
+		So(err, ShouldNotBeNil)
+
+		srv.AssertMessageCount(t, 1)
+	})
+}
+
+func TestServer_WebhookKeywordVisibleText(t *testing.T) {
+	Convey("test Server_WebhookKeywordVisibleText", t, func() {
+		srv := NewServer()
+		defer srv.Close()
+		srv.AddWebhookBot(botKey, BotConfig{Keywords: []string{"lark_md", "example.com"}})
+
+		client, _ := feishu.NewClient(feishu.WithBaseURL(srv.URL))
+		bot, _ := client.Bot.NewWebhookBot(botKey)
+
+		// 关键词只出现在 tag、href 中，飞书会拒绝
+		_, _, err := bot.SendPost(&feishu.Post{Title: "deploy", Content: [][]feishu.PostElement{{
+			{Tag: "a", Text: "detail", Href: "https://example.com"},
+		}}})
+		So(feishu.IsWebhookKeywordError(err), ShouldBeTrue)
+		_, _, err = bot.SendCard(map[string]interface{}{
+			"elements": []interface{}{map[string]interface{}{"tag": "div", "text": map[string]interface{}{"tag": "lark_md", "content": "done"}}},
+		})
+		So(feishu.IsWebhookKeywordError(err), ShouldBeTrue)
+		srv.AssertMessageCount(t, 0)
+
+		_, _, err = bot.SendText("see example.com")
+		So(err, ShouldBeNil)
+		srv.AssertMessageCount(t, 1)
+	})
+}
+
+func TestServer_WebhookRateLimit(t *testing.T) {
+	Convey("test Server_WebhookRateLimit", t, func() {
+		srv := NewServer()
+		defer srv.Close()
+		srv.AddWebhookBot(botKey, BotConfig{})
+
+		now := time.Now()
+		srv.Now = func() time.Time { return now }
+
+		client, _ := feishu.NewClient(feishu.WithBaseURL(srv.URL))
+		bot, _ := client.Bot.NewWebhookBot(botKey)
+		for i := 0; i < DefaultWebhookRateLimit; i++ {
+			_, _, err := bot.SendText("hello")
+			So(err, ShouldBeNil)
+		}
+		_, _, err := bot.SendText("hello")
+		So(feishu.IsWebhookRateLimited(err), ShouldBeTrue)
+
+		// 一分钟后恢复
+		now = now.Add(time.Minute)
+		_, _, err = bot.SendText("hello")
+		So(err, ShouldBeNil)
+		So(srv.WebhookMessages(botKey), ShouldHaveLength, DefaultWebhookRateLimit+1)
+	})
+}
+
+func TestServer_AppMessages(t *testing.T) {
+	Convey("test Server_AppMessages", t, func() {
+		srv := NewServer()
+		defer srv.Close()
+
+		client, err := feishu.NewLocalCacheClient("cli_feishutest", "secret", feishu.WithBaseURL(srv.URL))
+		So(err, ShouldBeNil)
+
+		opt := &feishu.AppCardMessageOption{
+			MsgType:   feishu.MsgTypeInteractive,
+			ReceiveID: "ou_b46ad73aaaqer1231bd1daeb7d3a41e9f1a",
+			Card: feishu.AppCardOption{
+				Header: feishu.HeadOption{
+					Title: feishu.TitleOption{Tag: "plain_text", Content: "test report"},
+				},
+			},
+		}
+		rsp, _, err := client.App.SendAppCardMessage("open_id", opt)
+		So(err, ShouldBeNil)
+		So(rsp.Code, ShouldEqual, 0)
+		So(rsp.Data["message_id"], ShouldNotBeEmpty)
+
+		m := srv.AssertAppMessage(t, "ou_b46ad73aaaqer1231bd1daeb7d3a41e9f1a", "test report")
+		So(m.ReceiveIdType, ShouldEqual, "open_id")
+		So(m.MsgType, ShouldEqual, feishu.MsgTypeInteractive)
+	})
+}
+
+func TestServer_Auth(t *testing.T) {
+	Convey("test Server_Auth", t, func() {
+		srv := NewServer()
+		defer srv.Close()
+		srv.AddApp("cli_feishutest_auth", "secret")
+
+		client, _ := feishu.NewClient(feishu.WithBaseURL(srv.URL))
+		token, _, err := client.Auth.GetTenantAccessTokenInternal(&feishu.GetAccessTokenOptions{AppId: "cli_feishutest_auth", AppSecret: "secret"})
+		So(err, ShouldBeNil)
+		So(token.Code, ShouldEqual, 0)
+		So(token.Expire, ShouldEqual, int(DefaultTokenExpire/time.Second))
+
+		// 剩余有效期充足时返回相同的 token
+		again, _, err := client.Auth.GetTenantAccessTokenInternal(&feishu.GetAccessTokenOptions{AppId: "cli_feishutest_auth", AppSecret: "secret"})
+		So(err, ShouldBeNil)
+		So(again.AccessToken, ShouldEqual, token.AccessToken)
+
+		wrong, _, err := client.Auth.GetTenantAccessTokenInternal(&feishu.GetAccessTokenOptions{AppId: "cli_feishutest_auth", AppSecret: "wrong"})
+		So(err, ShouldBeNil)
+		So(wrong.Code, ShouldEqual, CodeAppSecretInvalid)
+	})
+}
+
+func TestServer_BatchGetId(t *testing.T) {
+	Convey("test Server_BatchGetId", t, func() {
+		srv := NewServer()
+		defer srv.Close()
+		u := srv.AddUser(User{Name: "zhangsan", Email: "zhangsan@a.com", Mobile: "15921667242"})
+
+		client, _ := feishu.NewLocalCacheClient("cli_feishutest_contact", "secret", feishu.WithBaseURL(srv.URL))
+		users, _, err := client.Contact.BatchGetId("open_id", &feishu.BatchGetIdOptions{
+			Emails: []string{"zhangsan@a.com", "nobody@a.com"},
+		})
+		So(err, ShouldBeNil)
+		So(users.Data.UserList, ShouldResemble, []feishu.User{
+			{UserId: u.OpenId, Email: "zhangsan@a.com"},
+			{Email: "nobody@a.com"},
+		})
+	})
+}
+
+func TestServer_Chats(t *testing.T) {
+	Convey("test Server_Chats", t, func() {
+		srv := NewServer()
+		defer srv.Close()
+		for i := 0; i < 5; i++ {
+			srv.AddChat(Chat{Name: "chat"})
+		}
+
+		client, _ := feishu.NewLocalCacheClient("cli_feishutest_chats", "secret", feishu.WithBaseURL(srv.URL))
+		type page struct {
+			feishu.CodeMsg
+			Data struct {
+				Items     []map[string]interface{} `json:"items"`
+				PageToken string                   `json:"page_token"`
+				HasMore   bool                     `json:"has_more"`
+			} `json:"data"`
+		}
+
+		var items []map[string]interface{}
+		pageToken := ""
+		for {
+			opt := &struct {
+				PageSize  int    `url:"page_size"`
+				PageToken string `url:"page_token,omitempty"`
+			}{PageSize: 2, PageToken: pageToken}
+			req, err := client.NewServerRequest(http.MethodGet, "im/v1/chats", opt, nil)
+			So(err, ShouldBeNil)
+			p := new(page)
+			_, err = client.Do(req, p)
+			So(err, ShouldBeNil)
+			items = append(items, p.Data.Items...)
+			if !p.Data.HasMore {
+				break
+			}
+			pageToken = p.Data.PageToken
+		}
+		So(items, ShouldHaveLength, 5)
+	})
+}
+
+func TestServer_InjectFault(t *testing.T) {
+	Convey("test Server_InjectFault", t, func() {
+		srv := NewServer()
+		defer srv.Close()
+
+		client, _ := feishu.NewLocalCacheClient("cli_feishutest_fault", "secret", feishu.WithBaseURL(srv.URL))
+		opt := &feishu.AppCardMessageOption{MsgType: feishu.MsgTypeText, ReceiveID: "ou_a", Content: `{"text":"hi"}`}
+
+		// 限流一次后客户端自动重试成功
+		fault := FaultRateLimited
+		fault.Times = 1
+		srv.InjectFault("im/v1/messages", fault)
+		_, _, err := client.App.SendAppCardMessage("open_id", opt)
+		So(err, ShouldBeNil)
+		So(srv.Requests("im/v1/messages"), ShouldEqual, 2)
+
+		// token 失效
+		srv.InjectFault("im/v1/messages", FaultTokenInvalid)
+		_, resp, err := client.App.SendAppCardMessage("open_id", opt)
+		So(err, ShouldNotBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+
+		srv.ClearFaults()
+		srv.ExpireTokens()
+		_, _, err = client.App.SendAppCardMessage("open_id", opt)
+		So(err, ShouldNotBeNil)
+		So(srv.AppMessages("ou_a"), ShouldHaveLength, 1)
+	})
+}
@@ -0,0 +1,167 @@
+package feishutest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	feishu "github.com/eyotang/go-feishu"
+)
+
+// 签名中的时间戳和服务器时间最多相差 1 小时
+const signMaxSkew = time.Hour
+
+// BotConfig 自定义机器人的安全设置。
+type BotConfig struct {
+	Secret   string
+	Keywords []string
+}
+
+// AddWebhookBot 注册自定义机器人，未注册的 key 返回 19001。
+func (s *Server) AddWebhookBot(key string, config BotConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := config
+	s.bots[key] = &c
+}
+
+// WebhookMessages 返回机器人 key 收到的消息。
+func (s *Server) WebhookMessages(key string) []*Message {
+	return s.filter(func(m *Message) bool { return m.BotKey == key })
+}
+
+type webhookRequest struct {
+	Timestamp string          `json:"timestamp"`
+	Sign      string          `json:"sign"`
+	MsgType   string          `json:"msg_type"`
+	Content   json.RawMessage `json:"content"`
+	Card      json.RawMessage `json:"card"`
+}
+
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, apiPrefix+"bot/v2/hook/")
+	if r.Method != http.MethodPost {
+		writeCode(w, http.StatusMethodNotAllowed, 19001, "param invalid: method not allowed")
+		return
+	}
+
+	req := new(webhookRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeCode(w, http.StatusBadRequest, 9499, "Bad Request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bot, ok := s.bots[key]
+	if !ok {
+		writeCode(w, http.StatusOK, feishu.WebhookCodeInvalidToken, "param invalid: incoming webhook access token invalid")
+		return
+	}
+
+	now := s.Now()
+	if len(bot.Secret) > 0 && !validSign(bot.Secret, req.Timestamp, req.Sign, now) {
+		writeCode(w, http.StatusOK, feishu.WebhookCodeSignMismatch, "sign match fail or timestamp is not within one hour from current time")
+		return
+	}
+
+	content := req.Content
+	if req.MsgType == feishu.MsgTypeInteractive && len(req.Card) > 0 {
+		content = req.Card
+	}
+	if !containsKeyword(string(content), bot.Keywords) {
+		writeCode(w, http.StatusOK, feishu.WebhookCodeKeywordsNotFound, "Key Words Not Found")
+		return
+	}
+
+	if !s.allow(key, now) {
+		writeCode(w, http.StatusOK, feishu.WebhookCodeFrequencyLimited, "frequency limited")
+		return
+	}
+
+	s.record(&Message{
+		MessageId: s.newId("om"),
+		BotKey:    key,
+		MsgType:   req.MsgType,
+		Content:   string(content),
+		Time:      now,
+	})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"StatusCode":    0,
+		"StatusMessage": "success",
+		"code":          0,
+		"msg":           "success",
+		"data":          map[string]interface{}{},
+	})
+}
+
+// allow 按一分钟滑动窗口限流。
+func (s *Server) allow(key string, now time.Time) bool {
+	if s.WebhookRateLimit <= 0 {
+		return true
+	}
+	var recent []time.Time
+	for _, t := range s.sent[key] {
+		if now.Sub(t) < time.Minute {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= s.WebhookRateLimit {
+		s.sent[key] = recent
+		return false
+	}
+	s.sent[key] = append(recent, now)
+	return true
+}
+
+func validSign(secret, timestamp, sign string, now time.Time) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > signMaxSkew || d < -signMaxSkew {
+		return false
+	}
+	want, err := feishu.GenSign(secret, ts)
+	return err == nil && want == sign
+}
+
+// containsKeyword 和 WebhookBot 使用相同的规则，只匹配展示的文本。
+func containsKeyword(content string, keywords []string) bool {
+	ok, err := feishu.HasWebhookKeyword(json.RawMessage(content), keywords)
+	return err == nil && ok
+}
+
+// plainText 取出 json 中所有字符串，还原转义的 < > & 等字符，便于按原文匹配。
+func plainText(content string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(content), &v); err != nil {
+		return content
+	}
+	return collectStrings(v)
+}
+
+func collectStrings(v interface{}) string {
+	var b strings.Builder
+	var walk func(interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case string:
+			b.WriteString(v)
+			b.WriteByte('\n')
+		case []interface{}:
+			for _, e := range v {
+				walk(e)
+			}
+		case map[string]interface{}:
+			for _, e := range v {
+				walk(e)
+			}
+		}
+	}
+	walk(v)
+	return b.String()
+}
@@ -0,0 +1,220 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// Span 行内的一段文本和格式。
+type Span struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Strike bool
+	Code   bool
+	Link   string
+}
+
+func (s *Span) sameStyle(o *Span) bool {
+	return s.Bold == o.Bold && s.Italic == o.Italic && s.Strike == o.Strike && s.Code == o.Code && s.Link == o.Link
+}
+
+// ParseInline 解析行内格式：粗体、斜体、删除线、行内代码和链接，图片作为链接，未闭合的标记为文本。
+func ParseInline(s string) []Span {
+	var spans []Span
+	parseInline(s, Span{}, &spans)
+	return spans
+}
+
+func emitSpan(spans *[]Span, span Span) {
+	if len(span.Text) == 0 {
+		return
+	}
+	if n := len(*spans); n > 0 && (*spans)[n-1].sameStyle(&span) {
+		(*spans)[n-1].Text += span.Text
+		return
+	}
+	*spans = append(*spans, span)
+}
+
+func parseInline(s string, style Span, spans *[]Span) {
+	var text strings.Builder
+	flush := func() {
+		span := style
+		span.Text = text.String()
+		emitSpan(spans, span)
+		text.Reset()
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			text.WriteByte(s[i+1])
+			i += 2
+		case c == '`':
+			n := runLength(s, i)
+			fence := s[i : i+n]
+			end := strings.Index(s[i+n:], fence)
+			if end < 0 {
+				text.WriteString(fence)
+				i += n
+				continue
+			}
+			flush()
+			code := s[i+n : i+n+end]
+			if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' {
+				code = code[1 : len(code)-1]
+			}
+			span := style
+			span.Code, span.Text = true, code
+			emitSpan(spans, span)
+			i += n + end + n
+		case c == '[' || (c == '!' && i+1 < len(s) && s[i+1] == '['):
+			start := i
+			if c == '!' {
+				start++
+			}
+			label, url, next, ok := parseLink(s, start)
+			if !ok {
+				text.WriteByte(c)
+				i++
+				continue
+			}
+			flush()
+			span := style
+			span.Link = url
+			if c == '!' {
+				// 行内图片作为链接
+				if len(label) == 0 {
+					label = url
+				}
+				span.Text = label
+				emitSpan(spans, span)
+			} else {
+				parseInline(label, span, spans)
+			}
+			i = next
+		case c == '*' || c == '_' || c == '~':
+			n := runLength(s, i)
+			if n > 2 {
+				n = 2
+			}
+			delim := s[i : i+n]
+			end := -1
+			if (c != '~' || n == 2) && opens(s, i, n) {
+				end = findClose(s, i+n, delim)
+			}
+			if end < 0 {
+				text.WriteString(delim)
+				i += n
+				continue
+			}
+			flush()
+			span := style
+			switch {
+			case c == '~':
+				span.Strike = true
+			case n == 2:
+				span.Bold = true
+			default:
+				span.Italic = true
+			}
+			parseInline(s[i+n:end], span, spans)
+			i = end + n
+		default:
+			text.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+}
+
+func runLength(s string, i int) int {
+	n := 1
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+	return n
+}
+
+// opens 开始标记后不能是空白，_ 前不能是字母数字。
+func opens(s string, i, n int) bool {
+	if i+n >= len(s) || isSpace(s[i+n]) {
+		return false
+	}
+	return s[i] != '_' || i == 0 || !isAlnum(s[i-1])
+}
+
+// findClose 查找结束标记，跳过转义、行内代码和更长的同类标记，结束标记前不能是空白。
+func findClose(s string, from int, delim string) int {
+	for i := from; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case c == '`':
+			n := runLength(s, i)
+			if end := strings.Index(s[i+n:], s[i:i+n]); end >= 0 {
+				i += n + end + n - 1
+			} else {
+				i += n - 1
+			}
+		case c == delim[0]:
+			n := runLength(s, i)
+			if i > from && !isSpace(s[i-1]) && (n == len(delim) || (len(delim) == 2 && n == 3)) {
+				if delim[0] != '_' || i+n >= len(s) || !isAlnum(s[i+n]) {
+					// ***x*** 中粗体的结束标记是最后两个 *，第一个 * 结束内层的斜体
+					return i + n - len(delim)
+				}
+			}
+			i += n - 1
+		}
+	}
+	return -1
+}
+
+// parseLink 解析 [label](url "title")，返回 label、url 和结束位置。
+func parseLink(s string, i int) (label, url string, next int, ok bool) {
+	depth := 0
+	j := i
+	for ; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if j >= len(s) || j+1 >= len(s) || s[j+1] != '(' {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(s[j+2:], ')')
+	if end < 0 {
+		return "", "", 0, false
+	}
+	dest := strings.TrimSpace(s[j+2 : j+2+end])
+	if k := strings.IndexAny(dest, " \t"); k >= 0 {
+		dest = dest[:k]
+	}
+	if len(dest) == 0 {
+		return "", "", 0, false
+	}
+	return s[i+1 : j], dest, j + 2 + end + 1, true
+}
+
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
@@ -0,0 +1,51 @@
+package markdown
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseInline(t *testing.T) {
+	Convey("test ParseInline", t, func() {
+		So(ParseInline("plain text"), ShouldResemble, []Span{{Text: "plain text"}})
+
+		So(ParseInline("Run on **staging**, see [CI](https://ci.example.com \"ci\")."), ShouldResemble, []Span{
+			{Text: "Run on "},
+			{Text: "staging", Bold: true},
+			{Text: ", see "},
+			{Text: "CI", Link: "https://ci.example.com"},
+			{Text: "."},
+		})
+
+		So(ParseInline("*a **b** c* ~~old~~ `x * y`"), ShouldResemble, []Span{
+			{Text: "a ", Italic: true},
+			{Text: "b", Bold: true, Italic: true},
+			{Text: " c", Italic: true},
+			{Text: " "},
+			{Text: "old", Strike: true},
+			{Text: " "},
+			{Text: "x * y", Code: true},
+		})
+
+		So(ParseInline("***both***"), ShouldResemble, []Span{{Text: "both", Bold: true, Italic: true}})
+
+		Convey("literal markers", func() {
+			So(ParseInline("2 * 3 * 4"), ShouldResemble, []Span{{Text: "2 * 3 * 4"}})
+			So(ParseInline("snake_case_name and __init__"), ShouldResemble, []Span{
+				{Text: "snake_case_name and "},
+				{Text: "init", Bold: true},
+			})
+			So(ParseInline(`\*not italic\* [no link] **open`), ShouldResemble, []Span{{Text: "*not italic* [no link] **open"}})
+		})
+
+		Convey("links", func() {
+			So(ParseInline("[**bold** link](https://a.com/x_y) ![chart](https://a.com/c.png)"), ShouldResemble, []Span{
+				{Text: "bold", Bold: true, Link: "https://a.com/x_y"},
+				{Text: " link", Link: "https://a.com/x_y"},
+				{Text: " "},
+				{Text: "chart", Link: "https://a.com/c.png"},
+			})
+		})
+	})
+}
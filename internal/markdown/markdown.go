@@ -0,0 +1,384 @@
+// Package markdown 解析 GitHub 风格 markdown 的块结构，供卡片、文档转换使用。
+// 只处理常用的语法：标题、段落、列表、任务列表、代码块、引用、表格、分割线和独占一行的图片，
+// 行内的格式保留原文，由调用方处理，需要时使用 ParseInline 解析。
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Kind int
+
+const (
+	KindParagraph Kind = iota
+	KindHeading
+	KindList
+	KindCode
+	KindQuote
+	KindTable
+	KindHr
+	KindImage
+)
+
+type Align int
+
+const (
+	AlignNone Align = iota
+	AlignLeft
+	AlignCenter
+	AlignRight
+)
+
+// Block 块级元素，按 Kind 使用不同的字段。
+type Block struct {
+	Kind Kind
+
+	// 段落、标题、引用的行内 markdown，代码块的内容
+	Text string
+	// 标题级别 1-6
+	Level int
+	// 代码块语言
+	Lang string
+
+	Items []ListItem
+
+	Header []string
+	Align  []Align
+	Rows   [][]string
+
+	// 图片
+	Alt string
+	URL string
+}
+
+// ListItem 列表项，Indent 为嵌套层级，从 0 开始。
+type ListItem struct {
+	Text    string
+	Indent  int
+	Ordered bool
+	Number  int
+	// 任务列表，nil 表示不是任务
+	Checked *bool
+}
+
+var (
+	headingRe   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	fenceRe     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^ \t`]*)")
+	hrRe        = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	setextRe    = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	listRe      = regexp.MustCompile(`^([ \t]*)([-*+]|(\d{1,9})[.)])(?:[ \t]+(.*))?$`)
+	taskRe      = regexp.MustCompile(`^\[([ xX])\][ \t]+(.*)$`)
+	quoteRe     = regexp.MustCompile(`^ {0,3}>[ ]?(.*)$`)
+	delimiterRe = regexp.MustCompile(`^[ \t]*\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	imageRe     = regexp.MustCompile(`^!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)$`)
+)
+
+// Parse 解析 markdown。
+func Parse(src string) []Block {
+	p := &parser{lines: strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")}
+	p.parse()
+	return p.blocks
+}
+
+type parser struct {
+	lines  []string
+	pos    int
+	blocks []Block
+	para   []string
+}
+
+func (p *parser) parse() {
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+
+		if strings.TrimSpace(line) == "" {
+			p.flush()
+			p.pos++
+			continue
+		}
+
+		// setext 标题：段落下一行为 === 或 ---
+		if len(p.para) > 0 {
+			if m := setextRe.FindStringSubmatch(line); m != nil {
+				level := 1
+				if m[1][0] == '-' {
+					level = 2
+				}
+				text := joinLines(p.para)
+				p.para = nil
+				p.blocks = append(p.blocks, Block{Kind: KindHeading, Level: level, Text: text})
+				p.pos++
+				continue
+			}
+		}
+
+		switch {
+		case fenceRe.MatchString(line):
+			p.flush()
+			p.parseCode()
+		case hrRe.MatchString(line):
+			p.flush()
+			p.blocks = append(p.blocks, Block{Kind: KindHr})
+			p.pos++
+		case headingRe.MatchString(line):
+			p.flush()
+			m := headingRe.FindStringSubmatch(line)
+			p.blocks = append(p.blocks, Block{Kind: KindHeading, Level: len(m[1]), Text: strings.TrimSpace(m[2])})
+			p.pos++
+		case quoteRe.MatchString(line):
+			p.flush()
+			p.parseQuote()
+		case listRe.MatchString(line) && p.startsList(line):
+			p.flush()
+			p.parseList()
+		case p.pos+1 < len(p.lines) && strings.Contains(line, "|") && delimiterRe.MatchString(p.lines[p.pos+1]) &&
+			len(splitRow(line)) == len(splitRow(p.lines[p.pos+1])):
+			p.flush()
+			p.parseTable()
+		default:
+			p.para = append(p.para, line)
+			p.pos++
+		}
+	}
+	p.flush()
+}
+
+// startsList 段落中的数字开头的行只有 1. 才开始列表。
+func (p *parser) startsList(line string) bool {
+	m := listRe.FindStringSubmatch(line)
+	if len(p.para) == 0 {
+		return true
+	}
+	if len(m[4]) == 0 {
+		return false
+	}
+	return len(m[3]) == 0 || m[3] == "1"
+}
+
+func (p *parser) flush() {
+	if len(p.para) == 0 {
+		return
+	}
+	text := joinLines(p.para)
+	p.para = nil
+	if m := imageRe.FindStringSubmatch(text); m != nil {
+		p.blocks = append(p.blocks, Block{Kind: KindImage, Alt: m[1], URL: m[2]})
+		return
+	}
+	p.blocks = append(p.blocks, Block{Kind: KindParagraph, Text: text})
+}
+
+// joinLines 软换行合并为空格，行尾两个空格或反斜杠为硬换行。
+func joinLines(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		hard := strings.HasSuffix(line, "  ") || strings.HasSuffix(line, `\`)
+		line = strings.TrimSpace(line)
+		if hard {
+			line = strings.TrimSpace(strings.TrimSuffix(line, `\`))
+		}
+		b.WriteString(line)
+		if i < len(lines)-1 {
+			if hard {
+				b.WriteString("\n")
+			} else {
+				b.WriteString(" ")
+			}
+		}
+	}
+	return b.String()
+}
+
+func (p *parser) parseCode() {
+	m := fenceRe.FindStringSubmatch(p.lines[p.pos])
+	fence := m[1]
+	indent := len(p.lines[p.pos]) - len(strings.TrimLeft(p.lines[p.pos], " "))
+	block := Block{Kind: KindCode, Lang: m[2]}
+	p.pos++
+
+	var code []string
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, fence[:1]) && strings.Trim(trimmed, fence[:1]) == "" && len(trimmed) >= len(fence) {
+			p.pos++
+			break
+		}
+		// 去掉和开始标记相同的缩进
+		for i := 0; i < indent && strings.HasPrefix(line, " "); i++ {
+			line = line[1:]
+		}
+		code = append(code, line)
+	}
+	block.Text = strings.Join(code, "\n")
+	p.blocks = append(p.blocks, block)
+}
+
+func (p *parser) parseQuote() {
+	var lines []string
+	for ; p.pos < len(p.lines); p.pos++ {
+		m := quoteRe.FindStringSubmatch(p.lines[p.pos])
+		if m == nil {
+			break
+		}
+		lines = append(lines, m[1])
+	}
+
+	// 引用中的空行分段
+	var paragraphs, para []string
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if len(para) > 0 {
+				paragraphs = append(paragraphs, joinLines(para))
+				para = nil
+			}
+			continue
+		}
+		para = append(para, line)
+	}
+	if len(para) > 0 {
+		paragraphs = append(paragraphs, joinLines(para))
+	}
+	p.blocks = append(p.blocks, Block{Kind: KindQuote, Text: strings.Join(paragraphs, "\n")})
+}
+
+func (p *parser) parseList() {
+	block := Block{Kind: KindList}
+	// 每一层的缩进宽度
+	var indents []int
+
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if strings.TrimSpace(line) == "" {
+			// 空行后不是列表项或缩进的内容则结束
+			next := p.pos + 1
+			for next < len(p.lines) && strings.TrimSpace(p.lines[next]) == "" {
+				next++
+			}
+			if next >= len(p.lines) || (!listRe.MatchString(p.lines[next]) && width(p.lines[next]) < 2) {
+				break
+			}
+			p.pos = next
+			continue
+		}
+
+		m := listRe.FindStringSubmatch(line)
+		if m == nil {
+			// 缩进的续行
+			if len(block.Items) == 0 || (width(line) < 2 && isBlockStart(line)) {
+				break
+			}
+			item := &block.Items[len(block.Items)-1]
+			item.Text += " " + strings.TrimSpace(line)
+			p.pos++
+			continue
+		}
+
+		w := width(m[1])
+		for len(indents) > 0 && w < indents[len(indents)-1] {
+			indents = indents[:len(indents)-1]
+		}
+		if len(indents) == 0 || w > indents[len(indents)-1] {
+			indents = append(indents, w)
+		}
+
+		item := ListItem{Indent: len(indents) - 1, Text: strings.TrimSpace(m[4])}
+		if len(m[3]) > 0 {
+			item.Ordered = true
+			item.Number, _ = strconv.Atoi(m[3])
+		}
+		if t := taskRe.FindStringSubmatch(item.Text); t != nil {
+			checked := t[1] != " "
+			item.Checked, item.Text = &checked, t[2]
+		}
+		block.Items = append(block.Items, item)
+		p.pos++
+	}
+	p.blocks = append(p.blocks, block)
+}
+
+func isBlockStart(line string) bool {
+	return fenceRe.MatchString(line) || hrRe.MatchString(line) || headingRe.MatchString(line) || quoteRe.MatchString(line)
+}
+
+// width 行首空白的宽度，tab 按 4 个空格计算。
+func width(s string) int {
+	w := 0
+	for _, c := range s {
+		switch c {
+		case ' ':
+			w++
+		case '\t':
+			w += 4 - w%4
+		default:
+			return w
+		}
+	}
+	return w
+}
+
+func (p *parser) parseTable() {
+	block := Block{Kind: KindTable, Header: splitRow(p.lines[p.pos])}
+	for _, cell := range splitRow(p.lines[p.pos+1]) {
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			block.Align = append(block.Align, AlignCenter)
+		case left:
+			block.Align = append(block.Align, AlignLeft)
+		case right:
+			block.Align = append(block.Align, AlignRight)
+		default:
+			block.Align = append(block.Align, AlignNone)
+		}
+	}
+	p.pos += 2
+
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if strings.TrimSpace(line) == "" || !strings.Contains(line, "|") || isBlockStart(line) {
+			break
+		}
+		row := splitRow(line)
+		// 列数和表头一致
+		for len(row) < len(block.Header) {
+			row = append(row, "")
+		}
+		block.Rows = append(block.Rows, row[:len(block.Header)])
+	}
+	p.blocks = append(p.blocks, block)
+}
+
+// splitRow 按未转义、不在行内代码中的 | 分割单元格。
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var (
+		cells []string
+		cell  strings.Builder
+		code  bool
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case c == '`':
+			code = !code
+			cell.WriteByte(c)
+		case c == '|' && !code:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(c)
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
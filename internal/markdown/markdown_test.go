@@ -0,0 +1,86 @@
+package markdown
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const report = "# Test Report\n" +
+	"\n" +
+	"Run on **staging**,\n" +
+	"see [CI](https://ci.example.com).\n" +
+	"\n" +
+	"Summary\n" +
+	"---\n" +
+	"\n" +
+	"- passed: 10\n" +
+	"- failed: 2\n" +
+	"  - `TestLogin`\n" +
+	"  - TestLogout\n" +
+	"    flaky\n" +
+	"1. first\n" +
+	"2. second\n" +
+	"\n" +
+	"- [x] done\n" +
+	"- [ ] todo\n" +
+	"\n" +
+	"| Case | Result | Time |\n" +
+	"| :--- | :----: | ---: |\n" +
+	"| login | `a|b` | 1s |\n" +
+	"| logout \\| out | fail |\n" +
+	"\n" +
+	"```go\n" +
+	"func main() {\n" +
+	"\n" +
+	"}\n" +
+	"```\n" +
+	"\n" +
+	"> note line 1\n" +
+	"> line 2\n" +
+	"\n" +
+	"***\n" +
+	"![chart](https://example.com/chart.png)\n"
+
+func TestParse(t *testing.T) {
+	Convey("test Parse", t, func() {
+		blocks := Parse(report)
+		var kinds []Kind
+		for _, b := range blocks {
+			kinds = append(kinds, b.Kind)
+		}
+		So(kinds, ShouldResemble, []Kind{
+			KindHeading, KindParagraph, KindHeading, KindList, KindTable, KindCode, KindQuote, KindHr, KindImage,
+		})
+
+		So(blocks[0].Level, ShouldEqual, 1)
+		So(blocks[0].Text, ShouldEqual, "Test Report")
+		So(blocks[1].Text, ShouldEqual, "Run on **staging**, see [CI](https://ci.example.com).")
+		So(blocks[2].Level, ShouldEqual, 2)
+
+		items := blocks[3].Items
+		So(items, ShouldHaveLength, 8)
+		So(items[2].Indent, ShouldEqual, 1)
+		So(items[3].Text, ShouldEqual, "TestLogout flaky")
+		So(items[4].Indent, ShouldEqual, 0)
+		So(items[5].Ordered, ShouldBeTrue)
+		So(items[5].Number, ShouldEqual, 2)
+
+		// 空行后的列表项属于同一个列表
+		So(items[0].Checked, ShouldBeNil)
+		So(*items[6].Checked, ShouldBeTrue)
+		So(*items[7].Checked, ShouldBeFalse)
+		So(items[7].Text, ShouldEqual, "todo")
+
+		table := blocks[4]
+		So(table.Header, ShouldResemble, []string{"Case", "Result", "Time"})
+		So(table.Align, ShouldResemble, []Align{AlignLeft, AlignCenter, AlignRight})
+		So(table.Rows, ShouldResemble, [][]string{{"login", "`a|b`", "1s"}, {"logout | out", "fail", ""}})
+
+		So(blocks[5].Lang, ShouldEqual, "go")
+		So(blocks[5].Text, ShouldEqual, "func main() {\n\n}")
+		So(blocks[6].Text, ShouldEqual, "note line 1 line 2")
+		So(blocks[8].URL, ShouldEqual, "https://example.com/chart.png")
+		So(blocks[8].Alt, ShouldEqual, "chart")
+	})
+}
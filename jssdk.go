@@ -0,0 +1,84 @@
+package feishu
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const _jsapiTicket = "jsapi_ticket"
+
+type JsapiTicket struct {
+	CodeMsg
+	Data struct {
+		ExpireIn int    `json:"expire_in"`
+		Ticket   string `json:"ticket"`
+	} `json:"data"`
+}
+
+// GetJsapiTicket 获取 JS-SDK 鉴权使用的 jsapi_ticket，一般使用 JsapiTicket 获取缓存的 ticket。
+func (s *AuthService) GetJsapiTicket(options ...RequestOptionFunc) (*JsapiTicket, *Response, error) {
+	u := "jssdk/ticket/get"
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(JsapiTicket)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// JsapiTicket 返回缓存的 jsapi_ticket，和 tenant_access_token 一样过期前自动刷新。
+func (s *AuthService) JsapiTicket() (string, error) {
+	if s.client.jsapiTicketManager == nil {
+		return "", errors.New("NewClient with token manager first! WithTenantAccessTokenInternal(appId, appSecret)")
+	}
+	err, ticket := s.client.jsapiTicketManager.GetAccessToken()
+	return ticket, err
+}
+
+type H5Config struct {
+	AppId     string `json:"appId"`
+	Timestamp int64  `json:"timestamp"`
+	NonceStr  string `json:"nonceStr"`
+	Signature string `json:"signature"`
+}
+
+// GetH5Config 生成 h5sdk.config 所需的参数，url 为调用 JS-SDK 的页面地址（不包含 # 及其后面部分）。
+func (s *AuthService) GetH5Config(url string) (*H5Config, error) {
+	ticket, err := s.JsapiTicket()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 8)
+	if _, err = rand.Read(buf); err != nil {
+		return nil, err
+	}
+	c := &H5Config{
+		AppId:     s.client.appId,
+		Timestamp: time.Now().UnixNano() / int64(time.Millisecond),
+		NonceStr:  hex.EncodeToString(buf),
+	}
+	c.Signature = GenJsapiSignature(ticket, c.NonceStr, c.Timestamp, url)
+	return c, nil
+}
+
+func GenJsapiSignature(ticket, nonceStr string, timestamp int64, url string) string {
+	// 按字段名排序拼接后做 sha1
+	verifyStr := fmt.Sprintf("jsapi_ticket=%s&noncestr=%s&timestamp=%d&url=%s", ticket, nonceStr, timestamp, url)
+	h := sha1.New()
+	h.Write([]byte(verifyStr))
+	return hex.EncodeToString(h.Sum(nil))
+}
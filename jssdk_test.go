@@ -0,0 +1,59 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenJsapiSignature(t *testing.T) {
+	Convey("test GenJsapiSignature", t, func() {
+		sign := GenJsapiSignature("617bf955832a4d4d80d9d8d85917a427", "Y7a8KkqX041bsSwT", 1510045655000,
+			"https://m.haiwainet.cn/ttc/3541093/2018/0509/content_31312407_1.html")
+		So(sign, ShouldEqual, "2ee6a79c182eeec8d76e7474136edb6e617b8d58")
+	})
+}
+
+func TestAuthService_GetH5Config(t *testing.T) {
+	Convey("test AuthService_GetH5Config", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/open-apis/auth/v3/tenant_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			fmt.Fprint(w, `{
+				"code": 0,
+				"expire": 7200,
+				"msg": "ok",
+				"tenant_access_token": "t-caecc734c2e3328a62489fe0648c4b98779515d3"
+			}`)
+		})
+
+		requested := 0
+		mux.HandleFunc("/open-apis/jssdk/ticket/get", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			requested++
+			fmt.Fprint(w, `{
+				"code": 0,
+				"msg": "ok",
+				"data": {
+					"expire_in": 7200,
+					"ticket": "617bf955832a4d4d80d9d8d85917a427"
+				}
+			}`)
+		})
+
+		url := "https://example.com/gadget/index.html"
+		config, err := client.Auth.GetH5Config(url)
+		So(err, ShouldBeNil)
+		So(config.AppId, ShouldEqual, appId)
+		So(config.Signature, ShouldEqual, GenJsapiSignature("617bf955832a4d4d80d9d8d85917a427", config.NonceStr, config.Timestamp, url))
+
+		// ticket 已缓存
+		_, err = client.Auth.GetH5Config(url)
+		So(err, ShouldBeNil)
+		So(requested, ShouldEqual, 1)
+	})
+}
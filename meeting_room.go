@@ -0,0 +1,372 @@
+package feishu
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRoomSlotStep  = 15 * time.Minute
+	defaultRoomSlotLimit = 10
+)
+
+type MeetingRoomService struct {
+	client *Client
+}
+
+type Building struct {
+	BuildingId  string   `json:"building_id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Floors      []string `json:"floors"`
+	CountryId   string   `json:"country_id"`
+	DistrictId  string   `json:"district_id"`
+	Address     string   `json:"address"`
+}
+
+type MeetingRoom struct {
+	RoomId       string `json:"room_id"`
+	BuildingId   string `json:"building_id"`
+	BuildingName string `json:"building_name"`
+	Capacity     int    `json:"capacity"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	DisplayId    string `json:"display_id"`
+	FloorName    string `json:"floor_name"`
+	IsDisabled   bool   `json:"is_disabled"`
+	CustomRoomId string `json:"custom_room_id"`
+}
+
+// ListBuildingsOptions Fields 为返回的字段，* 为全部。
+type ListBuildingsOptions struct {
+	PageSize  int    `url:"page_size,omitempty"`
+	PageToken string `url:"page_token,omitempty"`
+	OrderBy   string `url:"order_by,omitempty"`
+	Fields    string `url:"fields,omitempty"`
+}
+
+type ListBuildingsResponse struct {
+	CodeMsg
+	Data struct {
+		PageToken string     `json:"page_token"`
+		HasMore   bool       `json:"has_more"`
+		Buildings []Building `json:"buildings"`
+	} `json:"data"`
+}
+
+type ListMeetingRoomsOptions struct {
+	BuildingId string `url:"building_id"`
+	PageSize   int    `url:"page_size,omitempty"`
+	PageToken  string `url:"page_token,omitempty"`
+	OrderBy    string `url:"order_by,omitempty"`
+	Fields     string `url:"fields,omitempty"`
+}
+
+type ListMeetingRoomsResponse struct {
+	CodeMsg
+	Data struct {
+		PageToken string        `json:"page_token"`
+		HasMore   bool          `json:"has_more"`
+		Rooms     []MeetingRoom `json:"rooms"`
+	} `json:"data"`
+}
+
+// VcRoom 视频会议的会议室，按层级（RoomLevelId）组织。
+type VcRoom struct {
+	RoomId       string   `json:"room_id"`
+	Name         string   `json:"name"`
+	Capacity     int      `json:"capacity"`
+	Description  string   `json:"description"`
+	DisplayId    string   `json:"display_id"`
+	CustomRoomId string   `json:"custom_room_id"`
+	RoomLevelId  string   `json:"room_level_id"`
+	Path         []string `json:"path"`
+}
+
+type ListVcRoomsOptions struct {
+	RoomLevelId string `url:"room_level_id,omitempty"`
+	UserIdType  string `url:"user_id_type,omitempty"`
+	PageSize    int    `url:"page_size,omitempty"`
+	PageToken   string `url:"page_token,omitempty"`
+}
+
+type ListVcRoomsResponse struct {
+	CodeMsg
+	Data struct {
+		PageToken string   `json:"page_token"`
+		HasMore   bool     `json:"has_more"`
+		Rooms     []VcRoom `json:"rooms"`
+	} `json:"data"`
+}
+
+// ListBuildings 查询建筑物列表。
+func (s *MeetingRoomService) ListBuildings(opt *ListBuildingsOptions, options ...RequestOptionFunc) (*ListBuildingsResponse, *Response, error) {
+	u := "meeting_room/building/list"
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListBuildingsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListRooms 查询建筑物中的会议室。
+func (s *MeetingRoomService) ListRooms(opt *ListMeetingRoomsOptions, options ...RequestOptionFunc) (*ListMeetingRoomsResponse, *Response, error) {
+	u := "meeting_room/room/list"
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListMeetingRoomsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListVcRooms 查询视频会议的会议室。
+func (s *MeetingRoomService) ListVcRooms(opt *ListVcRoomsOptions, options ...RequestOptionFunc) (*ListVcRoomsResponse, *Response, error) {
+	u := "vc/v1/rooms"
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListVcRoomsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AllRooms 翻页查询建筑物中所有可用的会议室。
+func (s *MeetingRoomService) AllRooms(buildingId string, options ...RequestOptionFunc) ([]MeetingRoom, error) {
+	opt := &ListMeetingRoomsOptions{BuildingId: buildingId, PageSize: 100, Fields: "*"}
+
+	var rooms []MeetingRoom
+	for {
+		rsp, _, err := s.ListRooms(opt, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("list rooms of %s: %d %s", buildingId, rsp.Code, rsp.Message)
+		}
+		for _, room := range rsp.Data.Rooms {
+			if !room.IsDisabled {
+				rooms = append(rooms, room)
+			}
+		}
+		if !rsp.Data.HasMore || len(rsp.Data.PageToken) == 0 {
+			return rooms, nil
+		}
+		opt.PageToken = rsp.Data.PageToken
+	}
+}
+
+// FindRoomsOptions 在 [Start, End) 内查找时长为 Duration 的时间段，参会人都空闲且有空闲的会议室。
+// Rooms 为空时查询 BuildingId 中的所有会议室，Capacity 为空时为参会人数。
+type FindRoomsOptions struct {
+	UserIdType string
+	UserIds    []string
+
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	// 候选时间段的间隔，默认 15 分钟
+	Step time.Duration
+
+	BuildingId string
+	Rooms      []MeetingRoom
+	Capacity   int
+
+	// 最多返回的结果数，默认 10
+	Limit int
+}
+
+// RoomSlot 空闲的会议室和时间段。
+type RoomSlot struct {
+	Room  MeetingRoom
+	Start time.Time
+	End   time.Time
+}
+
+// FindAvailableRooms 查询参会人和会议室的忙闲，返回可预定的会议室，按开始时间、会议室容量排序。
+func (s *MeetingRoomService) FindAvailableRooms(opt *FindRoomsOptions, options ...RequestOptionFunc) ([]RoomSlot, error) {
+	duration, step, limit := opt.Duration, opt.Step, opt.Limit
+	if duration <= 0 {
+		duration = opt.End.Sub(opt.Start)
+	}
+	if step <= 0 {
+		step = defaultRoomSlotStep
+	}
+	if limit <= 0 {
+		limit = defaultRoomSlotLimit
+	}
+	capacity := opt.Capacity
+	if capacity <= 0 {
+		capacity = len(opt.UserIds)
+	}
+	if duration <= 0 || opt.Start.Add(duration).After(opt.End) {
+		return nil, errors.Errorf("invalid time window %s - %s for duration %s", opt.Start, opt.End, duration)
+	}
+
+	rooms := opt.Rooms
+	if len(rooms) == 0 {
+		var err error
+		if rooms, err = s.AllRooms(opt.BuildingId, options...); err != nil {
+			return nil, err
+		}
+	}
+	var candidates []MeetingRoom
+	for _, room := range rooms {
+		if room.Capacity == 0 || room.Capacity >= capacity {
+			candidates = append(candidates, room)
+		}
+	}
+	// 容量小的优先
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Capacity < candidates[j].Capacity })
+
+	calendar := s.client.Calendar
+	usersBusy, err := calendar.UsersFreeBusy(opt.UserIdType, opt.UserIds, opt.Start, opt.End, options...)
+	if err != nil {
+		return nil, err
+	}
+	var busy []busyInterval
+	for _, list := range usersBusy {
+		intervals, err := parseFreeBusy(list)
+		if err != nil {
+			return nil, err
+		}
+		busy = append(busy, intervals...)
+	}
+
+	roomsBusy := make(map[string][]busyInterval, len(candidates))
+	for _, room := range candidates {
+		rsp, _, err := calendar.FreeBusy(opt.UserIdType, &FreeBusyOptions{
+			TimeMin: opt.Start.Format(time.RFC3339),
+			TimeMax: opt.End.Format(time.RFC3339),
+			RoomId:  room.RoomId,
+		}, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("freebusy of room %s: %d %s", room.RoomId, rsp.Code, rsp.Message)
+		}
+		if roomsBusy[room.RoomId], err = parseFreeBusy(rsp.Data.FreebusyList); err != nil {
+			return nil, err
+		}
+	}
+
+	var slots []RoomSlot
+	for start := opt.Start; !start.Add(duration).After(opt.End); start = start.Add(step) {
+		end := start.Add(duration)
+		if overlaps(busy, start, end) {
+			continue
+		}
+		for _, room := range candidates {
+			if overlaps(roomsBusy[room.RoomId], start, end) {
+				continue
+			}
+			slots = append(slots, RoomSlot{Room: room, Start: start, End: end})
+			if len(slots) >= limit {
+				return slots, nil
+			}
+		}
+	}
+	return slots, nil
+}
+
+// BookRoomOptions 在 CalendarId（如机器人的主日历）中创建日程，并邀请参会人和会议室。
+type BookRoomOptions struct {
+	CalendarId string
+	Event      *CalendarEvent
+	RoomId     string
+	UserIdType string
+	UserIds    []string
+}
+
+// BookRoom 创建日程并添加会议室和参会人。会议室的预定结果是异步的，
+// 通过 ListAttendees 查询会议室参会人的 RsvpStatus 确认是否预定成功。
+// 添加参会人失败时删除已创建的日程，删除也失败时返回日程，需要调用者删除。
+func (s *MeetingRoomService) BookRoom(opt *BookRoomOptions, options ...RequestOptionFunc) (*CalendarEvent, []Attendee, error) {
+	calendar := s.client.Calendar
+	created, _, err := calendar.CreateEvent(opt.CalendarId, opt.Event, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if created.Code != 0 {
+		return nil, nil, errors.Errorf("create event: %d %s", created.Code, created.Message)
+	}
+	event := &created.Data.Event
+
+	attendees := []Attendee{NewRoomAttendee(opt.RoomId)}
+	for _, userId := range opt.UserIds {
+		attendees = append(attendees, NewUserAttendee(userId))
+	}
+	rsp, _, err := calendar.CreateAttendees(opt.CalendarId, event.EventId, opt.UserIdType, &CreateAttendeesOptions{
+		Attendees:        attendees,
+		NeedNotification: true,
+	}, options...)
+	if err == nil && rsp.Code != 0 {
+		err = errors.Errorf("add attendees to event %s: %d %s", event.EventId, rsp.Code, rsp.Message)
+	}
+	if err != nil {
+		deleted, _, delErr := calendar.DeleteEvent(opt.CalendarId, event.EventId, false, options...)
+		if delErr == nil && deleted.Code != 0 {
+			delErr = errors.Errorf("%d %s", deleted.Code, deleted.Message)
+		}
+		if delErr != nil {
+			return event, nil, errors.Wrapf(delErr, "%v; delete event %s", err, event.EventId)
+		}
+		return nil, nil, err
+	}
+	return event, rsp.Data.Attendees, nil
+}
+
+type busyInterval struct {
+	start, end time.Time
+}
+
+func parseFreeBusy(list []FreeBusy) ([]busyInterval, error) {
+	intervals := make([]busyInterval, 0, len(list))
+	for _, fb := range list {
+		start, err := time.Parse(time.RFC3339, fb.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := time.Parse(time.RFC3339, fb.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, busyInterval{start: start, end: end})
+	}
+	return intervals, nil
+}
+
+func overlaps(intervals []busyInterval, start, end time.Time) bool {
+	for _, b := range intervals {
+		if b.start.Before(end) && start.Before(b.end) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMeetingRoomService_List(t *testing.T) {
+	Convey("test MeetingRoomService_List", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/meeting_room/building/list", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "fields=%2A&page_size=10")
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"page_token": "", "has_more": false, "buildings": [{"building_id": "omb_8ec170b937536a5d87c23b418b83f9bb", "name": "A", "floors": ["F1", "F2"]}]}}`)
+		})
+		mux.HandleFunc("/open-apis/vc/v1/rooms", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "room_level_id=omb_1")
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"rooms": [{"room_id": "omm_1", "name": "A-101", "capacity": 6, "room_level_id": "omb_1", "path": ["omb_0", "omb_1"]}], "has_more": false}}`)
+		})
+
+		buildings, _, err := client.MeetingRoom.ListBuildings(&ListBuildingsOptions{PageSize: 10, Fields: "*"})
+		So(err, ShouldBeNil)
+		So(buildings.Data.Buildings[0].Floors, ShouldResemble, []string{"F1", "F2"})
+
+		rooms, _, err := client.MeetingRoom.ListVcRooms(&ListVcRoomsOptions{RoomLevelId: "omb_1"})
+		So(err, ShouldBeNil)
+		So(rooms.Data.Rooms[0].Capacity, ShouldEqual, 6)
+	})
+}
+
+func TestMeetingRoomService_FindAndBook(t *testing.T) {
+	Convey("test MeetingRoomService_FindAndBook", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/meeting_room/room/list", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "building_id=omb_1&fields=%2A&page_size=100")
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"has_more": false, "rooms": [
+				{"room_id": "omm_big", "capacity": 20, "name": "Big"},
+				{"room_id": "omm_small", "capacity": 4, "name": "Small"},
+				{"room_id": "omm_tiny", "capacity": 1, "name": "Phone booth"},
+				{"room_id": "omm_closed", "capacity": 8, "is_disabled": true}
+			]}}`)
+		})
+
+		// 09:00-10:00 u1 忙，09:00-11:00 Small 忙
+		busy := map[string]string{
+			"u1":        `[{"start_time": "2022-05-01T09:00:00Z", "end_time": "2022-05-01T10:00:00Z"}]`,
+			"omm_small": `[{"start_time": "2022-05-01T09:00:00Z", "end_time": "2022-05-01T11:00:00Z"}]`,
+		}
+		mux.HandleFunc("/open-apis/calendar/v4/freebusy/list", func(w http.ResponseWriter, r *http.Request) {
+			opt := new(FreeBusyOptions)
+			if err := json.NewDecoder(r.Body).Decode(opt); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if opt.RoomId == "omm_tiny" || opt.RoomId == "omm_closed" {
+				t.Errorf("unexpected room %s", opt.RoomId)
+			}
+			list, ok := busy[opt.UserId+opt.RoomId]
+			if !ok {
+				list = "[]"
+			}
+			fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"freebusy_list": %s}}`, list)
+		})
+
+		start := time.Date(2022, 5, 1, 9, 0, 0, 0, time.UTC)
+		slots, err := client.MeetingRoom.FindAvailableRooms(&FindRoomsOptions{
+			UserIdType: "user_id",
+			UserIds:    []string{"u1", "u2"},
+			Start:      start,
+			End:        start.Add(3 * time.Hour),
+			Duration:   time.Hour,
+			Step:       30 * time.Minute,
+			BuildingId: "omb_1",
+			Limit:      3,
+		})
+		So(err, ShouldBeNil)
+		So(slots, ShouldHaveLength, 3)
+		So(slots[0].Room.RoomId, ShouldEqual, "omm_big")
+		So(slots[0].Start, ShouldEqual, start.Add(time.Hour))
+		So(slots[1].Room.RoomId, ShouldEqual, "omm_big")
+		So(slots[1].Start, ShouldEqual, start.Add(90*time.Minute))
+		So(slots[2].Room.RoomId, ShouldEqual, "omm_small")
+		So(slots[2].Start, ShouldEqual, start.Add(2*time.Hour))
+
+		_, err = client.MeetingRoom.FindAvailableRooms(&FindRoomsOptions{Start: start, End: start.Add(time.Hour), Duration: 2 * time.Hour})
+		So(err, ShouldNotBeNil)
+
+		eventsPath := "/open-apis/calendar/v4/calendars/" + testCalendarId + "/events"
+		mux.HandleFunc(eventsPath, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"event": {"event_id": "e1", "summary": "sync"}}}`)
+		})
+		mux.HandleFunc(eventsPath+"/e1/attendees", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "user_id_type=user_id")
+			testBody(t, r, `{"attendees":[{"type":"resource","room_id":"omm_big"},{"type":"user","user_id":"u1"},{"type":"user","user_id":"u2"}],"need_notification":true}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"attendees": [{"type": "resource", "attendee_id": "resource_1", "room_id": "omm_big", "rsvp_status": "needs_action"}]}}`)
+		})
+
+		slot := slots[0]
+		event, attendees, err := client.MeetingRoom.BookRoom(&BookRoomOptions{
+			CalendarId: testCalendarId,
+			Event:      &CalendarEvent{Summary: "sync", StartTime: NewEventTime(slot.Start), EndTime: NewEventTime(slot.End)},
+			RoomId:     slot.Room.RoomId,
+			UserIdType: "user_id",
+			UserIds:    []string{"u1", "u2"},
+		})
+		So(err, ShouldBeNil)
+		So(event.EventId, ShouldEqual, "e1")
+		So(attendees[0].RsvpStatus, ShouldEqual, "needs_action")
+	})
+}
+
+func TestMeetingRoomService_BookRoomFailed(t *testing.T) {
+	Convey("test MeetingRoomService_BookRoomFailed", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		eventsPath := "/open-apis/calendar/v4/calendars/" + testCalendarId + "/events"
+		mux.HandleFunc(eventsPath, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"event": {"event_id": "e1", "summary": "sync"}}}`)
+		})
+		mux.HandleFunc(eventsPath+"/e1/attendees", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"code": 190002, "msg": "invalid room", "data": {}}`)
+		})
+		deleted := 0
+		mux.HandleFunc(eventsPath+"/e1", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodDelete)
+			deleted++
+			if deleted == 1 {
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+				return
+			}
+			fmt.Fprint(w, `{"code": 193003, "msg": "event not found", "data": {}}`)
+		})
+
+		opt := &BookRoomOptions{
+			CalendarId: testCalendarId,
+			Event:      &CalendarEvent{Summary: "sync"},
+			RoomId:     "omm_gone",
+		}
+		// 添加会议室失败时删除日程
+		event, _, err := client.MeetingRoom.BookRoom(opt)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "invalid room")
+		So(event, ShouldBeNil)
+		So(deleted, ShouldEqual, 1)
+
+		// 删除失败时返回日程，由调用者处理
+		event, _, err = client.MeetingRoom.BookRoom(opt)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "invalid room")
+		So(err.Error(), ShouldContainSubstring, "event not found")
+		So(event.EventId, ShouldEqual, "e1")
+		So(deleted, ShouldEqual, 2)
+	})
+}
@@ -0,0 +1,107 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// 加急类型
+const (
+	UrgentApp   = "urgent_app"
+	UrgentSms   = "urgent_sms"
+	UrgentPhone = "urgent_phone"
+)
+
+type MessageService struct {
+	client *Client
+}
+
+type UserIdTypeQueryOptions struct {
+	UserIdType string `url:"user_id_type"`
+}
+
+type UrgentOptions struct {
+	UserIdList []string `json:"user_id_list"`
+}
+
+type UrgentResponse struct {
+	CodeMsg
+	Data struct {
+		// 无效的用户，如不在会话中
+		InvalidUserIdList []string `json:"invalid_user_id_list"`
+	} `json:"data"`
+}
+
+// UrgentApp 应用内加急，只能加急机器人自己发送的消息，userIdType 为 open_id、user_id、union_id。
+func (s *MessageService) UrgentApp(messageId, userIdType string, opt *UrgentOptions, options ...RequestOptionFunc) (*UrgentResponse, *Response, error) {
+	return s.Urgent(UrgentApp, messageId, userIdType, opt, options...)
+}
+
+// UrgentSms 短信加急。
+func (s *MessageService) UrgentSms(messageId, userIdType string, opt *UrgentOptions, options ...RequestOptionFunc) (*UrgentResponse, *Response, error) {
+	return s.Urgent(UrgentSms, messageId, userIdType, opt, options...)
+}
+
+// UrgentPhone 电话加急。
+func (s *MessageService) UrgentPhone(messageId, userIdType string, opt *UrgentOptions, options ...RequestOptionFunc) (*UrgentResponse, *Response, error) {
+	return s.Urgent(UrgentPhone, messageId, userIdType, opt, options...)
+}
+
+// Urgent 按加急类型加急消息，用于逐级升级，如 UrgentApp -> UrgentSms -> UrgentPhone。
+func (s *MessageService) Urgent(urgentType, messageId, userIdType string, opt *UrgentOptions, options ...RequestOptionFunc) (*UrgentResponse, *Response, error) {
+	u := fmt.Sprintf("im/v1/messages/%s/%s", messageId, urgentType)
+	options = append(options, WithQuery(&UserIdTypeQueryOptions{UserIdType: userIdType}))
+
+	req, err := s.client.NewServerRequest(http.MethodPatch, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(UrgentResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+type ReadUsersOptions struct {
+	UserIdType string `url:"user_id_type"`
+	PageSize   int    `url:"page_size,omitempty"`
+	PageToken  string `url:"page_token,omitempty"`
+}
+
+type ReadUser struct {
+	UserIdType string `json:"user_id_type"`
+	UserId     string `json:"user_id"`
+	Timestamp  string `json:"timestamp"`
+	TenantKey  string `json:"tenant_key"`
+}
+
+type ReadUsersResponse struct {
+	CodeMsg
+	Data struct {
+		Items     []ReadUser `json:"items"`
+		HasMore   bool       `json:"has_more"`
+		PageToken string     `json:"page_token"`
+	} `json:"data"`
+}
+
+// ReadUsers 查询消息的已读用户，只能查询机器人发送的 7 天内的消息，用于判断告警是否已被确认。
+func (s *MessageService) ReadUsers(messageId string, opt *ReadUsersOptions, options ...RequestOptionFunc) (*ReadUsersResponse, *Response, error) {
+	u := fmt.Sprintf("im/v1/messages/%s/read_users", messageId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ReadUsersResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
@@ -0,0 +1,97 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type Pin struct {
+	MessageId      string `json:"message_id"`
+	ChatId         string `json:"chat_id"`
+	OperatorId     string `json:"operator_id"`
+	OperatorIdType string `json:"operator_id_type"`
+	CreateTime     string `json:"create_time"`
+}
+
+type PinOptions struct {
+	MessageId string `json:"message_id"`
+}
+
+type PinResponse struct {
+	CodeMsg
+	Data struct {
+		Pin Pin `json:"pin"`
+	} `json:"data"`
+}
+
+// ListPinsOptions StartTime、EndTime 为毫秒时间戳。
+type ListPinsOptions struct {
+	ChatId    string `url:"chat_id"`
+	StartTime string `url:"start_time,omitempty"`
+	EndTime   string `url:"end_time,omitempty"`
+	PageSize  int    `url:"page_size,omitempty"`
+	PageToken string `url:"page_token,omitempty"`
+}
+
+type ListPinsResponse struct {
+	CodeMsg
+	Data struct {
+		Items     []Pin  `json:"items"`
+		HasMore   bool   `json:"has_more"`
+		PageToken string `json:"page_token"`
+	} `json:"data"`
+}
+
+// Pin 在会话中置顶消息（Pin）。
+func (s *MessageService) Pin(messageId string, options ...RequestOptionFunc) (*PinResponse, *Response, error) {
+	u := "im/v1/pins"
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, &PinOptions{MessageId: messageId}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(PinResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// Unpin 取消 Pin。
+func (s *MessageService) Unpin(messageId string, options ...RequestOptionFunc) (*ErrorMessage, *Response, error) {
+	u := fmt.Sprintf("im/v1/pins/%s", messageId)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ErrorMessage)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListPins 查询会话中 Pin 的消息，按 Pin 的时间倒序。
+func (s *MessageService) ListPins(opt *ListPinsOptions, options ...RequestOptionFunc) (*ListPinsResponse, *Response, error) {
+	u := "im/v1/pins"
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListPinsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
@@ -0,0 +1,44 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMessageService_Pins(t *testing.T) {
+	Convey("test MessageService_Pins", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/im/v1/pins", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				testBody(t, r, `{"message_id":"om_dc13264520392913993dd051dba21dcf"}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"pin": {"message_id": "om_dc13264520392913993dd051dba21dcf", "chat_id": "oc_a0553eda9014c201e6969b478895c230", "operator_id": "cli_a1b2c3", "operator_id_type": "app_id", "create_time": "1615380573211"}}}`)
+			case http.MethodGet:
+				testParams(t, r, "chat_id=oc_a0553eda9014c201e6969b478895c230&page_size=20")
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"items": [{"message_id": "om_dc13264520392913993dd051dba21dcf", "chat_id": "oc_a0553eda9014c201e6969b478895c230"}], "has_more": false, "page_token": ""}}`)
+			}
+		})
+		mux.HandleFunc("/open-apis/im/v1/pins/om_dc13264520392913993dd051dba21dcf", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodDelete)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+		})
+
+		pin, _, err := client.Message.Pin("om_dc13264520392913993dd051dba21dcf")
+		So(err, ShouldBeNil)
+		So(pin.Data.Pin.ChatId, ShouldEqual, "oc_a0553eda9014c201e6969b478895c230")
+
+		pins, _, err := client.Message.ListPins(&ListPinsOptions{ChatId: "oc_a0553eda9014c201e6969b478895c230", PageSize: 20})
+		So(err, ShouldBeNil)
+		So(pins.Data.Items, ShouldHaveLength, 1)
+
+		rsp, _, err := client.Message.Unpin("om_dc13264520392913993dd051dba21dcf")
+		So(err, ShouldBeNil)
+		So(rsp.Code, ShouldEqual, 0)
+	})
+}
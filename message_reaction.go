@@ -0,0 +1,140 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// 常用的表情，完整列表见飞书文档的表情文案说明
+const (
+	EmojiOK        = "OK"
+	EmojiThumbsUp  = "THUMBSUP"
+	EmojiDone      = "DONE"
+	EmojiGet       = "Get"
+	EmojiOnIt      = "OnIt"
+	EmojiSmile     = "SMILE"
+	EmojiJiaYi     = "JIAYI"
+	EmojiCrossMark = "CrossMark"
+)
+
+type Emoji struct {
+	EmojiType string `json:"emoji_type"`
+}
+
+type ReactionOperator struct {
+	OperatorId   string `json:"operator_id"`
+	OperatorType string `json:"operator_type"` // app、user
+}
+
+type Reaction struct {
+	ReactionId   string           `json:"reaction_id"`
+	Operator     ReactionOperator `json:"operator"`
+	ActionTime   string           `json:"action_time"`
+	ReactionType Emoji            `json:"reaction_type"`
+}
+
+type AddReactionOptions struct {
+	ReactionType Emoji `json:"reaction_type"`
+}
+
+type ReactionResponse struct {
+	CodeMsg
+	Data Reaction `json:"data"`
+}
+
+type ListReactionsOptions struct {
+	ReactionType string `url:"reaction_type,omitempty"`
+	UserIdType   string `url:"user_id_type,omitempty"`
+	PageSize     int    `url:"page_size,omitempty"`
+	PageToken    string `url:"page_token,omitempty"`
+}
+
+type ListReactionsResponse struct {
+	CodeMsg
+	Data struct {
+		Items     []Reaction `json:"items"`
+		HasMore   bool       `json:"has_more"`
+		PageToken string     `json:"page_token"`
+	} `json:"data"`
+}
+
+// AddReaction 添加表情回复，emojiType 如 EmojiThumbsUp。
+func (s *MessageService) AddReaction(messageId, emojiType string, options ...RequestOptionFunc) (*ReactionResponse, *Response, error) {
+	u := fmt.Sprintf("im/v1/messages/%s/reactions", messageId)
+	opt := &AddReactionOptions{ReactionType: Emoji{EmojiType: emojiType}}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ReactionResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListReactions 查询表情回复，ReactionType 为空时返回所有表情。
+func (s *MessageService) ListReactions(messageId string, opt *ListReactionsOptions, options ...RequestOptionFunc) (*ListReactionsResponse, *Response, error) {
+	u := fmt.Sprintf("im/v1/messages/%s/reactions", messageId)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListReactionsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AllReactions 翻页查询所有表情回复，如查询哪些用户确认了消息。
+func (s *MessageService) AllReactions(messageId string, opt *ListReactionsOptions, options ...RequestOptionFunc) ([]Reaction, error) {
+	o := ListReactionsOptions{}
+	if opt != nil {
+		o = *opt
+	}
+
+	var reactions []Reaction
+	for {
+		rsp, _, err := s.ListReactions(messageId, &o, options...)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != 0 {
+			return nil, errors.Errorf("list reactions: %d %s", rsp.Code, rsp.Message)
+		}
+		reactions = append(reactions, rsp.Data.Items...)
+		if !rsp.Data.HasMore || len(rsp.Data.PageToken) == 0 {
+			return reactions, nil
+		}
+		o.PageToken = rsp.Data.PageToken
+	}
+}
+
+// DeleteReaction 删除表情回复，只能删除自己添加的。
+func (s *MessageService) DeleteReaction(messageId, reactionId string, options ...RequestOptionFunc) (*ReactionResponse, *Response, error) {
+	u := fmt.Sprintf("im/v1/messages/%s/reactions/%s", messageId, reactionId)
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ReactionResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
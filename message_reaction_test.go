@@ -0,0 +1,59 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMessageService_Reactions(t *testing.T) {
+	Convey("test MessageService_Reactions", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		const messageId = "om_8964d1b4f1a2b3c4d5e62b31383276113"
+		mux.HandleFunc("/open-apis/im/v1/messages/"+messageId+"/reactions", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				testBody(t, r, `{"reaction_type":{"emoji_type":"THUMBSUP"}}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"reaction_id": "ZCaCIjUBVVWSrm5L-3ZTw_reaction", "operator": {"operator_id": "cli_a1b2c3", "operator_type": "app"}, "action_time": "1663054162546", "reaction_type": {"emoji_type": "THUMBSUP"}}}`)
+			case http.MethodGet:
+				if r.URL.Query().Get("page_token") == "" {
+					testParams(t, r, "page_size=1&reaction_type=OK&user_id_type=open_id")
+					fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"items": [{"reaction_id": "r1", "operator": {"operator_id": "ou_1", "operator_type": "user"}, "reaction_type": {"emoji_type": "OK"}}], "has_more": true, "page_token": "p2"}}`)
+					return
+				}
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"items": [{"reaction_id": "r2", "operator": {"operator_id": "ou_2", "operator_type": "user"}, "reaction_type": {"emoji_type": "OK"}}], "has_more": false}}`)
+			default:
+				t.Errorf("unexpected method %s", r.Method)
+			}
+		})
+		mux.HandleFunc("/open-apis/im/v1/messages/"+messageId+"/reactions/r1", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodDelete)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"reaction_id": "r1", "reaction_type": {"emoji_type": "OK"}}}`)
+		})
+
+		rsp, _, err := client.Message.AddReaction(messageId, EmojiThumbsUp)
+		So(err, ShouldBeNil)
+		So(rsp.Data.ReactionId, ShouldEqual, "ZCaCIjUBVVWSrm5L-3ZTw_reaction")
+		So(rsp.Data.Operator.OperatorType, ShouldEqual, "app")
+
+		opt := &ListReactionsOptions{ReactionType: EmojiOK, UserIdType: "open_id", PageSize: 1}
+		list, _, err := client.Message.ListReactions(messageId, opt)
+		So(err, ShouldBeNil)
+		So(list.Data.HasMore, ShouldBeTrue)
+
+		all, err := client.Message.AllReactions(messageId, opt)
+		So(err, ShouldBeNil)
+		So(all, ShouldHaveLength, 2)
+		So(all[1].Operator.OperatorId, ShouldEqual, "ou_2")
+		So(opt.PageToken, ShouldBeEmpty)
+
+		deleted, _, err := client.Message.DeleteReaction(messageId, "r1")
+		So(err, ShouldBeNil)
+		So(deleted.Data.ReactionId, ShouldEqual, "r1")
+	})
+}
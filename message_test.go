@@ -0,0 +1,66 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func mockTenantAccessToken(t *testing.T, mux *http.ServeMux) {
+	mux.HandleFunc("/open-apis/auth/v3/tenant_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		fmt.Fprint(w, `{"code": 0, "expire": 7200, "msg": "ok", "tenant_access_token": "t-caecc734c2e3328a62489fe0648c4b98779515d3"}`)
+	})
+}
+
+func TestMessageService_Urgent(t *testing.T) {
+	Convey("test MessageService_Urgent", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		for _, urgentType := range []string{UrgentApp, UrgentSms, UrgentPhone} {
+			mux.HandleFunc("/open-apis/im/v1/messages/om_dc13264520392913993dd051dba21dcf/"+urgentType, func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodPatch)
+				testParams(t, r, "user_id_type=open_id")
+				testBody(t, r, `{"user_id_list":["ou_6yf8o46d1a4f2c1baf0f6a5b1c5c0c60","ou_invalid"]}`)
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"invalid_user_id_list": ["ou_invalid"]}}`)
+			})
+		}
+
+		opt := &UrgentOptions{UserIdList: []string{"ou_6yf8o46d1a4f2c1baf0f6a5b1c5c0c60", "ou_invalid"}}
+		rsp, _, err := client.Message.UrgentApp("om_dc13264520392913993dd051dba21dcf", "open_id", opt)
+		So(err, ShouldBeNil)
+		So(rsp.Code, ShouldEqual, 0)
+		So(rsp.Data.InvalidUserIdList, ShouldResemble, []string{"ou_invalid"})
+
+		rsp, _, err = client.Message.UrgentSms("om_dc13264520392913993dd051dba21dcf", "open_id", opt)
+		So(err, ShouldBeNil)
+		So(rsp.Data.InvalidUserIdList, ShouldHaveLength, 1)
+
+		rsp, _, err = client.Message.UrgentPhone("om_dc13264520392913993dd051dba21dcf", "open_id", opt)
+		So(err, ShouldBeNil)
+		So(rsp.Data.InvalidUserIdList, ShouldHaveLength, 1)
+	})
+}
+
+func TestMessageService_ReadUsers(t *testing.T) {
+	Convey("test MessageService_ReadUsers", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/im/v1/messages/om_dc13264520392913993dd051dba21dcf/read_users", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "page_size=20&user_id_type=open_id")
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"items": [{"user_id_type": "open_id", "user_id": "ou_6yf8o46d1a4f2c1baf0f6a5b1c5c0c60", "timestamp": "1609484183000", "tenant_key": "736588c9260f175e"}], "has_more": false, "page_token": ""}}`)
+		})
+
+		rsp, _, err := client.Message.ReadUsers("om_dc13264520392913993dd051dba21dcf", &ReadUsersOptions{UserIdType: "open_id", PageSize: 20})
+		So(err, ShouldBeNil)
+		So(rsp.Data.Items, ShouldHaveLength, 1)
+		So(rsp.Data.Items[0].UserId, ShouldEqual, "ou_6yf8o46d1a4f2c1baf0f6a5b1c5c0c60")
+	})
+}
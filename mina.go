@@ -0,0 +1,130 @@
+package feishu
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const _minaSession = "mina_session"
+
+type MinaService struct {
+	client *Client
+}
+
+type Code2SessionOptions struct {
+	Code string `json:"code"`
+}
+
+type MinaSession struct {
+	OpenId       string `json:"open_id"`
+	UnionId      string `json:"union_id"`
+	EmployeeId   string `json:"employee_id"`
+	SessionKey   string `json:"session_key"`
+	TenantKey    string `json:"tenant_key"`
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type Code2SessionResponse struct {
+	CodeMsg
+	Data MinaSession `json:"data"`
+}
+
+// Code2Session 小程序/网页应用使用 tt.login 获取的 code 换取会话信息，需要 app_access_token。
+func (s *MinaService) Code2Session(code string, options ...RequestOptionFunc) (*Code2SessionResponse, *Response, error) {
+	u := "mina/v2/tokenLoginValidate"
+	opt := &Code2SessionOptions{Code: code}
+
+	req, err := s.client.NewAppServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(Code2SessionResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// MinaSessionStore 基于 TokenCache 保存小程序登录会话，返回给小程序的是随机的 session id，不暴露 session_key。
+type MinaSessionStore struct {
+	client *Client
+	Cache  TokenCache
+	// 会话有效期，为 0 时使用 access_token 的有效期
+	Expire time.Duration
+}
+
+func NewMinaSessionStore(client *Client, cache TokenCache, expire time.Duration) *MinaSessionStore {
+	return &MinaSessionStore{
+		client: client,
+		Cache:  cache,
+		Expire: expire,
+	}
+}
+
+func (s *MinaSessionStore) SessionKey(sessionID string) string {
+	return _minaSession + ":" + sessionID
+}
+
+// Login 用 code 换取会话并保存，返回 session id。
+func (s *MinaSessionStore) Login(code string, options ...RequestOptionFunc) (string, *MinaSession, error) {
+	c, _, err := s.client.Mina.Code2Session(code, options...)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.Code != 0 {
+		return "", nil, errors.Errorf("code2session failed: %d %s", c.Code, c.Message)
+	}
+
+	buf := make([]byte, 16)
+	if _, err = rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	sessionID := hex.EncodeToString(buf)
+	if err = s.Set(sessionID, &c.Data); err != nil {
+		return "", nil, err
+	}
+	return sessionID, &c.Data, nil
+}
+
+func (s *MinaSessionStore) Set(sessionID string, session *MinaSession) error {
+	buf, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	expire := s.Expire
+	if expire == 0 {
+		expire = time.Duration(session.ExpiresIn) * time.Second
+	}
+	if expire < 1 {
+		expire = 1
+	}
+	s.Cache.Set(s.SessionKey(sessionID), string(buf), expire)
+	return nil
+}
+
+func (s *MinaSessionStore) Get(sessionID string) (*MinaSession, bool) {
+	value, ok := s.Cache.Get(s.SessionKey(sessionID))
+	if !ok {
+		return nil, false
+	}
+	str, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	session := new(MinaSession)
+	if err := json.Unmarshal([]byte(str), session); err != nil {
+		return nil, false
+	}
+	return session, true
+}
@@ -0,0 +1,49 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMinaSessionStore_Login(t *testing.T) {
+	Convey("test MinaSessionStore_Login", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockAppAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/mina/v2/tokenLoginValidate", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"code":"a9f02f4b3b4c6c"}`)
+			fmt.Fprint(w, `{
+				"code": 0,
+				"msg": "ok",
+				"data": {
+					"open_id": "ou_3a0fb5d1a4c9c7",
+					"union_id": "on_94a1ee5551019f",
+					"employee_id": "f1ec5dc1",
+					"session_key": "7b7f8c7e8f1b4c1b",
+					"tenant_key": "736588c9260f175d",
+					"access_token": "u-xxx",
+					"expires_in": 7140,
+					"refresh_token": "ur-xxx"
+				}
+			}`)
+		})
+
+		store := NewMinaSessionStore(client, LocalTokenCache(), 0)
+		sessionID, session, err := store.Login("a9f02f4b3b4c6c")
+		So(err, ShouldBeNil)
+		So(sessionID, ShouldHaveLength, 32)
+		So(session.OpenId, ShouldEqual, "ou_3a0fb5d1a4c9c7")
+
+		got, ok := store.Get(sessionID)
+		So(ok, ShouldBeTrue)
+		So(got, ShouldResemble, session)
+
+		_, ok = store.Get("unknown")
+		So(ok, ShouldBeFalse)
+	})
+}
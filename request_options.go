@@ -2,6 +2,8 @@ package feishu
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/google/go-querystring/query"
 	"github.com/hashicorp/go-retryablehttp"
 )
@@ -36,3 +38,33 @@ func WithQuery(opt interface{}) RequestOptionFunc {
 		return nil
 	}
 }
+
+// WithHeader sets a header for this one request.
+func WithHeader(key, value string) RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		req.Header.Set(key, value)
+		return nil
+	}
+}
+
+// WithRange requests the bytes [start, end] of the content, end < 0 means
+// to the end, used to resume downloads.
+func WithRange(start, end int64) RequestOptionFunc {
+	if end < 0 {
+		return WithHeader("Range", fmt.Sprintf("bytes=%d-", start))
+	}
+	return WithHeader("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+}
+
+// WithJSONBody sets opt as the JSON encoded request body, used for the
+// DELETE requests with a body.
+func WithJSONBody(opt interface{}) RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		body, err := json.Marshal(opt)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req.SetBody(body)
+	}
+}
@@ -0,0 +1,536 @@
+package feishu
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// 自定义机器人限频：5 次/秒，100 次/分钟
+	defaultBotPerSecond = 5
+	defaultBotPerMinute = 100
+	// 应用向同一用户或群发消息限频：5 次/秒
+	defaultAppPerSecond = 5
+
+	defaultSenderMaxRetries  = 5
+	defaultSenderRetryWait   = time.Second
+	defaultSenderDedupWindow = time.Minute
+
+	// 应用消息限流的错误码
+	appCodeRateLimited      = 230020
+	appCodeFrequencyLimited = 99991400
+)
+
+// 队列空闲超过该时间（且超过 dedupWindow）后移除，此时限频器也已恢复
+var senderQueueIdle = time.Minute
+
+var ErrSenderClosed = errors.New("sender closed")
+
+// OutboundMessage 排队等待发送的消息，可序列化以便持久化。
+type OutboundMessage struct {
+	Id string `json:"id"`
+
+	// 自定义机器人消息使用 BotKey，需要先 Sender.AddBot
+	BotKey string `json:"bot_key,omitempty"`
+	// 应用消息的接收者
+	ReceiveIdType string `json:"receive_id_type,omitempty"`
+	ReceiveId     string `json:"receive_id,omitempty"`
+
+	MsgType string `json:"msg_type"`
+	// 消息内容，interactive 消息为卡片
+	Content json.RawMessage `json:"content"`
+	// 相同目的地、相同 DedupKey 的消息会合并，为空时使用内容的摘要
+	DedupKey string `json:"dedup_key,omitempty"`
+
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewBotMessage 创建发往自定义机器人的消息，content 为 TextContent、PostContent、卡片等。
+func NewBotMessage(botKey, msgType string, content interface{}) (*OutboundMessage, error) {
+	return newOutboundMessage(&OutboundMessage{BotKey: botKey, MsgType: msgType}, content)
+}
+
+// NewAppMessage 创建应用发送的消息。
+func NewAppMessage(receiveIdType, receiveId, msgType string, content interface{}) (*OutboundMessage, error) {
+	return newOutboundMessage(&OutboundMessage{ReceiveIdType: receiveIdType, ReceiveId: receiveId, MsgType: msgType}, content)
+}
+
+func newOutboundMessage(m *OutboundMessage, content interface{}) (*OutboundMessage, error) {
+	buf, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	m.Content = buf
+	return m, nil
+}
+
+// destination 消息的目的地，同一目的地的消息按顺序发送。
+func (m *OutboundMessage) destination() string {
+	if len(m.BotKey) > 0 {
+		return "bot:" + m.BotKey
+	}
+	return "app:" + m.ReceiveIdType + ":" + m.ReceiveId
+}
+
+func (m *OutboundMessage) dedupKey() string {
+	if len(m.DedupKey) > 0 {
+		return m.DedupKey
+	}
+	h := sha1.New()
+	h.Write([]byte(m.MsgType))
+	h.Write(m.Content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SenderStore 持久化待发送的消息，重启后继续发送。
+type SenderStore interface {
+	Save(m *OutboundMessage) error
+	Delete(id string) error
+	Load() ([]*OutboundMessage, error)
+}
+
+type senderQueue struct {
+	items    []*OutboundMessage
+	inflight *OutboundMessage
+	limiters []*rate.Limiter
+	signal   chan struct{}
+	// 最近发送过的消息，用于合并重复消息
+	sent map[string]time.Time
+}
+
+// Sender 按目的地排队发送消息，在 Client 的 RateLimiter 之外对每个目的地单独限频，
+// 合并重复消息，遇到限流时重试，并可持久化队列。
+type Sender struct {
+	client *Client
+	store  SenderStore
+
+	botPerSecond, botPerMinute float64
+	appPerSecond               float64
+	maxRetries                 int
+	retryWait                  time.Duration
+	dedupWindow                time.Duration
+
+	// ErrorHandler 消息最终发送失败时调用
+	ErrorHandler func(*OutboundMessage, error)
+
+	mu     sync.Mutex
+	bots   map[string]*WebhookBot
+	queues map[string]*senderQueue
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// SenderOptionFunc can be used to customize a Sender.
+type SenderOptionFunc func(*Sender)
+
+// WithSenderStore 持久化队列，例如 NewFileSenderStore、NewRedisSenderStore。
+func WithSenderStore(store SenderStore) SenderOptionFunc {
+	return func(s *Sender) {
+		s.store = store
+	}
+}
+
+// WithSenderBotLimit 设置每个机器人的限频，<= 0 表示不限制。
+func WithSenderBotLimit(perSecond, perMinute float64) SenderOptionFunc {
+	return func(s *Sender) {
+		s.botPerSecond, s.botPerMinute = perSecond, perMinute
+	}
+}
+
+// WithSenderAppLimit 设置应用向每个接收者发消息的限频，<= 0 表示不限制。
+func WithSenderAppLimit(perSecond float64) SenderOptionFunc {
+	return func(s *Sender) {
+		s.appPerSecond = perSecond
+	}
+}
+
+// WithSenderRetry 设置限流时的最大重试次数和初始等待时间，等待时间指数增长。
+func WithSenderRetry(maxRetries int, wait time.Duration) SenderOptionFunc {
+	return func(s *Sender) {
+		s.maxRetries, s.retryWait = maxRetries, wait
+	}
+}
+
+// WithSenderDedupWindow 设置合并重复消息的时间窗口，0 表示只合并还在排队的消息。
+func WithSenderDedupWindow(window time.Duration) SenderOptionFunc {
+	return func(s *Sender) {
+		s.dedupWindow = window
+	}
+}
+
+// WithSenderErrorHandler 消息最终发送失败时调用。
+func WithSenderErrorHandler(fn func(*OutboundMessage, error)) SenderOptionFunc {
+	return func(s *Sender) {
+		s.ErrorHandler = fn
+	}
+}
+
+// NewSender 创建 Sender，配置了 SenderStore 时会加载并继续发送上次未发送的消息，
+// 机器人消息需要在 Enqueue 之前 AddBot，恢复的机器人消息在 AddBot 后开始发送。
+func NewSender(client *Client, options ...SenderOptionFunc) (*Sender, error) {
+	s := &Sender{
+		client:       client,
+		botPerSecond: defaultBotPerSecond,
+		botPerMinute: defaultBotPerMinute,
+		appPerSecond: defaultAppPerSecond,
+		maxRetries:   defaultSenderMaxRetries,
+		retryWait:    defaultSenderRetryWait,
+		dedupWindow:  defaultSenderDedupWindow,
+		bots:         make(map[string]*WebhookBot),
+		queues:       make(map[string]*senderQueue),
+	}
+	for _, fn := range options {
+		if fn == nil {
+			continue
+		}
+		fn(s)
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	if s.store != nil {
+		pending, err := s.store.Load()
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		for _, m := range pending {
+			s.push(m)
+		}
+		s.mu.Unlock()
+	}
+	return s, nil
+}
+
+// AddBot 注册自定义机器人，之后可以向 bot.Key 发送消息。
+func (s *Sender) AddBot(bot *WebhookBot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bots[bot.Key] = bot
+	if q, ok := s.queues["bot:"+bot.Key]; ok {
+		q.notify()
+	}
+}
+
+// Enqueue 将消息加入队列，返回 false 表示和排队中或最近发送的消息重复，已合并。
+func (s *Sender) Enqueue(m *OutboundMessage) (bool, error) {
+	if len(m.BotKey) == 0 && len(m.ReceiveId) == 0 {
+		return false, errors.New("outbound message without destination")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false, ErrSenderClosed
+	}
+	if len(m.BotKey) > 0 {
+		if _, ok := s.bots[m.BotKey]; !ok {
+			return false, errors.Errorf("bot %s not added to sender", m.BotKey)
+		}
+	}
+
+	q := s.queue(m.destination())
+	key := m.dedupKey()
+	// 还在排队的重复消息，使用新的内容
+	for _, pending := range q.items {
+		if pending.dedupKey() != key {
+			continue
+		}
+		if pending == q.inflight {
+			// 正在发送，内容相同则丢弃，否则排在后面
+			if bytes.Equal(pending.Content, m.Content) {
+				return false, nil
+			}
+			continue
+		}
+		pending.Content = m.Content
+		if s.store != nil {
+			if err := s.store.Save(pending); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+	// 最近发送过的重复消息，丢弃
+	if t, ok := q.sent[key]; ok && time.Since(t) < s.dedupWindow {
+		return false, nil
+	}
+
+	if len(m.Id) == 0 {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return false, err
+		}
+		m.Id = hex.EncodeToString(buf)
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	if s.store != nil {
+		if err := s.store.Save(m); err != nil {
+			return false, err
+		}
+	}
+	s.push(m)
+	return true, nil
+}
+
+// Pending 返回还在排队的消息数。
+func (s *Sender) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, q := range s.queues {
+		n += len(q.items)
+	}
+	return n
+}
+
+// Close 停止接收新消息，等待队列发送完毕或 ctx 结束。未发送的消息保留在 SenderStore 中。
+func (s *Sender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	var err error
+	for s.Pending() > 0 && err == nil {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	s.cancel()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Sender) queue(dest string) *senderQueue {
+	q, ok := s.queues[dest]
+	if ok {
+		return q
+	}
+
+	q = &senderQueue{
+		signal: make(chan struct{}, 1),
+		sent:   make(map[string]time.Time),
+	}
+	if dest[:4] == "bot:" {
+		if s.botPerSecond > 0 {
+			q.limiters = append(q.limiters, rate.NewLimiter(rate.Limit(s.botPerSecond), int(s.botPerSecond)))
+		}
+		if s.botPerMinute > 0 {
+			q.limiters = append(q.limiters, rate.NewLimiter(rate.Limit(s.botPerMinute/60), int(s.botPerMinute)))
+		}
+	} else if s.appPerSecond > 0 {
+		q.limiters = append(q.limiters, rate.NewLimiter(rate.Limit(s.appPerSecond), int(s.appPerSecond)))
+	}
+	for _, l := range q.limiters {
+		if l.Burst() < 1 {
+			l.SetBurst(1)
+		}
+	}
+	s.queues[dest] = q
+
+	s.wg.Add(1)
+	go s.run(dest, q)
+	return q
+}
+
+// push 调用者需持有 s.mu。
+func (s *Sender) push(m *OutboundMessage) {
+	q := s.queue(m.destination())
+	q.items = append(q.items, m)
+	q.notify()
+}
+
+func (q *senderQueue) notify() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+// run 发送目的地的消息，队列空闲一段时间后从 s.queues 中移除并退出。
+func (s *Sender) run(dest string, q *senderQueue) {
+	defer s.wg.Done()
+
+	idle := senderQueueIdle
+	if s.dedupWindow > idle {
+		idle = s.dedupWindow
+	}
+	for {
+		s.mu.Lock()
+		var m *OutboundMessage
+		if len(q.items) > 0 {
+			m = q.items[0]
+			if len(m.BotKey) > 0 && s.bots[m.BotKey] == nil {
+				// 恢复的机器人消息，等待 AddBot
+				m = nil
+			}
+		}
+		q.inflight = m
+		empty := len(q.items) == 0
+		s.mu.Unlock()
+
+		if m == nil {
+			var timeout <-chan time.Time
+			if empty {
+				timeout = time.After(idle)
+			}
+			select {
+			case <-q.signal:
+			case <-timeout:
+				s.mu.Lock()
+				if len(q.items) == 0 {
+					delete(s.queues, dest)
+					s.mu.Unlock()
+					return
+				}
+				s.mu.Unlock()
+			case <-s.ctx.Done():
+				return
+			}
+			continue
+		}
+
+		for _, l := range q.limiters {
+			if err := l.Wait(s.ctx); err != nil {
+				return
+			}
+		}
+
+		err := s.send(m)
+		if s.ctx.Err() != nil {
+			// Close 超时中断了发送，消息保留在队列和 SenderStore 中
+			s.mu.Lock()
+			q.inflight = nil
+			s.mu.Unlock()
+			return
+		}
+		if err != nil && isRetryable(err) && m.Attempts < s.maxRetries {
+			wait := s.retryWait << uint(m.Attempts)
+			m.Attempts++
+			if s.store != nil {
+				_ = s.store.Save(m)
+			}
+			select {
+			case <-time.After(wait):
+				continue
+			case <-s.ctx.Done():
+				return
+			}
+		}
+
+		s.mu.Lock()
+		q.items = q.items[1:]
+		q.inflight = nil
+		now := time.Now()
+		if err == nil {
+			q.sent[m.dedupKey()] = now
+		}
+		for key, t := range q.sent {
+			if now.Sub(t) >= s.dedupWindow {
+				delete(q.sent, key)
+			}
+		}
+		s.mu.Unlock()
+
+		if s.store != nil {
+			_ = s.store.Delete(m.Id)
+		}
+		if err != nil && s.ErrorHandler != nil {
+			s.ErrorHandler(m, err)
+		}
+	}
+}
+
+// appMessageError 应用消息返回的错误码。
+type appMessageError struct {
+	CodeMsg
+}
+
+func (e *appMessageError) Error() string {
+	return fmt.Sprintf("send app message: %d %s", e.Code, e.Message)
+}
+
+func (s *Sender) send(m *OutboundMessage) error {
+	options := []RequestOptionFunc{WithContext(s.ctx)}
+	if len(m.BotKey) > 0 {
+		s.mu.Lock()
+		bot := s.bots[m.BotKey]
+		s.mu.Unlock()
+
+		msg := &webhookMessage{MsgType: m.MsgType, Content: m.Content}
+		if m.MsgType == MsgTypeInteractive {
+			msg = &webhookMessage{MsgType: m.MsgType, Card: m.Content}
+		}
+		_, _, err := bot.send(msg, options)
+		return err
+	}
+
+	opt := &AppCardMessageOption{
+		MsgType:   m.MsgType,
+		ReceiveID: m.ReceiveId,
+		Content:   string(m.Content),
+	}
+	// Content 为 json 字符串时直接使用
+	var content string
+	if json.Unmarshal(m.Content, &content) == nil {
+		opt.Content = content
+	}
+	c, _, err := s.client.App.SendAppCardMessage(m.ReceiveIdType, opt, options...)
+	if err != nil {
+		return err
+	}
+	if c.Code != 0 {
+		return &appMessageError{CodeMsg: c.CodeMsg}
+	}
+	return nil
+}
+
+// isRetryable 限流、5xx 和网络错误可以重试。
+func isRetryable(err error) bool {
+	if IsWebhookRateLimited(err) {
+		return true
+	}
+	var appErr *appMessageError
+	if errors.As(err, &appErr) {
+		return appErr.Code == appCodeRateLimited || appErr.Code == appCodeFrequencyLimited
+	}
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		status := errResp.Response.StatusCode
+		if status == http.StatusTooManyRequests || status >= http.StatusInternalServerError {
+			return true
+		}
+		c := new(CodeMsg)
+		if json.Unmarshal(errResp.Body, c) == nil {
+			return c.Code == appCodeRateLimited || c.Code == appCodeFrequencyLimited
+		}
+		return false
+	}
+	var webhookErr *WebhookError
+	if errors.As(err, &webhookErr) || errors.Is(err, ErrWebhookKeywordMissing) {
+		return false
+	}
+	return !errors.Is(err, context.Canceled)
+}
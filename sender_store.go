@@ -0,0 +1,146 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	senderFileExt          = ".json"
+	defaultSenderRedisHash = "feishu:sender:queue"
+)
+
+// FileSenderStore 每条消息保存为目录下的一个 json 文件。
+type FileSenderStore struct {
+	dir string
+}
+
+func NewFileSenderStore(dir string) (*FileSenderStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileSenderStore{dir: dir}, nil
+}
+
+func (s *FileSenderStore) path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+senderFileExt)
+}
+
+func (s *FileSenderStore) Save(m *OutboundMessage) error {
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再改名，避免进程退出时留下不完整的文件
+	tmp, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(buf); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(m.Id))
+}
+
+func (s *FileSenderStore) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Load 按消息创建时间排序返回。
+func (s *FileSenderStore) Load() ([]*OutboundMessage, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*OutboundMessage
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), senderFileExt) {
+			continue
+		}
+		buf, err := ioutil.ReadFile(filepath.Join(s.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m := new(OutboundMessage)
+		if err = json.Unmarshal(buf, m); err != nil {
+			continue
+		}
+		messages = append(messages, m)
+	}
+	sortOutboundMessages(messages)
+	return messages, nil
+}
+
+// RedisSenderStore 消息保存在 redis 的 hash 中。
+type RedisSenderStore struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisSenderStore key 为空时使用 feishu:sender:queue。
+func NewRedisSenderStore(addr, password, key string) *RedisSenderStore {
+	if len(key) == 0 {
+		key = defaultSenderRedisHash
+	}
+	return &RedisSenderStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: password,
+			DB:       0,
+		}),
+		key: key,
+	}
+}
+
+func (s *RedisSenderStore) Save(m *OutboundMessage) error {
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.client.HSet(context.Background(), s.key, m.Id, string(buf)).Err()
+}
+
+func (s *RedisSenderStore) Delete(id string) error {
+	return s.client.HDel(context.Background(), s.key, id).Err()
+}
+
+func (s *RedisSenderStore) Load() ([]*OutboundMessage, error) {
+	values, err := s.client.HGetAll(context.Background(), s.key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*OutboundMessage, 0, len(values))
+	for _, value := range values {
+		m := new(OutboundMessage)
+		if err = json.Unmarshal([]byte(value), m); err != nil {
+			continue
+		}
+		messages = append(messages, m)
+	}
+	sortOutboundMessages(messages)
+	return messages, nil
+}
+
+func sortOutboundMessages(messages []*OutboundMessage) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+}
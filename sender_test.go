@@ -0,0 +1,307 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const senderBotKey = "891105b7-1234-4567-7890-c4c372235090"
+
+type receivedWebhook struct {
+	mu       sync.Mutex
+	requests int
+	texts    []string
+}
+
+func (r *receivedWebhook) handle(t *testing.T, mux *http.ServeMux, fn func(n int) string) {
+	mux.HandleFunc("/open-apis/bot/v2/hook/"+senderBotKey, func(w http.ResponseWriter, req *http.Request) {
+		testMethod(t, req, http.MethodPost)
+		msg := new(struct {
+			Content TextContent `json:"content"`
+		})
+		if err := json.NewDecoder(req.Body).Decode(msg); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+
+		r.mu.Lock()
+		r.requests++
+		n := r.requests
+		r.mu.Unlock()
+
+		if body := fn(n); len(body) > 0 {
+			fmt.Fprint(w, body)
+			return
+		}
+		r.mu.Lock()
+		r.texts = append(r.texts, msg.Content.Text)
+		r.mu.Unlock()
+		fmt.Fprint(w, `{"StatusCode": 0, "StatusMessage": "success"}`)
+	})
+}
+
+func (r *receivedWebhook) received() (int, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests, append([]string(nil), r.texts...)
+}
+
+func closeSender(s *Sender) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.Close(ctx)
+}
+
+func TestSender_RetryRateLimited(t *testing.T) {
+	Convey("test Sender_RetryRateLimited", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		r := new(receivedWebhook)
+		r.handle(t, mux, func(n int) string {
+			if n == 1 {
+				return `{"code": 11232, "msg": "frequency limited", "data": {}}`
+			}
+			return ""
+		})
+
+		sender, err := NewSender(client, WithSenderRetry(3, 10*time.Millisecond))
+		So(err, ShouldBeNil)
+		bot, _ := client.Bot.NewWebhookBot(senderBotKey)
+		sender.AddBot(bot)
+
+		m, _ := NewBotMessage(senderBotKey, MsgTypeText, &TextContent{Text: "disk full"})
+		queued, err := sender.Enqueue(m)
+		So(err, ShouldBeNil)
+		So(queued, ShouldBeTrue)
+
+		So(closeSender(sender), ShouldBeNil)
+		requests, texts := r.received()
+		So(requests, ShouldEqual, 2)
+		So(texts, ShouldResemble, []string{"disk full"})
+
+		_, err = sender.Enqueue(m)
+		So(err, ShouldEqual, ErrSenderClosed)
+	})
+}
+
+func TestSender_Coalesce(t *testing.T) {
+	Convey("test Sender_Coalesce", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		release := make(chan struct{})
+		r := new(receivedWebhook)
+		r.handle(t, mux, func(n int) string {
+			if n == 1 {
+				<-release
+			}
+			return ""
+		})
+
+		sender, err := NewSender(client)
+		So(err, ShouldBeNil)
+		bot, _ := client.Bot.NewWebhookBot(senderBotKey)
+		sender.AddBot(bot)
+
+		first, _ := NewBotMessage(senderBotKey, MsgTypeText, &TextContent{Text: "deploying"})
+		_, err = sender.Enqueue(first)
+		So(err, ShouldBeNil)
+		// 等待第一条消息开始发送
+		for n, _ := r.received(); n == 0; n, _ = r.received() {
+			time.Sleep(time.Millisecond)
+		}
+
+		firing, _ := NewBotMessage(senderBotKey, MsgTypeText, &TextContent{Text: "[FIRING] disk full"})
+		firing.DedupKey = "alert:disk"
+		queued, _ := sender.Enqueue(firing)
+		So(queued, ShouldBeTrue)
+
+		// 相同 DedupKey 合并，使用新的内容
+		again, _ := NewBotMessage(senderBotKey, MsgTypeText, &TextContent{Text: "[FIRING] disk full x2"})
+		again.DedupKey = "alert:disk"
+		queued, _ = sender.Enqueue(again)
+		So(queued, ShouldBeFalse)
+
+		// 正在发送的相同消息直接丢弃
+		dup, _ := NewBotMessage(senderBotKey, MsgTypeText, &TextContent{Text: "deploying"})
+		queued, _ = sender.Enqueue(dup)
+		So(queued, ShouldBeFalse)
+		So(sender.Pending(), ShouldEqual, 2)
+
+		close(release)
+		So(closeSender(sender), ShouldBeNil)
+		_, texts := r.received()
+		So(texts, ShouldResemble, []string{"deploying", "[FIRING] disk full x2"})
+	})
+}
+
+func TestSender_FileStore(t *testing.T) {
+	Convey("test Sender_FileStore", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		r := new(receivedWebhook)
+		r.handle(t, mux, func(n int) string { return "" })
+
+		dir, err := ioutil.TempDir("", "feishu-sender")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		// 上次退出时未发送的消息
+		store, err := NewFileSenderStore(dir)
+		So(err, ShouldBeNil)
+		m, _ := NewBotMessage(senderBotKey, MsgTypeText, &TextContent{Text: "restored"})
+		m.Id = "pending-1"
+		m.CreatedAt = time.Now()
+		So(store.Save(m), ShouldBeNil)
+
+		sender, err := NewSender(client, WithSenderStore(store))
+		So(err, ShouldBeNil)
+		So(sender.Pending(), ShouldEqual, 1)
+
+		bot, _ := client.Bot.NewWebhookBot(senderBotKey)
+		sender.AddBot(bot)
+		So(closeSender(sender), ShouldBeNil)
+
+		_, texts := r.received()
+		So(texts, ShouldResemble, []string{"restored"})
+		pending, err := store.Load()
+		So(err, ShouldBeNil)
+		So(pending, ShouldBeEmpty)
+	})
+}
+
+func TestSender_AppMessage(t *testing.T) {
+	Convey("test Sender_AppMessage", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/open-apis/auth/v3/tenant_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"code": 0, "expire": 7200, "msg": "ok", "tenant_access_token": "t-caecc734c2e3328a62489fe0648c4b98779515d3"}`)
+		})
+		requests := 0
+		mux.HandleFunc("/open-apis/im/v1/messages", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "receive_id_type=chat_id")
+			requests++
+			if requests == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"code": 230020, "msg": "This operation triggers the frequency limit."}`)
+				return
+			}
+			testBody(t, r, `{"msg_type":"text","receive_id":"oc_a0553eda9014c201e6969b478895c230","content":"{\"text\":\"hello\"}"}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"message_id": "om_1"}}`)
+		})
+
+		failed := 0
+		sender, err := NewSender(client, WithSenderRetry(3, 10*time.Millisecond), WithSenderErrorHandler(func(*OutboundMessage, error) {
+			failed++
+		}))
+		So(err, ShouldBeNil)
+
+		m, _ := NewAppMessage("chat_id", "oc_a0553eda9014c201e6969b478895c230", MsgTypeText, &TextContent{Text: "hello"})
+		_, err = sender.Enqueue(m)
+		So(err, ShouldBeNil)
+		So(closeSender(sender), ShouldBeNil)
+		So(requests, ShouldEqual, 2)
+		So(failed, ShouldEqual, 0)
+	})
+}
+
+func TestSender_CloseTimeout(t *testing.T) {
+	Convey("test Sender_CloseTimeout", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		release := make(chan struct{})
+		defer close(release)
+		r := new(receivedWebhook)
+		r.handle(t, mux, func(n int) string {
+			<-release
+			return ""
+		})
+
+		dir, err := ioutil.TempDir("", "feishu-sender")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		store, err := NewFileSenderStore(dir)
+		So(err, ShouldBeNil)
+
+		failed := 0
+		sender, err := NewSender(client, WithSenderStore(store), WithSenderErrorHandler(func(*OutboundMessage, error) {
+			failed++
+		}))
+		So(err, ShouldBeNil)
+		bot, _ := client.Bot.NewWebhookBot(senderBotKey)
+		sender.AddBot(bot)
+
+		m, _ := NewBotMessage(senderBotKey, MsgTypeText, &TextContent{Text: "disk full"})
+		_, err = sender.Enqueue(m)
+		So(err, ShouldBeNil)
+		for n, _ := r.received(); n == 0; n, _ = r.received() {
+			time.Sleep(time.Millisecond)
+		}
+
+		// 发送被阻塞时 Close 超时，消息保留在 SenderStore 中
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		So(sender.Close(ctx), ShouldResemble, context.DeadlineExceeded)
+		So(failed, ShouldEqual, 0)
+		pending, err := store.Load()
+		So(err, ShouldBeNil)
+		So(pending, ShouldHaveLength, 1)
+		So(pending[0].Id, ShouldEqual, m.Id)
+	})
+}
+
+func TestSender_IdleQueue(t *testing.T) {
+	Convey("test Sender_IdleQueue", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		r := new(receivedWebhook)
+		r.handle(t, mux, func(n int) string { return "" })
+
+		idle := senderQueueIdle
+		senderQueueIdle = 10 * time.Millisecond
+		defer func() { senderQueueIdle = idle }()
+
+		sender, err := NewSender(client, WithSenderDedupWindow(0))
+		So(err, ShouldBeNil)
+		bot, _ := client.Bot.NewWebhookBot(senderBotKey)
+		sender.AddBot(bot)
+
+		m, _ := NewBotMessage(senderBotKey, MsgTypeText, &TextContent{Text: "disk full"})
+		_, err = sender.Enqueue(m)
+		So(err, ShouldBeNil)
+
+		// 发送完毕并空闲后移除队列
+		queues := func() int {
+			sender.mu.Lock()
+			defer sender.mu.Unlock()
+			return len(sender.queues)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for queues() > 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		So(queues(), ShouldEqual, 0)
+
+		// 移除后可以继续发送
+		m, _ = NewBotMessage(senderBotKey, MsgTypeText, &TextContent{Text: "disk ok"})
+		_, err = sender.Enqueue(m)
+		So(err, ShouldBeNil)
+		So(closeSender(sender), ShouldBeNil)
+		_, texts := r.received()
+		So(texts, ShouldResemble, []string{"disk full", "disk ok"})
+	})
+}
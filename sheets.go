@@ -0,0 +1,468 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type SheetsService struct {
+	client *Client
+}
+
+// 读取单元格时值的格式
+const (
+	ValueRenderOptionToString         = "ToString"
+	ValueRenderOptionFormattedValue   = "FormattedValue"
+	ValueRenderOptionFormula          = "Formula"
+	ValueRenderOptionUnformattedValue = "UnformattedValue"
+)
+
+// 追加数据时空间不足的处理方式
+const (
+	InsertDataOptionOverwrite  = "OVERWRITE"
+	InsertDataOptionInsertRows = "INSERT_ROWS"
+)
+
+const (
+	MajorDimensionRows    = "ROWS"
+	MajorDimensionColumns = "COLUMNS"
+)
+
+// 合并单元格的方式
+const (
+	MergeTypeAll     = "MERGE_ALL"
+	MergeTypeRows    = "MERGE_ROWS"
+	MergeTypeColumns = "MERGE_COLUMNS"
+)
+
+type Spreadsheet struct {
+	Title            string `json:"title"`
+	FolderToken      string `json:"folder_token"`
+	OwnerId          string `json:"owner_id"`
+	Url              string `json:"url"`
+	SpreadsheetToken string `json:"spreadsheet_token"`
+}
+
+type Sheet struct {
+	SheetId        string              `json:"sheet_id"`
+	Title          string              `json:"title"`
+	Index          int                 `json:"index"`
+	Hidden         bool                `json:"hidden"`
+	GridProperties SheetGridProperties `json:"grid_properties"`
+	ResourceType   string              `json:"resource_type"`
+	Merges         []SheetMerge        `json:"merges"`
+}
+
+type SheetGridProperties struct {
+	FrozenRowCount    int `json:"frozen_row_count"`
+	FrozenColumnCount int `json:"frozen_column_count"`
+	RowCount          int `json:"row_count"`
+	ColumnCount       int `json:"column_count"`
+}
+
+// SheetMerge 合并的单元格，行列从 0 开始，包含结束位置。
+type SheetMerge struct {
+	StartRowIndex    int `json:"start_row_index"`
+	EndRowIndex      int `json:"end_row_index"`
+	StartColumnIndex int `json:"start_column_index"`
+	EndColumnIndex   int `json:"end_column_index"`
+}
+
+// CreateSpreadsheetOptions FolderToken 为空时创建在根目录。
+type CreateSpreadsheetOptions struct {
+	Title       string `json:"title,omitempty"`
+	FolderToken string `json:"folder_token,omitempty"`
+}
+
+type SpreadsheetResponse struct {
+	CodeMsg
+	Data struct {
+		Spreadsheet Spreadsheet `json:"spreadsheet"`
+	} `json:"data"`
+}
+
+type QuerySheetsResponse struct {
+	CodeMsg
+	Data struct {
+		Sheets []Sheet `json:"sheets"`
+	} `json:"data"`
+}
+
+// GetValuesOptions ValueRenderOption 为空时公式返回计算结果，数字、日期返回原始值。
+type GetValuesOptions struct {
+	ValueRenderOption    string `url:"valueRenderOption,omitempty"`
+	DateTimeRenderOption string `url:"dateTimeRenderOption,omitempty"`
+	UserIdType           string `url:"user_id_type,omitempty"`
+}
+
+type BatchGetValuesOptions struct {
+	Ranges               []string `url:"ranges,comma"`
+	ValueRenderOption    string   `url:"valueRenderOption,omitempty"`
+	DateTimeRenderOption string   `url:"dateTimeRenderOption,omitempty"`
+	UserIdType           string   `url:"user_id_type,omitempty"`
+}
+
+type GetValuesResponse struct {
+	CodeMsg
+	Data struct {
+		Revision         int        `json:"revision"`
+		SpreadsheetToken string     `json:"spreadsheetToken"`
+		ValueRange       ValueRange `json:"valueRange"`
+	} `json:"data"`
+}
+
+type BatchGetValuesResponse struct {
+	CodeMsg
+	Data struct {
+		Revision         int          `json:"revision"`
+		SpreadsheetToken string       `json:"spreadsheetToken"`
+		TotalCells       int          `json:"totalCells"`
+		ValueRanges      []ValueRange `json:"valueRanges"`
+	} `json:"data"`
+}
+
+type UpdatedValues struct {
+	SpreadsheetToken string `json:"spreadsheetToken"`
+	UpdatedRange     string `json:"updatedRange"`
+	UpdatedRows      int    `json:"updatedRows"`
+	UpdatedColumns   int    `json:"updatedColumns"`
+	UpdatedCells     int    `json:"updatedCells"`
+	Revision         int    `json:"revision"`
+}
+
+type WriteValuesResponse struct {
+	CodeMsg
+	Data UpdatedValues `json:"data"`
+}
+
+type BatchWriteValuesResponse struct {
+	CodeMsg
+	Data struct {
+		Responses        []UpdatedValues `json:"responses"`
+		Revision         int             `json:"revision"`
+		SpreadsheetToken string          `json:"spreadsheetToken"`
+	} `json:"data"`
+}
+
+type AppendValuesQueryOptions struct {
+	InsertDataOption string `url:"insertDataOption,omitempty"`
+}
+
+type AppendValuesResponse struct {
+	CodeMsg
+	Data struct {
+		SpreadsheetToken string        `json:"spreadsheetToken"`
+		TableRange       string        `json:"tableRange"`
+		Revision         int           `json:"revision"`
+		Updates          UpdatedValues `json:"updates"`
+	} `json:"data"`
+}
+
+// Dimension 行或列的范围，StartIndex 从 0 开始，不包含 EndIndex。
+type Dimension struct {
+	SheetId        string `json:"sheetId"`
+	MajorDimension string `json:"majorDimension"`
+	StartIndex     int    `json:"startIndex"`
+	EndIndex       int    `json:"endIndex"`
+}
+
+// InsertDimensionOptions InheritStyle 为 BEFORE 或 AFTER 时继承前一行或后一行的样式。
+type InsertDimensionOptions struct {
+	Dimension    Dimension `json:"dimension"`
+	InheritStyle string    `json:"inheritStyle,omitempty"`
+}
+
+type AddDimensionResponse struct {
+	CodeMsg
+	Data struct {
+		AddCount       int    `json:"addCount"`
+		MajorDimension string `json:"majorDimension"`
+	} `json:"data"`
+}
+
+type DeleteDimensionResponse struct {
+	CodeMsg
+	Data struct {
+		DelCount       int    `json:"delCount"`
+		MajorDimension string `json:"majorDimension"`
+	} `json:"data"`
+}
+
+type MergeCellsOptions struct {
+	Range     string `json:"range"`
+	MergeType string `json:"mergeType,omitempty"`
+}
+
+type SheetsResponse struct {
+	CodeMsg
+	Data struct {
+		SpreadsheetToken string `json:"spreadsheetToken"`
+	} `json:"data"`
+}
+
+// CreateSpreadsheet 创建电子表格。
+func (s *SheetsService) CreateSpreadsheet(opt *CreateSpreadsheetOptions, options ...RequestOptionFunc) (*SpreadsheetResponse, *Response, error) {
+	u := "sheets/v3/spreadsheets"
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(SpreadsheetResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// GetSpreadsheet 查询电子表格的标题、所有者和链接。
+func (s *SheetsService) GetSpreadsheet(spreadsheetToken string, options ...RequestOptionFunc) (*SpreadsheetResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v3/spreadsheets/%s", spreadsheetToken)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(SpreadsheetResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// QuerySheets 查询所有工作表，包括行列数和合并的单元格。
+func (s *SheetsService) QuerySheets(spreadsheetToken string, options ...RequestOptionFunc) (*QuerySheetsResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v3/spreadsheets/%s/sheets/query", spreadsheetToken)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(QuerySheetsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// GetValues 读取一个范围，如 0b**12!A1:C3。
+func (s *SheetsService) GetValues(spreadsheetToken, valueRange string, opt *GetValuesOptions, options ...RequestOptionFunc) (*GetValuesResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/values/%s", spreadsheetToken, url.PathEscape(valueRange))
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(GetValuesResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// BatchGetValues 读取多个范围。
+func (s *SheetsService) BatchGetValues(spreadsheetToken string, opt *BatchGetValuesOptions, options ...RequestOptionFunc) (*BatchGetValuesResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/values_batch_get", spreadsheetToken)
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BatchGetValuesResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// WriteValues 写入一个范围，单次最多 5000 行、100 列。
+func (s *SheetsService) WriteValues(spreadsheetToken string, values *ValueRange, options ...RequestOptionFunc) (*WriteValuesResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/values", spreadsheetToken)
+	opt := struct {
+		ValueRange *ValueRange `json:"valueRange"`
+	}{values}
+
+	req, err := s.client.NewServerRequest(http.MethodPut, u, &opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(WriteValuesResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// BatchWriteValues 写入多个范围。
+func (s *SheetsService) BatchWriteValues(spreadsheetToken string, values []*ValueRange, options ...RequestOptionFunc) (*BatchWriteValuesResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/values_batch_update", spreadsheetToken)
+	opt := struct {
+		ValueRanges []*ValueRange `json:"valueRanges"`
+	}{values}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, &opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BatchWriteValuesResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AppendValues 在范围内第一个空行开始追加，insertDataOption 为空时覆盖空行。
+func (s *SheetsService) AppendValues(spreadsheetToken string, values *ValueRange, insertDataOption string, options ...RequestOptionFunc) (*AppendValuesResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/values_append", spreadsheetToken)
+	options = append(options, WithQuery(&AppendValuesQueryOptions{InsertDataOption: insertDataOption}))
+	opt := struct {
+		ValueRange *ValueRange `json:"valueRange"`
+	}{values}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, &opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(AppendValuesResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// InsertDimension 在 StartIndex 前插入 EndIndex-StartIndex 行或列。
+func (s *SheetsService) InsertDimension(spreadsheetToken string, opt *InsertDimensionOptions, options ...RequestOptionFunc) (*SheetsResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/insert_dimension_range", spreadsheetToken)
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(SheetsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// AddDimension 在工作表末尾增加 length 行或列，单次最多 5000。
+func (s *SheetsService) AddDimension(spreadsheetToken, sheetId, majorDimension string, length int, options ...RequestOptionFunc) (*AddDimensionResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/dimension_range", spreadsheetToken)
+	opt := struct {
+		Dimension struct {
+			SheetId        string `json:"sheetId"`
+			MajorDimension string `json:"majorDimension"`
+			Length         int    `json:"length"`
+		} `json:"dimension"`
+	}{}
+	opt.Dimension.SheetId, opt.Dimension.MajorDimension, opt.Dimension.Length = sheetId, majorDimension, length
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, &opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(AddDimensionResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteDimension 删除 [StartIndex, EndIndex) 的行或列。
+func (s *SheetsService) DeleteDimension(spreadsheetToken string, dimension *Dimension, options ...RequestOptionFunc) (*DeleteDimensionResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/dimension_range", spreadsheetToken)
+	// 接口的行列从 1 开始，包含结束位置
+	opt := struct {
+		Dimension Dimension `json:"dimension"`
+	}{*dimension}
+	opt.Dimension.StartIndex++
+	options = append(options, WithJSONBody(&opt))
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(DeleteDimensionResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// MergeCells 合并单元格，MergeType 为空时合并所有单元格。
+func (s *SheetsService) MergeCells(spreadsheetToken string, opt *MergeCellsOptions, options ...RequestOptionFunc) (*SheetsResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/merge_cells", spreadsheetToken)
+	if len(opt.MergeType) == 0 {
+		merge := *opt
+		merge.MergeType = MergeTypeAll
+		opt = &merge
+	}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(SheetsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// UnmergeCells 拆分范围内合并的单元格。
+func (s *SheetsService) UnmergeCells(spreadsheetToken, cellRange string, options ...RequestOptionFunc) (*SheetsResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/unmerge_cells", spreadsheetToken)
+	opt := struct {
+		Range string `json:"range"`
+	}{cellRange}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, &opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(SheetsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
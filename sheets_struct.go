@@ -0,0 +1,704 @@
+package feishu
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// 每次读取的行数
+	sheetReadRows = 1000
+	// 每次写入的行数和范围数
+	sheetWriteRows   = 1000
+	sheetWriteRanges = 100
+)
+
+// 自动查找表头时查找的行数
+const sheetHeaderScanRows = 10
+
+// 单元格日期的序列号从 1899-12-30 开始
+var sheetSerialEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SheetTableOptions HeaderRow 为表头所在的行，从 1 开始，0 时在前 10 行中查找，小于 0 时没有表头；
+// Location 为日期的时区，默认为 time.Local。
+type SheetTableOptions struct {
+	HeaderRow int
+	Location  *time.Location
+}
+
+// SheetTable 按结构体读写工作表的行，字段通过 tag 对应列：
+//
+//	type Host struct {
+//		Name  string    `feishu:"col=A"`
+//		Owner string    `feishu:"header=Owner"`
+//		Cores int       `feishu:"header=核数"`
+//		Since time.Time `feishu:"header=上线时间,format=2006-01-02"`
+//		Note  string    `feishu:"-"`
+//	}
+//
+// 只有 col 时按列名对应，只有 header 时按表头对应，没有 tag 的导出字段以字段名为表头。
+// Save 只写入和 Load 时相比变化的单元格，新增的行追加在最后，不会删除行。大的工作表分批读写，
+// 每个请求都经过客户端的限流。
+type SheetTable struct {
+	service          *SheetsService
+	spreadsheetToken string
+	sheetId          string
+	headerRow        int
+	location         *time.Location
+
+	rowType     reflect.Type
+	fields      []sheetField
+	writeHeader bool
+	// Load 时每行的行号和单元格，用于比较变化
+	rows     []int
+	snapshot [][]CellValue
+	lastRow  int
+}
+
+type sheetField struct {
+	index  int
+	col    int
+	header string
+	format string
+}
+
+// NewSheetTable opt 可以为空。
+func (s *SheetsService) NewSheetTable(spreadsheetToken, sheetId string, opt *SheetTableOptions) *SheetTable {
+	t := &SheetTable{service: s, spreadsheetToken: spreadsheetToken, sheetId: sheetId, location: time.Local}
+	if opt != nil {
+		t.headerRow = opt.HeaderRow
+		if opt.Location != nil {
+			t.location = opt.Location
+		}
+	}
+	return t
+}
+
+// Load 读取所有行到 dst，dst 为 *[]T 或 *[]*T，空行被跳过。
+func (t *SheetTable) Load(dst interface{}, options ...RequestOptionFunc) error {
+	slice := reflect.ValueOf(dst)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return errors.Errorf("load rows into %T, want pointer to slice", dst)
+	}
+	slice = slice.Elem()
+	rowType := sheetRowType(slice.Type().Elem())
+	if rowType == nil {
+		return errors.Errorf("load rows into %T, want slice of struct", dst)
+	}
+	fields, err := parseSheetFields(rowType)
+	if err != nil {
+		return err
+	}
+	t.rowType, t.fields = rowType, fields
+
+	rsp, _, err := t.service.QuerySheets(t.spreadsheetToken, options...)
+	if err != nil {
+		return err
+	}
+	if rsp.Code != 0 {
+		return errors.Errorf("query sheets of %s: %d %s", t.spreadsheetToken, rsp.Code, rsp.Message)
+	}
+	var sheet *Sheet
+	for i := range rsp.Data.Sheets {
+		if rsp.Data.Sheets[i].SheetId == t.sheetId {
+			sheet = &rsp.Data.Sheets[i]
+		}
+	}
+	if sheet == nil {
+		return errors.Errorf("sheet %s not found in %s", t.sheetId, t.spreadsheetToken)
+	}
+	grid := sheet.GridProperties
+
+	if err = t.resolveHeader(grid, options); err != nil {
+		return err
+	}
+
+	t.rows, t.snapshot, t.lastRow = nil, nil, t.headerRow
+	if t.lastRow < 0 {
+		t.lastRow = 0
+	}
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+	minCol, maxCol := t.columnSpan()
+	for start := t.lastRow + 1; start <= grid.RowCount; start += sheetReadRows {
+		end := start + sheetReadRows - 1
+		if end > grid.RowCount {
+			end = grid.RowCount
+		}
+		values, err := t.readRange(minCol, start, maxCol, end, options)
+		if err != nil {
+			return err
+		}
+		for i, row := range values {
+			elem := reflect.New(rowType).Elem()
+			cells := make([]CellValue, len(t.fields))
+			empty := true
+			for j, f := range t.fields {
+				var cell CellValue
+				if c := f.col - minCol; c < len(row) {
+					cell = row[c]
+				}
+				if CellString(cell) != "" {
+					empty = false
+				}
+				if err := t.decodeCell(cell, elem.Field(f.index), &f); err != nil {
+					return errors.Wrapf(err, "row %d column %s", start+i, ColumnName(f.col))
+				}
+				if cells[j], err = t.encodeCell(elem.Field(f.index), &f); err != nil {
+					return err
+				}
+			}
+			if empty {
+				continue
+			}
+			t.rows = append(t.rows, start+i)
+			t.snapshot = append(t.snapshot, cells)
+			t.lastRow = start + i
+			if slice.Type().Elem().Kind() == reflect.Ptr {
+				elem = elem.Addr()
+			}
+			slice.Set(reflect.Append(slice, elem))
+		}
+	}
+	return nil
+}
+
+// Save 写入 src 中变化的单元格，src 为 Load 得到的 []T 或 []*T，按顺序对应 Load 时的行，
+// 多出的行追加在最后。返回写入的单元格数。
+func (t *SheetTable) Save(src interface{}, options ...RequestOptionFunc) (int, error) {
+	if t.rowType == nil {
+		return 0, errors.New("save rows before load")
+	}
+	slice := reflect.Indirect(reflect.ValueOf(src))
+	if slice.Kind() != reflect.Slice {
+		return 0, errors.Errorf("save rows from %T, want slice", src)
+	}
+	if rowType := sheetRowType(slice.Type().Elem()); rowType != t.rowType {
+		return 0, errors.Errorf("save rows from %T, want slice of %s", src, t.rowType)
+	}
+
+	w := &sheetWriter{table: t, options: options}
+	if t.writeHeader {
+		header := make(map[int]CellValue, len(t.fields))
+		for _, f := range t.fields {
+			if len(f.header) > 0 {
+				header[f.col] = StringValue(f.header)
+			}
+		}
+		w.addRow(t.headerRow, header)
+	}
+
+	rows := make([]int, slice.Len())
+	snapshot := make([][]CellValue, slice.Len())
+	var appended [][]CellValue
+	for i := 0; i < slice.Len(); i++ {
+		elem := reflect.Indirect(slice.Index(i))
+		if !elem.IsValid() {
+			return 0, errors.Errorf("row %d is nil", i)
+		}
+		cells := make([]CellValue, len(t.fields))
+		for j := range t.fields {
+			var err error
+			if cells[j], err = t.encodeCell(elem.Field(t.fields[j].index), &t.fields[j]); err != nil {
+				return 0, errors.Wrapf(err, "row %d field %s", i, t.rowType.Field(t.fields[j].index).Name)
+			}
+		}
+		snapshot[i] = cells
+
+		if i >= len(t.snapshot) {
+			rows[i] = t.lastRow + len(appended) + 1
+			appended = append(appended, cells)
+			continue
+		}
+		rows[i] = t.rows[i]
+		changed := make(map[int]CellValue)
+		for j, f := range t.fields {
+			if !reflect.DeepEqual(cells[j], t.snapshot[i][j]) {
+				changed[f.col] = cells[j]
+			}
+		}
+		w.addRow(t.rows[i], changed)
+	}
+	w.addBlock(t.lastRow+1, appended)
+
+	if err := w.flush(); err != nil {
+		return w.cells, err
+	}
+	t.writeHeader = false
+	t.rows, t.snapshot = rows, snapshot
+	t.lastRow += len(appended)
+	return w.cells, nil
+}
+
+// resolveHeader 查找表头所在的行和只有 header 的字段所在的列。
+func (t *SheetTable) resolveHeader(grid SheetGridProperties, options []RequestOptionFunc) error {
+	t.writeHeader = false
+	if len(t.headers()) == 0 && t.headerRow == 0 {
+		// 所有字段都只有 col 时没有表头
+		t.headerRow = -1
+	}
+	if t.headerRow < 0 || len(t.headers()) == 0 {
+		return t.checkColumns()
+	}
+
+	scan := sheetHeaderScanRows
+	if t.headerRow > 0 {
+		scan = t.headerRow
+	}
+	if scan > grid.RowCount {
+		scan = grid.RowCount
+	}
+	var values [][]CellValue
+	if scan > 0 && grid.ColumnCount > 0 {
+		var err error
+		if values, err = t.readRange(0, 1, grid.ColumnCount-1, scan, options); err != nil {
+			return err
+		}
+	}
+
+	candidates := make([]int, 0, scan)
+	if t.headerRow > 0 {
+		candidates = append(candidates, t.headerRow)
+	} else {
+		for row := 1; row <= scan; row++ {
+			candidates = append(candidates, row)
+		}
+	}
+	for _, row := range candidates {
+		var cells []CellValue
+		if row-1 < len(values) {
+			cells = values[row-1]
+		}
+		if cols, ok := t.matchHeader(cells); ok {
+			t.headerRow = row
+			for i, col := range cols {
+				t.fields[i].col = col
+			}
+			return t.checkColumns()
+		}
+	}
+
+	// 表头为空时依次使用空闲的列，Save 时写入表头
+	empty := true
+	for _, row := range candidates {
+		if row-1 < len(values) {
+			for _, cell := range values[row-1] {
+				if CellString(cell) != "" {
+					empty = false
+				}
+			}
+		}
+	}
+	if !empty {
+		return errors.Errorf("header not found in sheet %s: %s", t.sheetId, strings.Join(t.headers(), ", "))
+	}
+	if t.headerRow == 0 {
+		t.headerRow = 1
+	}
+	used := make(map[int]bool)
+	for _, f := range t.fields {
+		if f.col >= 0 {
+			used[f.col] = true
+		}
+	}
+	next := 0
+	for i := range t.fields {
+		if t.fields[i].col >= 0 {
+			continue
+		}
+		for used[next] {
+			next++
+		}
+		t.fields[i].col = next
+		used[next] = true
+	}
+	t.writeHeader = true
+	return t.checkColumns()
+}
+
+// matchHeader 表头行需要包含只有 header 的字段，同时有 col 和 header 的字段需要在对应的列。
+func (t *SheetTable) matchHeader(cells []CellValue) ([]int, bool) {
+	cols := make([]int, len(t.fields))
+	checked := false
+	for i, f := range t.fields {
+		cols[i] = f.col
+		if len(f.header) == 0 {
+			continue
+		}
+		checked = true
+		if f.col >= 0 {
+			if f.col >= len(cells) || !strings.EqualFold(strings.TrimSpace(CellString(cells[f.col])), f.header) {
+				return nil, false
+			}
+			continue
+		}
+		cols[i] = -1
+		for c, cell := range cells {
+			if strings.EqualFold(strings.TrimSpace(CellString(cell)), f.header) {
+				cols[i] = c
+				break
+			}
+		}
+		if cols[i] < 0 {
+			return nil, false
+		}
+	}
+	return cols, checked
+}
+
+func (t *SheetTable) checkColumns() error {
+	used := make(map[int]string)
+	for _, f := range t.fields {
+		name := t.rowType.Field(f.index).Name
+		if f.col < 0 {
+			return errors.Errorf("column of field %s not found", name)
+		}
+		if other, ok := used[f.col]; ok {
+			return errors.Errorf("fields %s and %s both map to column %s", other, name, ColumnName(f.col))
+		}
+		used[f.col] = name
+	}
+	return nil
+}
+
+func (t *SheetTable) headers() []string {
+	var headers []string
+	for _, f := range t.fields {
+		if len(f.header) > 0 {
+			headers = append(headers, f.header)
+		}
+	}
+	return headers
+}
+
+func (t *SheetTable) columnSpan() (int, int) {
+	minCol, maxCol := math.MaxInt32, 0
+	for _, f := range t.fields {
+		if f.col < minCol {
+			minCol = f.col
+		}
+		if f.col > maxCol {
+			maxCol = f.col
+		}
+	}
+	return minCol, maxCol
+}
+
+func (t *SheetTable) cellRange(col0, row0, col1, row1 int) string {
+	return SheetRange(t.sheetId, fmt.Sprintf("%s%d:%s%d", ColumnName(col0), row0, ColumnName(col1), row1))
+}
+
+func (t *SheetTable) readRange(col0, row0, col1, row1 int, options []RequestOptionFunc) ([][]CellValue, error) {
+	cellRange := t.cellRange(col0, row0, col1, row1)
+	rsp, _, err := t.service.GetValues(t.spreadsheetToken, cellRange, &GetValuesOptions{
+		ValueRenderOption: ValueRenderOptionUnformattedValue,
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.Code != 0 {
+		return nil, errors.Errorf("get values of %s: %d %s", cellRange, rsp.Code, rsp.Message)
+	}
+	return rsp.Data.ValueRange.Values, nil
+}
+
+// encodeCell 字段的值转换为单元格，零值的时间和空指针为空单元格。
+func (t *SheetTable) encodeCell(v reflect.Value, f *sheetField) (CellValue, error) {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, nil
+	}
+	v = reflect.Indirect(v)
+	if v.Type() == timeType {
+		tm := v.Interface().(time.Time)
+		if tm.IsZero() {
+			return nil, nil
+		}
+		format := f.format
+		if len(format) == 0 {
+			format = "2006-01-02 15:04:05"
+		}
+		return StringValue(tm.In(t.location).Format(format)), nil
+	}
+	return CellValueOf(v.Interface())
+}
+
+// decodeCell 单元格转换为字段的值，数字可以是文本，日期可以是文本或序列号。
+func (t *SheetTable) decodeCell(cell CellValue, v reflect.Value, f *sheetField) error {
+	if cell == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if reflect.TypeOf(cell).AssignableTo(v.Type()) {
+		v.Set(reflect.ValueOf(cell))
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := t.decodeCell(cell, elem.Elem(), f); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	text := strings.TrimSpace(CellString(cell))
+	if v.Type() == timeType {
+		tm, err := t.parseTime(cell, text, f.format)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(tm))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(CellString(cell))
+	case reflect.Bool:
+		if b, ok := cell.(BoolValue); ok {
+			v.SetBool(bool(b))
+			return nil
+		}
+		if len(text) == 0 {
+			v.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return errors.Errorf("invalid bool %q", text)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := cellNumber(cell, text)
+		if err != nil {
+			return err
+		}
+		i := int64(math.Round(n))
+		if v.OverflowInt(i) {
+			return errors.Errorf("number %v overflows %s", n, v.Type())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := cellNumber(cell, text)
+		if err != nil {
+			return err
+		}
+		if n < 0 || v.OverflowUint(uint64(math.Round(n))) {
+			return errors.Errorf("number %v overflows %s", n, v.Type())
+		}
+		v.SetUint(uint64(math.Round(n)))
+	case reflect.Float32, reflect.Float64:
+		n, err := cellNumber(cell, text)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return errors.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+func cellNumber(cell CellValue, text string) (float64, error) {
+	if n, ok := cell.(NumberValue); ok {
+		return float64(n), nil
+	}
+	if len(text) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", ""), 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid number %q", text)
+	}
+	return n, nil
+}
+
+var sheetTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	time.RFC3339,
+}
+
+func (t *SheetTable) parseTime(cell CellValue, text, format string) (time.Time, error) {
+	if n, ok := cell.(NumberValue); ok {
+		// 序列号的整数部分为天数，小数部分为一天中的时间
+		d := time.Duration(math.Round(float64(n) * 24 * 3600))
+		tm := sheetSerialEpoch.Add(d * time.Second)
+		return time.Date(tm.Year(), tm.Month(), tm.Day(), tm.Hour(), tm.Minute(), tm.Second(), 0, t.location), nil
+	}
+	if len(text) == 0 {
+		return time.Time{}, nil
+	}
+	layouts := sheetTimeLayouts
+	if len(format) > 0 {
+		layouts = append([]string{format}, layouts...)
+	}
+	for _, layout := range layouts {
+		if tm, err := time.ParseInLocation(layout, text, t.location); err == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("invalid time %q", text)
+}
+
+// sheetRowType 返回元素为 T 或 *T 时的结构体类型。
+func sheetRowType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	return typ
+}
+
+// parseSheetFields 解析 feishu:"col=A,header=Owner,format=2006-01-02" 形式的 tag。
+func parseSheetFields(typ reflect.Type) ([]sheetField, error) {
+	var fields []sheetField
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag := sf.Tag.Get("feishu")
+		if len(sf.PkgPath) > 0 || tag == "-" {
+			continue
+		}
+
+		f := sheetField{index: i, col: -1}
+		for _, part := range strings.Split(tag, ",") {
+			if len(strings.TrimSpace(part)) == 0 {
+				continue
+			}
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.Errorf("invalid tag %q of field %s", tag, sf.Name)
+			}
+			key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+			switch key {
+			case "col":
+				if f.col = ColumnIndex(value); f.col < 0 {
+					return nil, errors.Errorf("invalid column %q of field %s", value, sf.Name)
+				}
+			case "header":
+				f.header = value
+			case "format":
+				f.format = value
+			default:
+				return nil, errors.Errorf("unknown tag key %q of field %s", key, sf.Name)
+			}
+		}
+		if f.col < 0 && len(f.header) == 0 {
+			f.header = sf.Name
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, errors.Errorf("no field of %s maps to a column", typ)
+	}
+	return fields, nil
+}
+
+// sheetWriter 合并连续的单元格为范围，分批写入。
+type sheetWriter struct {
+	table   *SheetTable
+	options []RequestOptionFunc
+	ranges  []*ValueRange
+	cells   int
+	pending int
+	err     error
+}
+
+// addRow 写入一行中的单元格，cells 的 key 为列序号。
+func (w *sheetWriter) addRow(row int, cells map[int]CellValue) {
+	cols := make([]int, 0, len(cells))
+	for col := range cells {
+		cols = append(cols, col)
+	}
+	sort.Ints(cols)
+	for i := 0; i < len(cols); {
+		j := i
+		for j+1 < len(cols) && cols[j+1] == cols[j]+1 {
+			j++
+		}
+		values := make([]CellValue, 0, j-i+1)
+		for _, col := range cols[i : j+1] {
+			values = append(values, cells[col])
+		}
+		w.add(&ValueRange{Range: w.table.cellRange(cols[i], row, cols[j], row), Values: [][]CellValue{values}})
+		i = j + 1
+	}
+}
+
+// addBlock 从 startRow 开始写入连续的行，每组连续的列为一个范围。
+func (w *sheetWriter) addBlock(startRow int, rows [][]CellValue) {
+	if len(rows) == 0 {
+		return
+	}
+	order := make([]int, len(w.table.fields))
+	for i := range order {
+		order[i] = i
+	}
+	fields := w.table.fields
+	sort.Slice(order, func(a, b int) bool { return fields[order[a]].col < fields[order[b]].col })
+
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && fields[order[j+1]].col == fields[order[j]].col+1 {
+			j++
+		}
+		for start := 0; start < len(rows); start += sheetWriteRows {
+			end := start + sheetWriteRows
+			if end > len(rows) {
+				end = len(rows)
+			}
+			values := make([][]CellValue, 0, end-start)
+			for _, row := range rows[start:end] {
+				line := make([]CellValue, 0, j-i+1)
+				for _, k := range order[i : j+1] {
+					line = append(line, row[k])
+				}
+				values = append(values, line)
+			}
+			cellRange := w.table.cellRange(fields[order[i]].col, startRow+start, fields[order[j]].col, startRow+end-1)
+			w.add(&ValueRange{Range: cellRange, Values: values})
+		}
+		i = j + 1
+	}
+}
+
+func (w *sheetWriter) add(r *ValueRange) {
+	w.ranges = append(w.ranges, r)
+	for _, row := range r.Values {
+		w.pending += len(row)
+	}
+	if len(w.ranges) >= sheetWriteRanges {
+		w.err = w.flush()
+	}
+}
+
+func (w *sheetWriter) flush() error {
+	if w.err != nil || len(w.ranges) == 0 {
+		return w.err
+	}
+	t := w.table
+	rsp, _, err := t.service.BatchWriteValues(t.spreadsheetToken, w.ranges, w.options...)
+	if err != nil {
+		return err
+	}
+	if rsp.Code != 0 {
+		return errors.Errorf("write values of %s: %d %s", t.sheetId, rsp.Code, rsp.Message)
+	}
+	w.cells += w.pending
+	w.ranges, w.pending = nil, 0
+	return nil
+}
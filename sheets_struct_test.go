@@ -0,0 +1,256 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testHost struct {
+	Name    string     `feishu:"col=A,header=主机"`
+	Owner   string     `feishu:"header=Owner"`
+	Cores   int        `feishu:"header=核数"`
+	Memory  float64    `feishu:"header=内存"`
+	Online  bool       `feishu:"header=在线"`
+	Since   time.Time  `feishu:"header=上线时间,format=2006-01-02"`
+	Retired *time.Time `feishu:"header=下线时间,format=2006-01-02"`
+	Note    string     `feishu:"-"`
+	Link    LinkValue  `feishu:"header=监控"`
+}
+
+// testSheetStore 模拟一个工作表的读写。
+type testSheetStore struct {
+	sheetId string
+	rows    int
+	columns int
+	cells   map[[2]int]CellValue
+	reads   int
+	writes  []string
+}
+
+var testCellRange = regexp.MustCompile(`^([A-Z]+)(\d+):([A-Z]+)(\d+)$`)
+
+func newTestSheetStore(t *testing.T, mux *http.ServeMux, rows, columns int, grid [][]CellValue) *testSheetStore {
+	s := &testSheetStore{sheetId: "0b**12", rows: rows, columns: columns, cells: make(map[[2]int]CellValue)}
+	for r, row := range grid {
+		for c, cell := range row {
+			if cell != nil {
+				s.cells[[2]int{r + 1, c}] = cell
+			}
+		}
+	}
+
+	base := "/open-apis/sheets/v2/spreadsheets/" + testSpreadsheetToken
+	mux.HandleFunc("/open-apis/sheets/v3/spreadsheets/"+testSpreadsheetToken+"/sheets/query", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"sheets": [{"sheet_id": "%s", "title": "Sheet1",
+			"grid_properties": {"row_count": %d, "column_count": %d}}]}}`, s.sheetId, s.rows, s.columns)
+	})
+	mux.HandleFunc(base+"/values/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testParams(t, r, "valueRenderOption=UnformattedValue")
+		s.reads++
+		rangeName := strings.TrimPrefix(r.URL.Path, base+"/values/")
+		c0, r0, c1, r1 := s.parseRange(t, rangeName)
+		vr := ValueRange{Range: rangeName}
+		for row := r0; row <= r1; row++ {
+			line := make([]CellValue, 0, c1-c0+1)
+			for col := c0; col <= c1; col++ {
+				line = append(line, s.cells[[2]int{row, col}])
+			}
+			vr.Values = append(vr.Values, line)
+		}
+		body, _ := json.Marshal(map[string]interface{}{"code": 0, "msg": "success", "data": map[string]interface{}{"valueRange": vr}})
+		w.Write(body)
+	})
+	mux.HandleFunc(base+"/values_batch_update", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		var opt struct {
+			ValueRanges []ValueRange `json:"valueRanges"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+			t.Errorf("decode values: %v", err)
+		}
+		for _, vr := range opt.ValueRanges {
+			s.writes = append(s.writes, vr.Range)
+			c0, r0, _, _ := s.parseRange(t, vr.Range)
+			for i, line := range vr.Values {
+				for j, cell := range line {
+					s.cells[[2]int{r0 + i, c0 + j}] = cell
+					if r0+i > s.rows {
+						s.rows = r0 + i
+					}
+				}
+			}
+		}
+		fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+	})
+	return s
+}
+
+func (s *testSheetStore) parseRange(t *testing.T, rangeName string) (int, int, int, int) {
+	parts := strings.SplitN(rangeName, "!", 2)
+	if len(parts) != 2 || parts[0] != s.sheetId {
+		t.Errorf("range %s", rangeName)
+		return 0, 1, 0, 0
+	}
+	m := testCellRange.FindStringSubmatch(parts[1])
+	if m == nil {
+		t.Errorf("range %s", rangeName)
+		return 0, 1, 0, 0
+	}
+	r0, _ := strconv.Atoi(m[2])
+	r1, _ := strconv.Atoi(m[4])
+	return ColumnIndex(m[1]), r0, ColumnIndex(m[3]), r1
+}
+
+func TestSheetTable_LoadSave(t *testing.T) {
+	Convey("test SheetTable_LoadSave", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		defer func(rows int) { sheetReadRows = rows }(sheetReadRows)
+		sheetReadRows = 2
+
+		store := newTestSheetStore(t, mux, 8, 10, [][]CellValue{
+			{StringValue("容量规划")},
+			{StringValue("主机"), StringValue("核数"), StringValue(" owner "), StringValue("内存"), StringValue("在线"),
+				StringValue("上线时间"), StringValue("下线时间"), StringValue("监控"), StringValue("备注")},
+			{StringValue("web-1"), NumberValue(16), StringValue("张三"), StringValue("1,024"), BoolValue(true),
+				NumberValue(44621), nil, LinkValue{Text: "面板", Link: "https://grafana.example.com/d/1"}, StringValue("主库")},
+			{},
+			{StringValue("web-2"), StringValue("8"), StringValue("李四"), NumberValue(32.5), StringValue("false"),
+				StringValue("2021-11-02"), StringValue("2022-06-30")},
+		})
+
+		loc := time.FixedZone("CST", 8*3600)
+		table := client.Sheets.NewSheetTable(testSpreadsheetToken, "0b**12", &SheetTableOptions{Location: loc})
+		var hosts []*testHost
+		So(table.Load(&hosts), ShouldBeNil)
+		So(hosts, ShouldHaveLength, 2)
+		// 表头 1 次，数据 (8-2)/2 = 3 次
+		So(store.reads, ShouldEqual, 4)
+
+		So(hosts[0].Name, ShouldEqual, "web-1")
+		So(hosts[0].Owner, ShouldEqual, "张三")
+		So(hosts[0].Cores, ShouldEqual, 16)
+		So(hosts[0].Memory, ShouldEqual, 1024)
+		So(hosts[0].Online, ShouldBeTrue)
+		So(hosts[0].Since.Equal(time.Date(2022, 3, 1, 0, 0, 0, 0, loc)), ShouldBeTrue)
+		So(hosts[0].Retired, ShouldBeNil)
+		So(hosts[0].Link.Link, ShouldEqual, "https://grafana.example.com/d/1")
+		So(hosts[1].Cores, ShouldEqual, 8)
+		So(hosts[1].Memory, ShouldEqual, 32.5)
+		So(hosts[1].Online, ShouldBeFalse)
+		So(hosts[1].Retired.Format("2006-01-02"), ShouldEqual, "2022-06-30")
+
+		Convey("save writes changed cells and appends new rows", func() {
+			hosts[0].Cores = 32
+			hosts[0].Memory = 2048
+			hosts[1].Retired = nil
+			hosts = append(hosts, &testHost{Name: "web-3", Owner: "王五", Cores: 4, Since: time.Date(2022, 7, 1, 0, 0, 0, 0, loc)})
+
+			n, err := table.Save(hosts)
+			So(err, ShouldBeNil)
+			So(store.writes, ShouldResemble, []string{
+				"0b**12!B3:B3", "0b**12!D3:D3", "0b**12!G5:G5",
+				"0b**12!A6:H6",
+			})
+			So(n, ShouldEqual, 11)
+			So(store.cells[[2]int{3, 1}], ShouldEqual, NumberValue(32))
+			So(store.cells[[2]int{5, 6}], ShouldBeNil)
+			So(store.cells[[2]int{6, 0}], ShouldEqual, StringValue("web-3"))
+			So(store.cells[[2]int{6, 5}], ShouldEqual, StringValue("2022-07-01"))
+			So(store.cells[[2]int{3, 8}], ShouldEqual, StringValue("主库"))
+
+			store.writes = nil
+			n, err = table.Save(hosts)
+			So(err, ShouldBeNil)
+			So(n, ShouldEqual, 0)
+			So(store.writes, ShouldBeEmpty)
+
+			var reloaded []testHost
+			So(table.Load(&reloaded), ShouldBeNil)
+			So(reloaded, ShouldHaveLength, 3)
+			So(reloaded[2].Owner, ShouldEqual, "王五")
+		})
+	})
+}
+
+func TestSheetTable_EmptySheet(t *testing.T) {
+	Convey("test SheetTable_EmptySheet", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		store := newTestSheetStore(t, mux, 0, 0, nil)
+
+		type row struct {
+			Id    int    `feishu:"col=C"`
+			Title string `feishu:"header=标题"`
+			Done  bool
+		}
+		table := client.Sheets.NewSheetTable(testSpreadsheetToken, "0b**12", nil)
+		var rows []row
+		So(table.Load(&rows), ShouldBeNil)
+		So(rows, ShouldBeEmpty)
+
+		rows = append(rows, row{Id: 1, Title: "扩容", Done: true})
+		n, err := table.Save(rows)
+		So(err, ShouldBeNil)
+		So(n, ShouldEqual, 5)
+		So(store.writes, ShouldResemble, []string{"0b**12!A1:B1", "0b**12!A2:C2"})
+		So(store.cells[[2]int{1, 0}], ShouldEqual, StringValue("标题"))
+		So(store.cells[[2]int{1, 1}], ShouldEqual, StringValue("Done"))
+		So(store.cells[[2]int{2, 2}], ShouldEqual, NumberValue(1))
+	})
+}
+
+func TestSheetTable_Errors(t *testing.T) {
+	Convey("test SheetTable_Errors", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		newTestSheetStore(t, mux, 2, 3, [][]CellValue{
+			{StringValue("主机"), StringValue("核数")},
+			{StringValue("web-1"), StringValue("很多")},
+		})
+		table := client.Sheets.NewSheetTable(testSpreadsheetToken, "0b**12", nil)
+
+		_, err := table.Save([]testHost{})
+		So(err, ShouldNotBeNil)
+
+		var bad []struct {
+			Name string `feishu:"col=1"`
+		}
+		So(table.Load(&bad), ShouldNotBeNil)
+
+		var missing []struct {
+			Name  string `feishu:"header=主机"`
+			Owner string `feishu:"header=Owner"`
+		}
+		err = table.Load(&missing)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "header not found")
+
+		var hosts []struct {
+			Name  string `feishu:"header=主机"`
+			Cores int    `feishu:"header=核数"`
+		}
+		err = table.Load(&hosts)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, `invalid number "很多"`)
+
+		So(ColumnIndex("A"), ShouldEqual, 0)
+		So(ColumnIndex("aa"), ShouldEqual, 26)
+		So(ColumnIndex("A1"), ShouldEqual, -1)
+	})
+}
@@ -0,0 +1,310 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// 水平对齐
+const (
+	CellHAlignLeft   = 0
+	CellHAlignCenter = 1
+	CellHAlignRight  = 2
+)
+
+// 垂直对齐
+const (
+	CellVAlignTop    = 0
+	CellVAlignMiddle = 1
+	CellVAlignBottom = 2
+)
+
+// 文本装饰
+const (
+	CellTextDecorationNone          = 0
+	CellTextDecorationUnderline     = 1
+	CellTextDecorationStrikethrough = 2
+	CellTextDecorationBoth          = 3
+)
+
+// 边框
+const (
+	CellBorderFull   = "FULL_BORDER"
+	CellBorderOuter  = "OUTER_BORDER"
+	CellBorderInner  = "INNER_BORDER"
+	CellBorderNone   = "NO_BORDER"
+	CellBorderLeft   = "LEFT_BORDER"
+	CellBorderRight  = "RIGHT_BORDER"
+	CellBorderTop    = "TOP_BORDER"
+	CellBorderBottom = "BOTTOM_BORDER"
+)
+
+// 条件格式的规则
+const (
+	ConditionRuleContainsBlanks    = "containsBlanks"
+	ConditionRuleNotContainsBlanks = "notContainsBlanks"
+	ConditionRuleDuplicateValues   = "duplicateValues"
+	ConditionRuleUniqueValues      = "uniqueValues"
+	ConditionRuleCellIs            = "cellIs"
+	ConditionRuleContainsText      = "containsText"
+	ConditionRuleTimePeriod        = "timePeriod"
+)
+
+// cellIs 规则的运算符
+const (
+	ConditionOperatorEqual              = "equal"
+	ConditionOperatorNotEqual           = "notEqual"
+	ConditionOperatorGreaterThan        = "greaterThan"
+	ConditionOperatorGreaterThanOrEqual = "greaterThanOrEqual"
+	ConditionOperatorLessThan           = "lessThan"
+	ConditionOperatorLessThanOrEqual    = "lessThanOrEqual"
+	ConditionOperatorBetween            = "between"
+	ConditionOperatorNotBetween         = "notBetween"
+)
+
+// CellFont FontSize 如 10pt/1.5，Clean 为清除字体格式。
+type CellFont struct {
+	Bold     bool   `json:"bold,omitempty"`
+	Italic   bool   `json:"italic,omitempty"`
+	FontSize string `json:"fontSize,omitempty"`
+	Clean    bool   `json:"clean,omitempty"`
+}
+
+// CellStyle 单元格样式，Formatter 为数字格式，如 #,##0.00；颜色如 #21d11f；Clean 为清除所有格式。
+type CellStyle struct {
+	Font           *CellFont `json:"font,omitempty"`
+	TextDecoration int       `json:"textDecoration,omitempty"`
+	Formatter      string    `json:"formatter,omitempty"`
+	HAlign         *int      `json:"hAlign,omitempty"`
+	VAlign         *int      `json:"vAlign,omitempty"`
+	ForeColor      string    `json:"foreColor,omitempty"`
+	BackColor      string    `json:"backColor,omitempty"`
+	BorderType     string    `json:"borderType,omitempty"`
+	BorderColor    string    `json:"borderColor,omitempty"`
+	Clean          bool      `json:"clean,omitempty"`
+}
+
+// CellAlign 对齐方式，用于 CellStyle 的 HAlign、VAlign。
+func CellAlign(align int) *int {
+	return &align
+}
+
+type RangesStyle struct {
+	Ranges []string   `json:"ranges"`
+	Style  *CellStyle `json:"style"`
+}
+
+type SetStyleResponse struct {
+	CodeMsg
+	Data UpdatedValues `json:"data"`
+}
+
+type BatchSetStyleResponse struct {
+	CodeMsg
+	Data struct {
+		Responses        []UpdatedValues `json:"responses"`
+		Revision         int             `json:"revision"`
+		SpreadsheetToken string          `json:"spreadsheetToken"`
+		TotalUpdatedRows int             `json:"totalUpdatedRows"`
+		TotalUpdatedCols int             `json:"totalUpdatedColumns"`
+	} `json:"data"`
+}
+
+// ConditionStyle 满足条件时的样式。
+type ConditionStyle struct {
+	Font           *CellFont `json:"font,omitempty"`
+	TextDecoration int       `json:"text_decoration,omitempty"`
+	ForeColor      string    `json:"fore_color,omitempty"`
+	BackColor      string    `json:"back_color,omitempty"`
+}
+
+// ConditionAttr cellIs 规则的 Formula 为比较的值，between 需要两个；containsText 规则的 Text 为包含的文本。
+type ConditionAttr struct {
+	Operator   string   `json:"operator,omitempty"`
+	Formula    []string `json:"formula,omitempty"`
+	Text       string   `json:"text,omitempty"`
+	TimePeriod string   `json:"time_period,omitempty"`
+}
+
+// ConditionFormat 条件格式，CfId 在创建后返回。
+type ConditionFormat struct {
+	CfId     string          `json:"cf_id,omitempty"`
+	Ranges   []string        `json:"ranges"`
+	RuleType string          `json:"rule_type"`
+	Attrs    []ConditionAttr `json:"attrs,omitempty"`
+	Style    *ConditionStyle `json:"style,omitempty"`
+}
+
+type SheetConditionFormat struct {
+	SheetId         string          `json:"sheet_id"`
+	ConditionFormat ConditionFormat `json:"condition_format"`
+}
+
+type ListConditionFormatsOptions struct {
+	SheetIds []string `url:"sheet_ids,comma"`
+}
+
+type ListConditionFormatsResponse struct {
+	CodeMsg
+	Data struct {
+		SheetConditionFormats []SheetConditionFormat `json:"sheet_condition_formats"`
+	} `json:"data"`
+}
+
+// ConditionFormatResult ResCode 不为 0 时该条件格式失败。
+type ConditionFormatResult struct {
+	SheetId string `json:"sheet_id"`
+	CfId    string `json:"cf_id"`
+	ResCode int    `json:"res_code"`
+	ResMsg  string `json:"res_msg"`
+}
+
+type ConditionFormatsResponse struct {
+	CodeMsg
+	Data struct {
+		Responses []ConditionFormatResult `json:"responses"`
+	} `json:"data"`
+}
+
+type SheetConditionFormatId struct {
+	SheetId string `json:"sheet_id"`
+	CfId    string `json:"cf_id"`
+}
+
+// NewCellIsConditionFormat 单元格的值满足 operator 时使用 style，如大于 100：
+// NewCellIsConditionFormat(ranges, ConditionOperatorGreaterThan, style, "100")。
+func NewCellIsConditionFormat(ranges []string, operator string, style *ConditionStyle, values ...string) ConditionFormat {
+	return ConditionFormat{
+		Ranges:   ranges,
+		RuleType: ConditionRuleCellIs,
+		Attrs:    []ConditionAttr{{Operator: operator, Formula: values}},
+		Style:    style,
+	}
+}
+
+// NewContainsTextConditionFormat 单元格包含 text 时使用 style。
+func NewContainsTextConditionFormat(ranges []string, text string, style *ConditionStyle) ConditionFormat {
+	return ConditionFormat{
+		Ranges:   ranges,
+		RuleType: ConditionRuleContainsText,
+		Attrs:    []ConditionAttr{{Operator: "containsText", Text: text}},
+		Style:    style,
+	}
+}
+
+// SetStyle 设置一个范围的样式。
+func (s *SheetsService) SetStyle(spreadsheetToken, cellRange string, style *CellStyle, options ...RequestOptionFunc) (*SetStyleResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/style", spreadsheetToken)
+	opt := struct {
+		AppendStyle struct {
+			Range string     `json:"range"`
+			Style *CellStyle `json:"style"`
+		} `json:"appendStyle"`
+	}{}
+	opt.AppendStyle.Range, opt.AppendStyle.Style = cellRange, style
+
+	req, err := s.client.NewServerRequest(http.MethodPut, u, &opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(SetStyleResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// BatchSetStyle 设置多组范围的样式。
+func (s *SheetsService) BatchSetStyle(spreadsheetToken string, styles []*RangesStyle, options ...RequestOptionFunc) (*BatchSetStyleResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/styles_batch_update", spreadsheetToken)
+	opt := struct {
+		Data []*RangesStyle `json:"data"`
+	}{styles}
+
+	req, err := s.client.NewServerRequest(http.MethodPut, u, &opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(BatchSetStyleResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// ListConditionFormats 查询工作表的条件格式。
+func (s *SheetsService) ListConditionFormats(spreadsheetToken string, sheetIds []string, options ...RequestOptionFunc) (*ListConditionFormatsResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/condition_formats", spreadsheetToken)
+	opt := &ListConditionFormatsOptions{SheetIds: sheetIds}
+
+	req, err := s.client.NewServerRequest(http.MethodGet, u, opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ListConditionFormatsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// CreateConditionFormats 创建条件格式，每个条件格式的结果见 Responses。
+func (s *SheetsService) CreateConditionFormats(spreadsheetToken string, formats []SheetConditionFormat, options ...RequestOptionFunc) (*ConditionFormatsResponse, *Response, error) {
+	return s.conditionFormats(spreadsheetToken, "batch_create", formats, options)
+}
+
+// UpdateConditionFormats 更新条件格式，需要 CfId。
+func (s *SheetsService) UpdateConditionFormats(spreadsheetToken string, formats []SheetConditionFormat, options ...RequestOptionFunc) (*ConditionFormatsResponse, *Response, error) {
+	return s.conditionFormats(spreadsheetToken, "batch_update", formats, options)
+}
+
+func (s *SheetsService) conditionFormats(spreadsheetToken, action string, formats []SheetConditionFormat, options []RequestOptionFunc) (*ConditionFormatsResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/condition_formats/%s", spreadsheetToken, action)
+	opt := struct {
+		SheetConditionFormats []SheetConditionFormat `json:"sheet_condition_formats"`
+	}{formats}
+
+	req, err := s.client.NewServerRequest(http.MethodPost, u, &opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ConditionFormatsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// DeleteConditionFormats 删除条件格式。
+func (s *SheetsService) DeleteConditionFormats(spreadsheetToken string, ids []SheetConditionFormatId, options ...RequestOptionFunc) (*ConditionFormatsResponse, *Response, error) {
+	u := fmt.Sprintf("sheets/v2/spreadsheets/%s/condition_formats/batch_delete", spreadsheetToken)
+	opt := struct {
+		SheetCfIds []SheetConditionFormatId `json:"sheet_cf_ids"`
+	}{ids}
+	options = append(options, WithJSONBody(&opt))
+
+	req, err := s.client.NewServerRequest(http.MethodDelete, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(ConditionFormatsResponse)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
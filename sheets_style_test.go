@@ -0,0 +1,97 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSheetsService_Style(t *testing.T) {
+	Convey("test SheetsService_Style", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		base := "/open-apis/sheets/v2/spreadsheets/" + testSpreadsheetToken
+		mux.HandleFunc(base+"/style", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPut)
+			testBody(t, r, `{"appendStyle":{"range":"0b**12!A1:C1","style":{"font":{"bold":true},"hAlign":0,"backColor":"#fff258","borderType":"FULL_BORDER"}}}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"updatedRange": "0b**12!A1:C1", "updatedCells": 3, "revision": 2}}`)
+		})
+		mux.HandleFunc(base+"/styles_batch_update", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPut)
+			testBody(t, r, `{"data":[{"ranges":["0b**12!B2:B9","0b**12!D2:D9"],"style":{"formatter":"#,##0.00"}}]}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"revision": 3, "totalUpdatedRows": 8, "responses": [{"updatedRange": "0b**12!B2:B9"}, {"updatedRange": "0b**12!D2:D9"}]}}`)
+		})
+
+		style, _, err := client.Sheets.SetStyle(testSpreadsheetToken, "0b**12!A1:C1", &CellStyle{
+			Font:       &CellFont{Bold: true},
+			HAlign:     CellAlign(CellHAlignLeft),
+			BackColor:  "#fff258",
+			BorderType: CellBorderFull,
+		})
+		So(err, ShouldBeNil)
+		So(style.Data.UpdatedCells, ShouldEqual, 3)
+
+		batch, _, err := client.Sheets.BatchSetStyle(testSpreadsheetToken, []*RangesStyle{
+			{Ranges: []string{"0b**12!B2:B9", "0b**12!D2:D9"}, Style: &CellStyle{Formatter: "#,##0.00"}},
+		})
+		So(err, ShouldBeNil)
+		So(batch.Data.Responses, ShouldHaveLength, 2)
+	})
+}
+
+func TestSheetsService_ConditionFormats(t *testing.T) {
+	Convey("test SheetsService_ConditionFormats", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		base := "/open-apis/sheets/v2/spreadsheets/" + testSpreadsheetToken + "/condition_formats"
+		mux.HandleFunc(base, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "sheet_ids=0b%2A%2A12")
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"sheet_condition_formats": [{"sheet_id": "0b**12", "condition_format": {
+				"cf_id": "6hfCeh", "ranges": ["0b**12!B2:B9"], "rule_type": "cellIs", "attrs": [{"operator": "greaterThan", "formula": ["64"]}],
+				"style": {"back_color": "#f76964"}}}]}}`)
+		})
+		mux.HandleFunc(base+"/batch_create", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"sheet_condition_formats":[{"sheet_id":"0b**12","condition_format":{"ranges":["0b**12!B2:B9"],"rule_type":"cellIs",`+
+				`"attrs":[{"operator":"greaterThan","formula":["64"]}],"style":{"back_color":"#f76964"}}},`+
+				`{"sheet_id":"0b**12","condition_format":{"ranges":["0b**12!A2:A9"],"rule_type":"containsText",`+
+				`"attrs":[{"operator":"containsText","text":"下线"}],"style":{"text_decoration":2}}}]}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"responses": [
+				{"sheet_id": "0b**12", "cf_id": "6hfCeh", "res_code": 0, "res_msg": "success"},
+				{"sheet_id": "0b**12", "cf_id": "", "res_code": 90202, "res_msg": "wrong ranges"}
+			]}}`)
+		})
+		mux.HandleFunc(base+"/batch_delete", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodDelete)
+			testBody(t, r, `{"sheet_cf_ids":[{"sheet_id":"0b**12","cf_id":"6hfCeh"}]}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"responses": [{"sheet_id": "0b**12", "cf_id": "6hfCeh", "res_code": 0}]}}`)
+		})
+
+		created, _, err := client.Sheets.CreateConditionFormats(testSpreadsheetToken, []SheetConditionFormat{
+			{SheetId: "0b**12", ConditionFormat: NewCellIsConditionFormat([]string{"0b**12!B2:B9"}, ConditionOperatorGreaterThan,
+				&ConditionStyle{BackColor: "#f76964"}, "64")},
+			{SheetId: "0b**12", ConditionFormat: NewContainsTextConditionFormat([]string{"0b**12!A2:A9"}, "下线",
+				&ConditionStyle{TextDecoration: CellTextDecorationStrikethrough})},
+		})
+		So(err, ShouldBeNil)
+		So(created.Data.Responses, ShouldHaveLength, 2)
+		So(created.Data.Responses[0].CfId, ShouldEqual, "6hfCeh")
+		So(created.Data.Responses[1].ResCode, ShouldEqual, 90202)
+
+		list, _, err := client.Sheets.ListConditionFormats(testSpreadsheetToken, []string{"0b**12"})
+		So(err, ShouldBeNil)
+		So(list.Data.SheetConditionFormats, ShouldHaveLength, 1)
+		So(list.Data.SheetConditionFormats[0].ConditionFormat.Attrs[0].Formula, ShouldResemble, []string{"64"})
+
+		deleted, _, err := client.Sheets.DeleteConditionFormats(testSpreadsheetToken, []SheetConditionFormatId{{SheetId: "0b**12", CfId: "6hfCeh"}})
+		So(err, ShouldBeNil)
+		So(deleted.Data.Responses[0].CfId, ShouldEqual, "6hfCeh")
+	})
+}
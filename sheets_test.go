@@ -0,0 +1,180 @@
+package feishu
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testSpreadsheetToken = "shtcnmBA*****yGehy8"
+
+func TestSheetsService_Spreadsheet(t *testing.T) {
+	Convey("test SheetsService_Spreadsheet", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		mux.HandleFunc("/open-apis/sheets/v3/spreadsheets/"+testSpreadsheetToken, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"spreadsheet": {"title": "容量规划", "owner_id": "ou_1", "spreadsheet_token": "%s", "url": "https://example.feishu.cn/sheets/%s"}}}`,
+				testSpreadsheetToken, testSpreadsheetToken)
+		})
+		mux.HandleFunc("/open-apis/sheets/v3/spreadsheets/"+testSpreadsheetToken+"/sheets/query", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"sheets": [{"sheet_id": "0b**12", "title": "Sheet1", "index": 0, "resource_type": "sheet",
+				"grid_properties": {"frozen_row_count": 1, "row_count": 200, "column_count": 20},
+				"merges": [{"start_row_index": 0, "end_row_index": 0, "start_column_index": 0, "end_column_index": 2}]}]}}`)
+		})
+
+		ss, _, err := client.Sheets.GetSpreadsheet(testSpreadsheetToken)
+		So(err, ShouldBeNil)
+		So(ss.Data.Spreadsheet.Title, ShouldEqual, "容量规划")
+		So(ss.Data.Spreadsheet.SpreadsheetToken, ShouldEqual, testSpreadsheetToken)
+
+		sheets, _, err := client.Sheets.QuerySheets(testSpreadsheetToken)
+		So(err, ShouldBeNil)
+		So(sheets.Data.Sheets, ShouldHaveLength, 1)
+		So(sheets.Data.Sheets[0].SheetId, ShouldEqual, "0b**12")
+		So(sheets.Data.Sheets[0].GridProperties.RowCount, ShouldEqual, 200)
+		So(sheets.Data.Sheets[0].Merges[0].EndColumnIndex, ShouldEqual, 2)
+	})
+}
+
+func TestSheetsService_Values(t *testing.T) {
+	Convey("test SheetsService_Values", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		base := "/open-apis/sheets/v2/spreadsheets/" + testSpreadsheetToken
+		mux.HandleFunc(base+"/values_batch_get", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "ranges=0b%2A%2A12%21A1%3AB2%2C0b%2A%2A12%21D1&valueRenderOption=Formula")
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"revision": 5, "totalCells": 5, "valueRanges": [
+				{"majorDimension": "ROWS", "range": "0b**12!A1:B2", "revision": 5, "values": [["主机", "核数"], ["web-1", 16]]},
+				{"majorDimension": "ROWS", "range": "0b**12!D1:D1", "revision": 5, "values": [[{"type": "formula", "text": "=SUM(B2:B9)"}]]}
+			]}}`)
+		})
+		mux.HandleFunc(base+"/values/0b**12!A1:B2", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"revision": 5, "valueRange": {"range": "0b**12!A1:B2", "values": [["主机", "核数"], ["web-1", 16]]}}}`)
+		})
+		mux.HandleFunc(base+"/values", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPut)
+			testBody(t, r, `{"valueRange":{"range":"0b**12!A2:B2","values":[["web-2",32]]}}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"updatedRange": "0b**12!A2:B2", "updatedRows": 1, "updatedColumns": 2, "updatedCells": 2, "revision": 6}}`)
+		})
+		mux.HandleFunc(base+"/values_batch_update", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"valueRanges":[{"range":"0b**12!A1:A1","values":[["主机"]]},{"range":"0b**12!C1:C1","values":[[{"text":"=B2*2","type":"formula"}]]}]}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"revision": 7, "responses": [{"updatedRange": "0b**12!A1:A1", "updatedCells": 1}, {"updatedRange": "0b**12!C1:C1", "updatedCells": 1}]}}`)
+		})
+		mux.HandleFunc(base+"/values_append", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testParams(t, r, "insertDataOption=INSERT_ROWS")
+			testBody(t, r, `{"valueRange":{"range":"0b**12!A1:B1","values":[["web-3",8]]}}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"tableRange": "0b**12!A1:B3", "revision": 8, "updates": {"updatedRange": "0b**12!A4:B4", "updatedRows": 1}}}`)
+		})
+
+		batch, _, err := client.Sheets.BatchGetValues(testSpreadsheetToken, &BatchGetValuesOptions{
+			Ranges:            []string{"0b**12!A1:B2", "0b**12!D1"},
+			ValueRenderOption: ValueRenderOptionFormula,
+		})
+		So(err, ShouldBeNil)
+		So(batch.Data.ValueRanges, ShouldHaveLength, 2)
+		So(batch.Data.ValueRanges[0].Values[1][1], ShouldEqual, NumberValue(16))
+		So(batch.Data.ValueRanges[1].Values[0][0], ShouldResemble, FormulaValue{Formula: "=SUM(B2:B9)"})
+
+		one, _, err := client.Sheets.GetValues(testSpreadsheetToken, "0b**12!A1:B2", nil)
+		So(err, ShouldBeNil)
+		So(CellString(one.Data.ValueRange.Values[1][0]), ShouldEqual, "web-1")
+
+		written, _, err := client.Sheets.WriteValues(testSpreadsheetToken, &ValueRange{
+			Range:  "0b**12!A2:B2",
+			Values: [][]CellValue{{StringValue("web-2"), NumberValue(32)}},
+		})
+		So(err, ShouldBeNil)
+		So(written.Data.UpdatedCells, ShouldEqual, 2)
+
+		batchWritten, _, err := client.Sheets.BatchWriteValues(testSpreadsheetToken, []*ValueRange{
+			{Range: "0b**12!A1:A1", Values: [][]CellValue{{StringValue("主机")}}},
+			{Range: "0b**12!C1:C1", Values: [][]CellValue{{FormulaValue{Formula: "=B2*2"}}}},
+		})
+		So(err, ShouldBeNil)
+		So(batchWritten.Data.Responses, ShouldHaveLength, 2)
+
+		appended, _, err := client.Sheets.AppendValues(testSpreadsheetToken, &ValueRange{
+			Range:  "0b**12!A1:B1",
+			Values: [][]CellValue{{StringValue("web-3"), NumberValue(8)}},
+		}, InsertDataOptionInsertRows)
+		So(err, ShouldBeNil)
+		So(appended.Data.Updates.UpdatedRange, ShouldEqual, "0b**12!A4:B4")
+	})
+}
+
+func TestSheetsService_Dimension(t *testing.T) {
+	Convey("test SheetsService_Dimension", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+		mockTenantAccessToken(t, mux)
+
+		base := "/open-apis/sheets/v2/spreadsheets/" + testSpreadsheetToken
+		mux.HandleFunc(base+"/insert_dimension_range", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"dimension":{"sheetId":"0b**12","majorDimension":"ROWS","startIndex":1,"endIndex":3},"inheritStyle":"BEFORE"}`)
+			fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {}}`)
+		})
+		mux.HandleFunc(base+"/dimension_range", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			switch r.Method {
+			case http.MethodPost:
+				if got := string(body); got != `{"dimension":{"sheetId":"0b**12","majorDimension":"COLUMNS","length":2}}` {
+					t.Errorf("Request body: %s", got)
+				}
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"addCount": 2, "majorDimension": "COLUMNS"}}`)
+			case http.MethodDelete:
+				if got := string(body); got != `{"dimension":{"sheetId":"0b**12","majorDimension":"ROWS","startIndex":2,"endIndex":3}}` {
+					t.Errorf("Request body: %s", got)
+				}
+				fmt.Fprint(w, `{"code": 0, "msg": "success", "data": {"delCount": 2, "majorDimension": "ROWS"}}`)
+			default:
+				t.Errorf("Request method: %s", r.Method)
+			}
+		})
+		mux.HandleFunc(base+"/merge_cells", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"range":"0b**12!A1:C1","mergeType":"MERGE_ALL"}`)
+			fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"spreadsheetToken": "%s"}}`, testSpreadsheetToken)
+		})
+		mux.HandleFunc(base+"/unmerge_cells", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"range":"0b**12!A1:C1"}`)
+			fmt.Fprintf(w, `{"code": 0, "msg": "success", "data": {"spreadsheetToken": "%s"}}`, testSpreadsheetToken)
+		})
+
+		_, _, err := client.Sheets.InsertDimension(testSpreadsheetToken, &InsertDimensionOptions{
+			Dimension:    Dimension{SheetId: "0b**12", MajorDimension: MajorDimensionRows, StartIndex: 1, EndIndex: 3},
+			InheritStyle: "BEFORE",
+		})
+		So(err, ShouldBeNil)
+
+		added, _, err := client.Sheets.AddDimension(testSpreadsheetToken, "0b**12", MajorDimensionColumns, 2)
+		So(err, ShouldBeNil)
+		So(added.Data.AddCount, ShouldEqual, 2)
+
+		// 删除第 2、3 行
+		deleted, _, err := client.Sheets.DeleteDimension(testSpreadsheetToken, &Dimension{SheetId: "0b**12", MajorDimension: MajorDimensionRows, StartIndex: 1, EndIndex: 3})
+		So(err, ShouldBeNil)
+		So(deleted.Data.DelCount, ShouldEqual, 2)
+
+		merged, _, err := client.Sheets.MergeCells(testSpreadsheetToken, &MergeCellsOptions{Range: "0b**12!A1:C1"})
+		So(err, ShouldBeNil)
+		So(merged.Data.SpreadsheetToken, ShouldEqual, testSpreadsheetToken)
+
+		_, _, err = client.Sheets.UnmergeCells(testSpreadsheetToken, "0b**12!A1:C1")
+		So(err, ShouldBeNil)
+	})
+}
@@ -0,0 +1,289 @@
+package feishu
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CellValue 单元格的值：StringValue、NumberValue、BoolValue、FormulaValue、LinkValue、MentionValue、
+// RichTextValue，空单元格为 nil。
+type CellValue interface {
+	// String 单元格显示的文本
+	String() string
+	cellValue()
+}
+
+type StringValue string
+
+type NumberValue float64
+
+// BoolValue 复选框。
+type BoolValue bool
+
+// FormulaValue 公式，如 =SUM(A1:A3)，读取时需要 ValueRenderOptionFormula。
+type FormulaValue struct {
+	Formula string
+}
+
+// LinkValue 超链接，Text 为显示的文本。
+type LinkValue struct {
+	Text string
+	Link string
+}
+
+// MentionValue 提及用户或文档。写入时 Text 为用户的邮箱、open_id 或文档 token，TextType 为
+// email、openId、fileToken；读取时 Text 为显示的文本，Token 为用户或文档的 token。
+type MentionValue struct {
+	Text                string
+	TextType            string
+	Token               string
+	Notify              bool
+	GrantReadPermission bool
+	ObjType             string
+}
+
+// CellSegment 富文本中的一段。
+type CellSegment struct {
+	Type        string `json:"type"`
+	Text        string `json:"text"`
+	Link        string `json:"link,omitempty"`
+	Token       string `json:"token,omitempty"`
+	MentionType int    `json:"mentionType,omitempty"`
+}
+
+// RichTextValue 多段文本、链接、提及组成的单元格，只在读取时返回。
+type RichTextValue []CellSegment
+
+func (StringValue) cellValue()   {}
+func (NumberValue) cellValue()   {}
+func (BoolValue) cellValue()     {}
+func (FormulaValue) cellValue()  {}
+func (LinkValue) cellValue()     {}
+func (MentionValue) cellValue()  {}
+func (RichTextValue) cellValue() {}
+
+func (v StringValue) String() string {
+	return string(v)
+}
+
+func (v NumberValue) String() string {
+	return strconv.FormatFloat(float64(v), 'f', -1, 64)
+}
+
+func (v BoolValue) String() string {
+	return strconv.FormatBool(bool(v))
+}
+
+func (v FormulaValue) String() string {
+	return v.Formula
+}
+
+func (v LinkValue) String() string {
+	return v.Text
+}
+
+func (v MentionValue) String() string {
+	return v.Text
+}
+
+func (v RichTextValue) String() string {
+	var b strings.Builder
+	for _, seg := range v {
+		b.WriteString(seg.Text)
+	}
+	return b.String()
+}
+
+func (v FormulaValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"type": "formula", "text": v.Formula})
+}
+
+func (v LinkValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"type": "url", "text": v.Text, "link": v.Link})
+}
+
+func (v MentionValue) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{
+		"type":                "mention",
+		"text":                v.Text,
+		"textType":            v.TextType,
+		"notify":              v.Notify,
+		"grantReadPermission": v.GrantReadPermission,
+	}
+	if len(v.ObjType) > 0 {
+		m["objType"] = v.ObjType
+	}
+	return json.Marshal(m)
+}
+
+// CellString 单元格显示的文本，空单元格为空字符串。
+func CellString(v CellValue) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// ParseCellValue 解析接口返回的单元格。
+func ParseCellValue(raw json.RawMessage) (CellValue, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	switch raw[0] {
+	case '"':
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return StringValue(s), err
+	case 't', 'f':
+		var b bool
+		err := json.Unmarshal(raw, &b)
+		return BoolValue(b), err
+	case '[':
+		var segments RichTextValue
+		err := json.Unmarshal(raw, &segments)
+		return segments, err
+	case '{':
+		var obj struct {
+			Type                string `json:"type"`
+			Text                string `json:"text"`
+			Link                string `json:"link"`
+			TextType            string `json:"textType"`
+			Token               string `json:"token"`
+			Notify              bool   `json:"notify"`
+			GrantReadPermission bool   `json:"grantReadPermission"`
+			ObjType             string `json:"objType"`
+		}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, err
+		}
+		switch obj.Type {
+		case "formula":
+			return FormulaValue{Formula: obj.Text}, nil
+		case "url":
+			return LinkValue{Text: obj.Text, Link: obj.Link}, nil
+		case "mention":
+			return MentionValue{Text: obj.Text, TextType: obj.TextType, Token: obj.Token, Notify: obj.Notify,
+				GrantReadPermission: obj.GrantReadPermission, ObjType: obj.ObjType}, nil
+		}
+		return StringValue(obj.Text), nil
+	}
+
+	var f float64
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, errors.Wrapf(err, "unknown cell value %s", raw)
+	}
+	return NumberValue(f), nil
+}
+
+// CellValueOf 把 Go 的值转换为单元格的值：字符串、数字、布尔值和 time.Time，nil 为空单元格，
+// 已经是 CellValue 时直接返回。
+func CellValueOf(v interface{}) (CellValue, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case CellValue:
+		return v, nil
+	case time.Time:
+		if v.IsZero() {
+			return nil, nil
+		}
+		return StringValue(v.Format("2006-01-02 15:04:05")), nil
+	case fmt.Stringer:
+		return StringValue(v.String()), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return CellValueOf(rv.Elem().Interface())
+	case reflect.String:
+		return StringValue(rv.String()), nil
+	case reflect.Bool:
+		return BoolValue(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NumberValue(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return NumberValue(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return NumberValue(rv.Float()), nil
+	}
+	return nil, errors.Errorf("unsupported cell value type %T", v)
+}
+
+// ValueRange 一个范围的值，Range 如 0b**12!A1:C3，按行排列。
+type ValueRange struct {
+	MajorDimension string        `json:"majorDimension,omitempty"`
+	Range          string        `json:"range"`
+	Revision       int           `json:"revision,omitempty"`
+	Values         [][]CellValue `json:"values"`
+}
+
+func (r *ValueRange) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		MajorDimension string              `json:"majorDimension"`
+		Range          string              `json:"range"`
+		Revision       int                 `json:"revision"`
+		Values         [][]json.RawMessage `json:"values"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.MajorDimension, r.Range, r.Revision = aux.MajorDimension, aux.Range, aux.Revision
+	r.Values = make([][]CellValue, len(aux.Values))
+	for i, row := range aux.Values {
+		r.Values[i] = make([]CellValue, len(row))
+		for j, raw := range row {
+			v, err := ParseCellValue(raw)
+			if err != nil {
+				return errors.Wrapf(err, "%s row %d column %d", aux.Range, i, j)
+			}
+			r.Values[i][j] = v
+		}
+	}
+	return nil
+}
+
+// SheetRange 工作表中的范围，如 SheetRange("0b**12", "A1:C3")。
+func SheetRange(sheetId, cells string) string {
+	if len(cells) == 0 {
+		return sheetId
+	}
+	return sheetId + "!" + cells
+}
+
+// ColumnName 列序号转换为列名，从 0 开始，如 0 为 A，26 为 AA。
+func ColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// ColumnIndex 列名转换为列序号，从 0 开始，列名不合法时返回 -1。
+func ColumnIndex(name string) int {
+	if len(name) == 0 {
+		return -1
+	}
+	index := 0
+	for _, c := range strings.ToUpper(name) {
+		if c < 'A' || c > 'Z' {
+			return -1
+		}
+		index = index*26 + int(c-'A') + 1
+	}
+	return index - 1
+}
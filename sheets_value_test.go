@@ -0,0 +1,95 @@
+package feishu
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValueRange_JSON(t *testing.T) {
+	Convey("test ValueRange_JSON", t, func() {
+		var vr ValueRange
+		err := json.Unmarshal([]byte(`{"majorDimension": "ROWS", "range": "0b**12!A1:F2", "revision": 3, "values": [
+			["名称", 12.5, true, null, {"type": "formula", "text": "=SUM(B1:B3)"}, [{"type": "text", "text": "见 "}, {"type": "url", "text": "文档", "link": "https://example.com"}]],
+			[{"type": "url", "text": "官网", "link": "https://feishu.cn"}, {"type": "mention", "text": "@张三", "token": "ou_1"}]
+		]}`), &vr)
+		So(err, ShouldBeNil)
+		So(vr.Range, ShouldEqual, "0b**12!A1:F2")
+		So(vr.Revision, ShouldEqual, 3)
+		So(vr.Values, ShouldHaveLength, 2)
+
+		row := vr.Values[0]
+		So(row[0], ShouldEqual, StringValue("名称"))
+		So(row[1], ShouldEqual, NumberValue(12.5))
+		So(row[2], ShouldEqual, BoolValue(true))
+		So(row[3], ShouldBeNil)
+		So(row[4], ShouldResemble, FormulaValue{Formula: "=SUM(B1:B3)"})
+		So(CellString(row[5]), ShouldEqual, "见 文档")
+		So(row[5].(RichTextValue)[1].Link, ShouldEqual, "https://example.com")
+		So(CellString(row[3]), ShouldEqual, "")
+
+		So(vr.Values[1][0], ShouldResemble, LinkValue{Text: "官网", Link: "https://feishu.cn"})
+		So(vr.Values[1][1].(MentionValue).Token, ShouldEqual, "ou_1")
+
+		write := ValueRange{Range: "0b**12!A1:E1", Values: [][]CellValue{{
+			StringValue("a"), NumberValue(1), nil,
+			FormulaValue{Formula: "=A1"},
+			LinkValue{Text: "官网", Link: "https://feishu.cn"},
+		}, {
+			MentionValue{Text: "zhangsan@example.com", TextType: "email", Notify: true},
+		}}}
+		body, err := json.Marshal(&write)
+		So(err, ShouldBeNil)
+		So(string(body), ShouldEqual, `{"range":"0b**12!A1:E1","values":[["a",1,null,{"text":"=A1","type":"formula"},`+
+			`{"link":"https://feishu.cn","text":"官网","type":"url"}],`+
+			`[{"grantReadPermission":false,"notify":true,"text":"zhangsan@example.com","textType":"email","type":"mention"}]]}`)
+	})
+}
+
+func TestCellValueOf(t *testing.T) {
+	Convey("test CellValueOf", t, func() {
+		n := 3
+		var nilPtr *int
+		cases := []struct {
+			in   interface{}
+			want CellValue
+		}{
+			{"a", StringValue("a")},
+			{42, NumberValue(42)},
+			{uint8(7), NumberValue(7)},
+			{1.5, NumberValue(1.5)},
+			{false, BoolValue(false)},
+			{&n, NumberValue(3)},
+			{nilPtr, nil},
+			{nil, nil},
+			{time.Time{}, nil},
+			{time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC), StringValue("2022-03-04 05:06:07")},
+			{LinkValue{Text: "a", Link: "b"}, LinkValue{Text: "a", Link: "b"}},
+		}
+		for _, c := range cases {
+			got, err := CellValueOf(c.in)
+			So(err, ShouldBeNil)
+			So(got, ShouldResemble, c.want)
+		}
+
+		_, err := CellValueOf([]int{1})
+		So(err, ShouldNotBeNil)
+
+		So(NumberValue(12.5).String(), ShouldEqual, "12.5")
+		So(NumberValue(100).String(), ShouldEqual, "100")
+	})
+}
+
+func TestColumnName(t *testing.T) {
+	Convey("test ColumnName", t, func() {
+		So(ColumnName(0), ShouldEqual, "A")
+		So(ColumnName(25), ShouldEqual, "Z")
+		So(ColumnName(26), ShouldEqual, "AA")
+		So(ColumnName(51), ShouldEqual, "AZ")
+		So(ColumnName(702), ShouldEqual, "AAA")
+		So(SheetRange("0b**12", "A1:B2"), ShouldEqual, "0b**12!A1:B2")
+		So(SheetRange("0b**12", ""), ShouldEqual, "0b**12")
+	})
+}
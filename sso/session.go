@@ -0,0 +1,131 @@
+package sso
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSessionCookie = "feishu_session"
+	defaultSessionMaxAge = 24 * time.Hour
+)
+
+var (
+	ErrNoSession       = errors.New("sso: no session")
+	ErrInvalidSession  = errors.New("sso: invalid session")
+	ErrSessionExpired  = errors.New("sso: session expired")
+	ErrEmptySigningKey = errors.New("sso: empty signing key")
+)
+
+// User 登录的飞书用户，保存在会话中并注入到请求的 context。
+type User struct {
+	OpenId    string `json:"open_id"`
+	UnionId   string `json:"union_id"`
+	UserId    string `json:"user_id,omitempty"`
+	Name      string `json:"name"`
+	Email     string `json:"email,omitempty"`
+	TenantKey string `json:"tenant_key,omitempty"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// SessionStore 保存登录会话，可替换为服务端存储（Redis、数据库等）。
+type SessionStore interface {
+	Load(r *http.Request) (*User, error)
+	Save(w http.ResponseWriter, r *http.Request, u *User) error
+	Clear(w http.ResponseWriter, r *http.Request) error
+}
+
+// CookieStore 把会话签名后直接保存在 cookie 中。
+type CookieStore struct {
+	key []byte
+
+	Name   string
+	Path   string
+	Domain string
+	Secure bool
+	MaxAge time.Duration
+}
+
+func NewCookieStore(signingKey []byte) (*CookieStore, error) {
+	if len(signingKey) == 0 {
+		return nil, ErrEmptySigningKey
+	}
+	return &CookieStore{
+		key:    signingKey,
+		Name:   defaultSessionCookie,
+		Path:   "/",
+		MaxAge: defaultSessionMaxAge,
+	}, nil
+}
+
+func (s *CookieStore) sign(payload string) string {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func (s *CookieStore) Load(r *http.Request) (*User, error) {
+	cookie, err := r.Cookie(s.Name)
+	if err != nil {
+		return nil, ErrNoSession
+	}
+
+	parts := strings.SplitN(cookie.Value, ".", 2)
+	if len(parts) != 2 || !hmac.Equal([]byte(s.sign(parts[0])), []byte(parts[1])) {
+		return nil, ErrInvalidSession
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+	u := new(User)
+	if err = json.Unmarshal(buf, u); err != nil {
+		return nil, ErrInvalidSession
+	}
+	if u.ExpiresAt > 0 && time.Now().Unix() >= u.ExpiresAt {
+		return nil, ErrSessionExpired
+	}
+	return u, nil
+}
+
+func (s *CookieStore) Save(w http.ResponseWriter, r *http.Request, u *User) error {
+	if u.ExpiresAt == 0 {
+		u.ExpiresAt = time.Now().Add(s.MaxAge).Unix()
+	}
+	buf, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(buf)
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.Name,
+		Value:    payload + "." + s.sign(payload),
+		Path:     s.Path,
+		Domain:   s.Domain,
+		Expires:  time.Unix(u.ExpiresAt, 0),
+		Secure:   s.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+func (s *CookieStore) Clear(w http.ResponseWriter, r *http.Request) error {
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.Name,
+		Value:    "",
+		Path:     s.Path,
+		Domain:   s.Domain,
+		MaxAge:   -1,
+		Secure:   s.Secure,
+		HttpOnly: true,
+	})
+	return nil
+}
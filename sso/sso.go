@@ -0,0 +1,259 @@
+// Package sso 为内部 web 应用提供飞书网页登录（SSO）中间件。
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	feishu "github.com/eyotang/go-feishu"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultCallbackPath = "/feishu/callback"
+	defaultStateCookie  = "feishu_state"
+	stateMaxAge         = 10 * time.Minute
+)
+
+type contextKey struct{}
+
+// UserFromContext 返回中间件注入的登录用户。
+func UserFromContext(ctx context.Context) (*User, bool) {
+	u, ok := ctx.Value(contextKey{}).(*User)
+	return u, ok
+}
+
+// NewContext 返回带有登录用户的 context，测试时也可用来伪造登录。
+func NewContext(ctx context.Context, u *User) context.Context {
+	return context.WithValue(ctx, contextKey{}, u)
+}
+
+// Middleware 未登录时重定向到飞书登录，处理回调并保存会话。
+type Middleware struct {
+	client *feishu.Client
+	store  SessionStore
+
+	callbackPath    string
+	redirectURL     string
+	stateCookie     string
+	secureCookie    bool
+	saveUserToken   bool
+	errorHandler    func(http.ResponseWriter, *http.Request, error)
+	unauthenticated func(http.ResponseWriter, *http.Request) bool
+}
+
+// OptionFunc can be used to customize the Middleware.
+type OptionFunc func(*Middleware)
+
+// WithCallbackPath 设置登录回调的路径，默认 /feishu/callback。
+func WithCallbackPath(path string) OptionFunc {
+	return func(m *Middleware) {
+		m.callbackPath = path
+	}
+}
+
+// WithRedirectURL 设置完整的回调地址（需要在飞书后台配置），默认根据请求的 Host 生成。
+func WithRedirectURL(redirectURL string) OptionFunc {
+	return func(m *Middleware) {
+		m.redirectURL = redirectURL
+		if u, err := url.Parse(redirectURL); err == nil && len(u.Path) > 0 {
+			m.callbackPath = u.Path
+		}
+	}
+}
+
+// WithSecureCookie 只在 https 下发送 state cookie。
+func WithSecureCookie() OptionFunc {
+	return func(m *Middleware) {
+		m.secureCookie = true
+	}
+}
+
+// WithUserAccessTokenSaved 登录后把 user_access_token 保存到 Client.UserAccessTokens，以便后续以用户身份调用接口。
+func WithUserAccessTokenSaved() OptionFunc {
+	return func(m *Middleware) {
+		m.saveUserToken = true
+	}
+}
+
+// WithErrorHandler 自定义登录失败时的处理，默认返回 401。
+func WithErrorHandler(fn func(http.ResponseWriter, *http.Request, error)) OptionFunc {
+	return func(m *Middleware) {
+		m.errorHandler = fn
+	}
+}
+
+// WithUnauthenticatedHandler 未登录时先调用 fn，返回 true 表示已处理（例如 API 请求直接返回 401），不再重定向。
+func WithUnauthenticatedHandler(fn func(http.ResponseWriter, *http.Request) bool) OptionFunc {
+	return func(m *Middleware) {
+		m.unauthenticated = fn
+	}
+}
+
+func New(client *feishu.Client, store SessionStore, options ...OptionFunc) *Middleware {
+	m := &Middleware{
+		client:       client,
+		store:        store,
+		callbackPath: defaultCallbackPath,
+		stateCookie:  defaultStateCookie,
+		errorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		},
+	}
+	for _, fn := range options {
+		if fn == nil {
+			continue
+		}
+		fn(m)
+	}
+	return m
+}
+
+// Handler 包装 next，只有登录用户才能访问。
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == m.callbackPath {
+			m.handleCallback(w, r)
+			return
+		}
+
+		if u, err := m.store.Load(r); err == nil {
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), u)))
+			return
+		}
+
+		if m.unauthenticated != nil && m.unauthenticated(w, r) {
+			return
+		}
+		m.redirectToLogin(w, r)
+	})
+}
+
+// Logout 清除会话。
+func (m *Middleware) Logout(w http.ResponseWriter, r *http.Request) error {
+	return m.store.Clear(w, r)
+}
+
+func (m *Middleware) callbackURL(r *http.Request) string {
+	if len(m.redirectURL) > 0 {
+		return m.redirectURL
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + m.callbackPath
+}
+
+func (m *Middleware) redirectToLogin(w http.ResponseWriter, r *http.Request) {
+	state, err := randomState()
+	if err != nil {
+		m.errorHandler(w, r, err)
+		return
+	}
+
+	authURL, err := m.client.Auth.AuthorizeURL(&feishu.AuthorizeURLOptions{
+		RedirectURI: m.callbackURL(r),
+		State:       state,
+	})
+	if err != nil {
+		m.errorHandler(w, r, err)
+		return
+	}
+
+	// state 和登录前的地址一起保存在 cookie，回调时校验，防止 CSRF
+	returnTo := base64.RawURLEncoding.EncodeToString([]byte(r.URL.RequestURI()))
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.stateCookie,
+		Value:    state + "." + returnTo,
+		Path:     "/",
+		Expires:  time.Now().Add(stateMaxAge),
+		Secure:   m.secureCookie,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (m *Middleware) handleCallback(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(m.stateCookie)
+	if err != nil {
+		m.errorHandler(w, r, errors.New("sso: missing state cookie"))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: m.stateCookie, Value: "", Path: "/", MaxAge: -1})
+
+	parts := strings.SplitN(cookie.Value, ".", 2)
+	state := r.URL.Query().Get("state")
+	if len(parts) != 2 || len(state) == 0 || parts[0] != state {
+		m.errorHandler(w, r, errors.New("sso: state mismatch"))
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if len(code) == 0 {
+		m.errorHandler(w, r, errors.New("sso: missing code"))
+		return
+	}
+
+	t, _, err := m.client.Auth.GetUserAccessToken(code, feishu.WithContext(r.Context()))
+	if err != nil {
+		m.errorHandler(w, r, err)
+		return
+	}
+	if t.Code != 0 {
+		m.errorHandler(w, r, errors.Errorf("sso: get user access token failed: %d %s", t.Code, t.Message))
+		return
+	}
+
+	if m.saveUserToken && m.client.UserAccessTokens != nil {
+		if err = m.client.UserAccessTokens.Set(t.Data.OpenId, &t.Data); err != nil {
+			m.errorHandler(w, r, err)
+			return
+		}
+	}
+
+	u := &User{
+		OpenId:    t.Data.OpenId,
+		UnionId:   t.Data.UnionId,
+		UserId:    t.Data.UserId,
+		Name:      t.Data.Name,
+		Email:     t.Data.Email,
+		TenantKey: t.Data.TenantKey,
+	}
+	if len(u.Email) == 0 {
+		u.Email = t.Data.EnterpriseEmail
+	}
+	if err = m.store.Save(w, r, u); err != nil {
+		m.errorHandler(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, safeReturnTo(parts[1]), http.StatusFound)
+}
+
+// safeReturnTo 只允许跳转回本站的相对路径。
+func safeReturnTo(encoded string) string {
+	buf, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "/"
+	}
+	returnTo := string(buf)
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") || strings.HasPrefix(returnTo, "/\\") {
+		return "/"
+	}
+	return returnTo
+}
+
+func randomState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
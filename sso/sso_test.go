@@ -0,0 +1,119 @@
+package sso
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	feishu "github.com/eyotang/go-feishu"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func setup(t *testing.T) (*http.ServeMux, *httptest.Server, *feishu.Client) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	client, err := feishu.NewLocalCacheClient("cli_sso_test", "secret", feishu.WithBaseURL(server.URL))
+	if err != nil {
+		server.Close()
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	mux.HandleFunc("/open-apis/auth/v3/app_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code": 0, "msg": "ok", "app_access_token": "a-test", "expire": 7200}`)
+	})
+	mux.HandleFunc("/open-apis/authen/v1/access_token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"code": 0,
+			"msg": "success",
+			"data": {
+				"access_token": "u-test",
+				"expires_in": 7140,
+				"name": "zhangsan",
+				"open_id": "ou_zhangsan",
+				"union_id": "on_zhangsan",
+				"email": "zhangsan@a.com",
+				"refresh_expires_in": 2591940,
+				"refresh_token": "ur-test"
+			}
+		}`)
+	})
+	return mux, server, client
+}
+
+func TestMiddleware_Handler(t *testing.T) {
+	Convey("test Middleware_Handler", t, func() {
+		_, server, client := setup(t)
+		defer server.Close()
+
+		store, err := NewCookieStore([]byte("signing-key"))
+		So(err, ShouldBeNil)
+		m := New(client, store, WithUserAccessTokenSaved())
+
+		handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			u, ok := UserFromContext(r.Context())
+			if !ok {
+				t.Errorf("user not found in context")
+				return
+			}
+			fmt.Fprint(w, u.Name)
+		}))
+
+		// 未登录，重定向到飞书登录
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://app.local/reports?id=1", nil))
+		So(w.Code, ShouldEqual, http.StatusFound)
+		loc, err := url.Parse(w.Header().Get("Location"))
+		So(err, ShouldBeNil)
+		So(loc.Path, ShouldEqual, "/open-apis/authen/v1/index")
+		So(loc.Query().Get("redirect_uri"), ShouldEqual, "http://app.local/feishu/callback")
+		state := loc.Query().Get("state")
+		So(state, ShouldNotBeEmpty)
+		stateCookie := w.Result().Cookies()[0]
+
+		// state 不匹配
+		w = httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "http://app.local/feishu/callback?code=abc&state=forged", nil)
+		r.AddCookie(stateCookie)
+		handler.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnauthorized)
+
+		// 回调，保存会话后跳转回原地址
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodGet, "http://app.local/feishu/callback?code=abc&state="+state, nil)
+		r.AddCookie(stateCookie)
+		handler.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusFound)
+		So(w.Header().Get("Location"), ShouldEqual, "/reports?id=1")
+
+		var session *http.Cookie
+		for _, c := range w.Result().Cookies() {
+			if c.Name == defaultSessionCookie {
+				session = c
+			}
+		}
+		So(session, ShouldNotBeNil)
+
+		token, err := client.UserAccessTokens.GetUserAccessToken("ou_zhangsan")
+		So(err, ShouldBeNil)
+		So(token, ShouldEqual, "u-test")
+
+		// 已登录
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodGet, "http://app.local/reports?id=1", nil)
+		r.AddCookie(session)
+		handler.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Body.String(), ShouldEqual, "zhangsan")
+
+		// 篡改的会话
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodGet, "http://app.local/reports", nil)
+		session.Value = "e30." + session.Value[len(session.Value)-10:]
+		r.AddCookie(session)
+		handler.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusFound)
+	})
+}
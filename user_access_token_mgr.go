@@ -0,0 +1,147 @@
+package feishu
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+)
+
+const (
+	_userAccessToken = "user_access_token"
+
+	// 过期前提前刷新 user_access_token
+	userAccessTokenRefreshAhead = 5 * time.Minute
+)
+
+var (
+	ErrUserAccessTokenNotFound = errors.New("user access token not found, login first")
+	ErrUserAccessTokenExpired  = errors.New("user access token and refresh token expired, login again")
+)
+
+// UserAccessTokenStore 基于 TokenCache 按用户保存 user_access_token，过期前自动用 refresh_token 刷新。
+type UserAccessTokenStore struct {
+	client *Client
+	appId  string
+	Cache  TokenCache
+
+	// 刷新时会请求 app_access_token，不能和 token manager 共用 Cache 的锁
+	mu sync.Mutex
+}
+
+type userAccessTokenEntry struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresAt        int64  `json:"expires_at"`
+	RefreshExpiresAt int64  `json:"refresh_expires_at"`
+}
+
+func NewUserAccessTokenStore(client *Client, appId string, cache TokenCache) *UserAccessTokenStore {
+	return &UserAccessTokenStore{
+		client: client,
+		appId:  appId,
+		Cache:  cache,
+	}
+}
+
+func (s *UserAccessTokenStore) TokenKey(userID string) string {
+	return _userAccessToken + ":" + s.appId + ":" + userID
+}
+
+// Set 保存 GetUserAccessToken/RefreshUserAccessToken 返回的 token，userID 由调用者决定，一般使用 open_id。
+func (s *UserAccessTokenStore) Set(userID string, t *UserAccessTokenData) error {
+	now := time.Now()
+	entry := &userAccessTokenEntry{
+		AccessToken:      t.AccessToken,
+		RefreshToken:     t.RefreshToken,
+		ExpiresAt:        now.Add(time.Duration(t.ExpiresIn) * time.Second).Unix(),
+		RefreshExpiresAt: now.Add(time.Duration(t.RefreshExpiresIn) * time.Second).Unix(),
+	}
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// refresh_token 有效期内都保留，以便刷新
+	expire := time.Duration(t.RefreshExpiresIn) * time.Second
+	if t.RefreshExpiresIn < t.ExpiresIn {
+		expire = time.Duration(t.ExpiresIn) * time.Second
+	}
+	if expire < 1 {
+		expire = 1
+	}
+	s.Cache.Set(s.TokenKey(userID), string(buf), expire)
+	return nil
+}
+
+func (s *UserAccessTokenStore) get(userID string) (*userAccessTokenEntry, bool) {
+	value, ok := s.Cache.Get(s.TokenKey(userID))
+	if !ok {
+		return nil, false
+	}
+	str, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	entry := new(userAccessTokenEntry)
+	if err := json.Unmarshal([]byte(str), entry); err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+// GetUserAccessToken 返回用户的 user_access_token，快过期时自动刷新。
+func (s *UserAccessTokenStore) GetUserAccessToken(userID string) (string, error) {
+	// 未过期，直接使用
+	if entry, ok := s.get(userID); ok && time.Now().Add(userAccessTokenRefreshAhead).Unix() < entry.ExpiresAt {
+		return entry.AccessToken, nil
+	}
+
+	// 上锁
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 其他协程刷新并保存了，直接使用
+	entry, ok := s.get(userID)
+	if !ok {
+		return "", ErrUserAccessTokenNotFound
+	}
+	now := time.Now()
+	if now.Add(userAccessTokenRefreshAhead).Unix() < entry.ExpiresAt {
+		return entry.AccessToken, nil
+	}
+	if now.Unix() >= entry.RefreshExpiresAt {
+		if now.Unix() < entry.ExpiresAt {
+			return entry.AccessToken, nil
+		}
+		return "", ErrUserAccessTokenExpired
+	}
+
+	t, _, err := s.client.Auth.RefreshUserAccessToken(entry.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	if t.Code != 0 {
+		return "", errors.Errorf("refresh user access token failed: %d %s", t.Code, t.Message)
+	}
+	if err = s.Set(userID, &t.Data); err != nil {
+		return "", err
+	}
+	return t.Data.AccessToken, nil
+}
+
+// WithUserAccessToken 使用 UserAccessTokens 中保存的用户 token 发起这次请求。
+func (c *Client) WithUserAccessToken(userID string) RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		if c.UserAccessTokens == nil {
+			return errors.New("NewClient with token manager first! WithTenantAccessTokenInternal(appId, appSecret)")
+		}
+		token, err := c.UserAccessTokens.GetUserAccessToken(userID)
+		if err != nil {
+			return err
+		}
+		return WithToken(token)(req)
+	}
+}
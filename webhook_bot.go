@@ -0,0 +1,321 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	webhookPathPrefix = "bot/v2/hook/"
+
+	MsgTypeText        = "text"
+	MsgTypePost        = "post"
+	MsgTypeImage       = "image"
+	MsgTypeShareChat   = "share_chat"
+	MsgTypeInteractive = "interactive"
+)
+
+// 自定义机器人返回的错误码
+const (
+	WebhookCodeInvalidToken     = 19001
+	WebhookCodeSignMismatch     = 19021
+	WebhookCodeIPNotAllowed     = 19022
+	WebhookCodeKeywordsNotFound = 19024
+	WebhookCodeRateLimited      = 9499
+	WebhookCodeFrequencyLimited = 11232
+)
+
+var ErrWebhookKeywordMissing = errors.New("webhook message does not contain any keyword")
+
+// WebhookError 自定义机器人返回的错误，兼容 code/msg 和 StatusCode/StatusMessage 两种格式。
+type WebhookError struct {
+	Code     int
+	Message  string
+	Response *http.Response
+}
+
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("webhook bot: %d %s", e.Code, e.Message)
+}
+
+func IsWebhookSignError(err error) bool {
+	var e *WebhookError
+	return errors.As(err, &e) && e.Code == WebhookCodeSignMismatch
+}
+
+func IsWebhookKeywordError(err error) bool {
+	var e *WebhookError
+	return errors.Is(err, ErrWebhookKeywordMissing) || (errors.As(err, &e) && e.Code == WebhookCodeKeywordsNotFound)
+}
+
+func IsWebhookRateLimited(err error) bool {
+	var e *WebhookError
+	return errors.As(err, &e) && (e.Code == WebhookCodeRateLimited || e.Code == WebhookCodeFrequencyLimited ||
+		(e.Response != nil && e.Response.StatusCode == http.StatusTooManyRequests))
+}
+
+// WebhookBot 群自定义机器人。
+type WebhookBot struct {
+	client *Client
+
+	Key      string
+	secret   CredentialProvider
+	Keywords []string
+}
+
+// WebhookBotOptionFunc can be used to customize a WebhookBot.
+type WebhookBotOptionFunc func(*WebhookBot)
+
+// WithWebhookSecret 开启签名校验时配置的 secret。
+func WithWebhookSecret(secret string) WebhookBotOptionFunc {
+	return func(b *WebhookBot) {
+		if len(secret) > 0 {
+			b.secret = StaticCredential(secret)
+		}
+	}
+}
+
+// WithWebhookSecretProvider 每次发送时从 provider 获取 secret。
+func WithWebhookSecretProvider(provider CredentialProvider) WebhookBotOptionFunc {
+	return func(b *WebhookBot) {
+		b.secret = provider
+	}
+}
+
+// WithWebhookKeywords 开启自定义关键词时配置的关键词，发送前检查消息至少包含其中一个。
+func WithWebhookKeywords(keywords ...string) WebhookBotOptionFunc {
+	return func(b *WebhookBot) {
+		b.Keywords = keywords
+	}
+}
+
+// NewWebhookBot 使用完整的 webhook 地址或者其中的 key 创建机器人，请求发往 Client 的 baseURL。
+func (s *BotService) NewWebhookBot(webhook string, options ...WebhookBotOptionFunc) (*WebhookBot, error) {
+	key := webhook
+	if i := strings.Index(webhook, webhookPathPrefix); i >= 0 {
+		key = webhook[i+len(webhookPathPrefix):]
+	}
+	key = strings.Trim(key, "/")
+	if len(key) == 0 || strings.ContainsAny(key, "/?#") {
+		return nil, errors.Errorf("invalid webhook: %s", webhook)
+	}
+
+	b := &WebhookBot{client: s.client, Key: key}
+	for _, fn := range options {
+		if fn == nil {
+			continue
+		}
+		fn(b)
+	}
+	return b, nil
+}
+
+type webhookMessage struct {
+	Timestamp string      `json:"timestamp,omitempty"`
+	Sign      string      `json:"sign,omitempty"`
+	MsgType   string      `json:"msg_type"`
+	Content   interface{} `json:"content,omitempty"`
+	Card      interface{} `json:"card,omitempty"`
+}
+
+type webhookResponse struct {
+	CodeMsg
+	StatusCode    int    `json:"StatusCode"`
+	StatusMessage string `json:"StatusMessage"`
+}
+
+func (r *webhookResponse) code() (int, string) {
+	if r.Code != 0 {
+		return r.Code, r.Message
+	}
+	if r.StatusCode != 0 {
+		return r.StatusCode, r.StatusMessage
+	}
+	if len(r.Message) > 0 {
+		return 0, r.Message
+	}
+	return 0, r.StatusMessage
+}
+
+type TextContent struct {
+	Text string `json:"text"`
+}
+
+// PostElement 富文本元素，Tag 为 text、a、at、img。
+type PostElement struct {
+	Tag      string `json:"tag"`
+	Text     string `json:"text,omitempty"`
+	UnEscape bool   `json:"un_escape,omitempty"`
+	Href     string `json:"href,omitempty"`
+	UserId   string `json:"user_id,omitempty"`
+	UserName string `json:"user_name,omitempty"`
+	ImageKey string `json:"image_key,omitempty"`
+}
+
+type Post struct {
+	Title   string          `json:"title"`
+	Content [][]PostElement `json:"content"`
+}
+
+// PostContent 富文本消息内容，key 为语言，如 zh_cn、en_us。
+type PostContent struct {
+	Post map[string]*Post `json:"post"`
+}
+
+type ImageContent struct {
+	ImageKey string `json:"image_key"`
+}
+
+type ShareChatContent struct {
+	ShareChatId string `json:"share_chat_id"`
+}
+
+func (b *WebhookBot) SendText(text string, options ...RequestOptionFunc) (*BotResponse, *Response, error) {
+	return b.send(&webhookMessage{MsgType: MsgTypeText, Content: &TextContent{Text: text}}, options)
+}
+
+// SendPost 发送富文本消息，默认使用 zh_cn。
+func (b *WebhookBot) SendPost(post *Post, options ...RequestOptionFunc) (*BotResponse, *Response, error) {
+	return b.SendPostContent(&PostContent{Post: map[string]*Post{"zh_cn": post}}, options...)
+}
+
+func (b *WebhookBot) SendPostContent(content *PostContent, options ...RequestOptionFunc) (*BotResponse, *Response, error) {
+	return b.send(&webhookMessage{MsgType: MsgTypePost, Content: content}, options)
+}
+
+func (b *WebhookBot) SendImage(imageKey string, options ...RequestOptionFunc) (*BotResponse, *Response, error) {
+	return b.send(&webhookMessage{MsgType: MsgTypeImage, Content: &ImageContent{ImageKey: imageKey}}, options)
+}
+
+func (b *WebhookBot) SendShareChat(chatId string, options ...RequestOptionFunc) (*BotResponse, *Response, error) {
+	return b.send(&webhookMessage{MsgType: MsgTypeShareChat, Content: &ShareChatContent{ShareChatId: chatId}}, options)
+}
+
+// SendCard 发送消息卡片，card 可以是 BotCardOption、AppCardOption 或者 json object。
+func (b *WebhookBot) SendCard(card interface{}, options ...RequestOptionFunc) (*BotResponse, *Response, error) {
+	return b.send(&webhookMessage{MsgType: MsgTypeInteractive, Card: card}, options)
+}
+
+// hasKeyword 消息中的文本包含任意一个关键词即可，未配置关键词时不检查。
+func (b *WebhookBot) hasKeyword(msg *webhookMessage) (bool, error) {
+	return HasWebhookKeyword([]interface{}{msg.Content, msg.Card}, b.Keywords)
+}
+
+// HasWebhookKeyword 检查消息内容或卡片展示的文本是否包含任意一个关键词，keywords 为空时返回 true。
+// 只匹配 text、title、content 等展示文本，不匹配字段名和 tag、href 等字段的值。
+func HasWebhookKeyword(content interface{}, keywords []string) (bool, error) {
+	if len(keywords) == 0 {
+		return true, nil
+	}
+	buf, err := json.Marshal(content)
+	if err != nil {
+		return false, err
+	}
+	var v interface{}
+	if err = json.Unmarshal(buf, &v); err != nil {
+		return false, err
+	}
+	var texts []string
+	collectWebhookTexts(v, false, &texts)
+	for _, keyword := range keywords {
+		for _, text := range texts {
+			if len(keyword) > 0 && strings.Contains(text, keyword) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// 值为展示文本的字段，如文本消息的 text、富文本的 title、卡片元素的 content
+var webhookTextKeys = map[string]bool{"text": true, "title": true, "content": true}
+
+// collectWebhookTexts 收集消息中展示的文本，不包括字段名和 tag、href 等字段的值，
+// 服务端模板卡片收集所有变量的值。
+func collectWebhookTexts(v interface{}, visible bool, texts *[]string) {
+	switch v := v.(type) {
+	case string:
+		if visible {
+			*texts = append(*texts, v)
+		}
+	case []interface{}:
+		for _, child := range v {
+			collectWebhookTexts(child, visible, texts)
+		}
+	case map[string]interface{}:
+		for k, child := range v {
+			if k == "template_variable" {
+				collectWebhookValues(child, texts)
+				continue
+			}
+			collectWebhookTexts(child, webhookTextKeys[k], texts)
+		}
+	}
+}
+
+func collectWebhookValues(v interface{}, texts *[]string) {
+	switch v := v.(type) {
+	case string:
+		*texts = append(*texts, v)
+	case []interface{}:
+		for _, child := range v {
+			collectWebhookValues(child, texts)
+		}
+	case map[string]interface{}:
+		for _, child := range v {
+			collectWebhookValues(child, texts)
+		}
+	}
+}
+
+func (b *WebhookBot) send(msg *webhookMessage, options []RequestOptionFunc) (*BotResponse, *Response, error) {
+	if ok, err := b.hasKeyword(msg); err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, ErrWebhookKeywordMissing
+	}
+
+	secret := ""
+	if b.secret != nil {
+		var err error
+		if secret, err = b.secret.Secret(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if timestamp, sign, err := b.client.Bot.sign(b.Key, secret); err != nil {
+		return nil, nil, err
+	} else {
+		msg.Timestamp, msg.Sign = timestamp, sign
+	}
+
+	req, err := b.client.NewRequest(http.MethodPost, webhookPathPrefix+b.Key, msg, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(webhookResponse)
+	resp, err := b.client.Do(req, c)
+	if err != nil {
+		// 非 2xx 时尝试解析出机器人的错误码
+		var errResp *ErrorResponse
+		if errors.As(err, &errResp) && json.Unmarshal(errResp.Body, c) == nil {
+			if code, message := c.code(); code != 0 {
+				return nil, resp, &WebhookError{Code: code, Message: message, Response: resp.Response}
+			}
+		}
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			return nil, resp, &WebhookError{Code: WebhookCodeRateLimited, Message: http.StatusText(resp.StatusCode), Response: resp.Response}
+		}
+		return nil, resp, err
+	}
+
+	code, message := c.code()
+	if code != 0 {
+		return nil, resp, &WebhookError{Code: code, Message: message, Response: resp.Response}
+	}
+	return &BotResponse{StatusCode: code, StatusMessage: message}, resp, nil
+}
@@ -0,0 +1,130 @@
+package feishu
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBotService_NewWebhookBot(t *testing.T) {
+	Convey("test BotService_NewWebhookBot", t, func() {
+		client, _ := NewClient()
+
+		bot, err := client.Bot.NewWebhookBot("https://open.feishu.cn/open-apis/bot/v2/hook/891105b7-1234-4567-7890-c4c372235090")
+		So(err, ShouldBeNil)
+		So(bot.Key, ShouldEqual, "891105b7-1234-4567-7890-c4c372235090")
+
+		bot, err = client.Bot.NewWebhookBot("891105b7-1234-4567-7890-c4c372235090")
+		So(err, ShouldBeNil)
+		So(bot.Key, ShouldEqual, "891105b7-1234-4567-7890-c4c372235090")
+
+		_, err = client.Bot.NewWebhookBot("https://open.feishu.cn/open-apis/bot/v2/hook/")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestWebhookBot_SendText(t *testing.T) {
+	Convey("test WebhookBot_SendText", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/open-apis/bot/v2/hook/891105b7-1234-4567-7890-c4c372235090", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"msg_type":"text","content":{"text":"[alert] disk full"}}`)
+			fmt.Fprint(w, `{
+				"StatusCode": 0,
+				"StatusMessage": "success"
+			}`)
+		})
+
+		bot, err := client.Bot.NewWebhookBot("891105b7-1234-4567-7890-c4c372235090", WithWebhookKeywords("[alert]"))
+		So(err, ShouldBeNil)
+
+		rsp, _, err := bot.SendText("[alert] disk full")
+		So(err, ShouldBeNil)
+		So(rsp, ShouldResemble, &BotResponse{StatusCode: 0, StatusMessage: "success"})
+
+		_, _, err = bot.SendText("disk full")
+		So(err, ShouldEqual, ErrWebhookKeywordMissing)
+		So(IsWebhookKeywordError(err), ShouldBeTrue)
+
+		// 只匹配展示的文本，不匹配字段名和 tag、href 的值
+		bot, _ = client.Bot.NewWebhookBot("891105b7-1234-4567-7890-c4c372235090", WithWebhookKeywords("text", "content", "tag", "example"))
+		_, _, err = bot.SendText("disk full")
+		So(err, ShouldEqual, ErrWebhookKeywordMissing)
+		_, _, err = bot.SendPost(&Post{Title: "deploy", Content: [][]PostElement{{
+			{Tag: "text", Text: "done "},
+			{Tag: "a", Text: "detail", Href: "https://example.com"},
+		}}})
+		So(err, ShouldEqual, ErrWebhookKeywordMissing)
+		_, _, err = bot.SendCard(map[string]interface{}{
+			"header":   map[string]interface{}{"title": map[string]interface{}{"tag": "plain_text", "content": "deploy"}},
+			"elements": []interface{}{map[string]interface{}{"tag": "markdown", "content": "done"}},
+		})
+		So(err, ShouldEqual, ErrWebhookKeywordMissing)
+
+		bot.Keywords = []string{"[alert]"}
+		ok, err := bot.hasKeyword(&webhookMessage{MsgType: MsgTypeInteractive, Card: map[string]interface{}{
+			"elements": []interface{}{map[string]interface{}{"tag": "div", "text": map[string]interface{}{"tag": "lark_md", "content": "**[alert]** disk full"}}},
+		}})
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeTrue)
+		ok, err = bot.hasKeyword(&webhookMessage{MsgType: MsgTypePost, Content: &PostContent{Post: map[string]*Post{"zh_cn": {Title: "[alert] deploy"}}}})
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeTrue)
+	})
+}
+
+func TestWebhookBot_SendPost(t *testing.T) {
+	Convey("test WebhookBot_SendPost", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/open-apis/bot/v2/hook/891105b7-1234-4567-7890-c4c372235090", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, `{"msg_type":"post","content":{"post":{"zh_cn":{"title":"deploy","content":[[{"tag":"text","text":"done "},{"tag":"a","text":"detail","href":"https://example.com"}]]}}}}`)
+			fmt.Fprint(w, `{
+				"code": 0,
+				"msg": "success",
+				"data": {}
+			}`)
+		})
+
+		bot, _ := client.Bot.NewWebhookBot("891105b7-1234-4567-7890-c4c372235090")
+		rsp, _, err := bot.SendPost(&Post{
+			Title: "deploy",
+			Content: [][]PostElement{{
+				{Tag: "text", Text: "done "},
+				{Tag: "a", Text: "detail", Href: "https://example.com"},
+			}},
+		})
+		So(err, ShouldBeNil)
+		So(rsp.StatusMessage, ShouldEqual, "success")
+	})
+}
+
+func TestWebhookBot_SendCardError(t *testing.T) {
+	Convey("test WebhookBot_SendCardError", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/open-apis/bot/v2/hook/891105b7-1234-4567-7890-c4c372235090", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			fmt.Fprint(w, `{
+				"code": 19021,
+				"msg": "sign match fail or timestamp is not within one hour from current time",
+				"data": {}
+			}`)
+		})
+
+		bot, _ := client.Bot.NewWebhookBot("891105b7-1234-4567-7890-c4c372235090", WithWebhookSecret("wrong"))
+		_, _, err := bot.SendCard(&BotCardOption{
+			Header: HeadOption{Title: TitleOption{Tag: "plain_text", Content: "test report"}},
+		})
+		So(err, ShouldNotBeNil)
+		So(IsWebhookSignError(err), ShouldBeTrue)
+		So(err.(*WebhookError).Code, ShouldEqual, WebhookCodeSignMismatch)
+	})
+}